@@ -0,0 +1,180 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Theme names
+const (
+	ThemeDark  = "dark"
+	ThemeLight = "light"
+)
+
+// hexColor matches colors given as #rgb or #rrggbb
+var hexColor = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// Config is the user configuration read from config.yml
+type Config struct {
+	Keys     Keys     `yaml:"keys"`
+	Theme    Theme    `yaml:"theme"`
+	List     List     `yaml:"list"`
+	Details  Details  `yaml:"details"`
+	Defaults Defaults `yaml:"defaults"`
+}
+
+// Keys maps the actions of each screen to the keys bound to them, such as keys["list"]["quit"]
+type Keys map[string]map[string][]string
+
+// Theme is a named theme, with some of its colors overridden
+type Theme struct {
+	Name   string            `yaml:"name"`
+	Colors map[string]string `yaml:"colors"` // ANSI 256 color numbers or #rrggbb, keyed by role
+}
+
+// UnmarshalYAML reads a theme given by its name only, or with its colors
+func (t *Theme) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&t.Name)
+	}
+	type plain Theme
+	return value.Decode((*plain)(t))
+}
+
+// List configures the package list
+type List struct {
+	Description []string `yaml:"description"` // Fields shown below each package, in order
+}
+
+// Details configures the details view
+type Details struct {
+	Fields []string `yaml:"fields"` // Fields shown in the details view, in order
+}
+
+// Defaults are the default values of the command line flags
+type Defaults struct {
+	All          *bool          `yaml:"all"`
+	Offline      *bool          `yaml:"offline"`
+	CacheTTL     *time.Duration `yaml:"cache_ttl"`
+	StarCacheTTL *time.Duration `yaml:"star_cache_ttl"`
+}
+
+// FieldError is an invalid entry of the config file
+type FieldError struct {
+	Field   string // Dotted path of the entry, such as theme.colors.accent
+	Message string
+}
+
+// Error returns the entry and why it's invalid
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// DefaultPath returns the path of the config file in the user config directory
+// ($XDG_CONFIG_HOME, or ~/.config)
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "gh-lsmod", "config.yml"), nil
+}
+
+// Load reads and validates the config file. A missing file means everything is left to its default.
+func Load(path string) (*Config, error) {
+	config := &Config{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	// Unknown entries are rejected, so that typos don't go unnoticed
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s:\n%w", path, err)
+	}
+	return config, nil
+}
+
+// Validate checks the entries that don't depend on the screens, every invalid entry being reported.
+// The names of actions, colors and fields are checked by the UI.
+func (c *Config) Validate() error {
+	var errs []error
+
+	for _, screen := range sortedKeys(c.Keys) {
+		for _, action := range sortedKeys(c.Keys[screen]) {
+			field := "keys." + screen + "." + action
+			keys := c.Keys[screen][action]
+			if len(keys) == 0 {
+				errs = append(errs, &FieldError{Field: field, Message: "no keys given"})
+			}
+			for _, k := range keys {
+				if strings.TrimSpace(k) == "" {
+					errs = append(errs, &FieldError{Field: field, Message: "empty key"})
+				}
+			}
+		}
+	}
+
+	switch c.Theme.Name {
+	case "", ThemeDark, ThemeLight:
+	default:
+		errs = append(errs, &FieldError{Field: "theme.name", Message: fmt.Sprintf("unknown theme %q, expected %s or %s", c.Theme.Name, ThemeDark, ThemeLight)})
+	}
+	for _, role := range sortedKeys(c.Theme.Colors) {
+		if !ValidColor(c.Theme.Colors[role]) {
+			errs = append(errs, &FieldError{Field: "theme.colors." + role, Message: fmt.Sprintf("invalid color %q, expected an ANSI color number from 0 to 255 or #rrggbb", c.Theme.Colors[role])})
+		}
+	}
+
+	if ttl := c.Defaults.CacheTTL; ttl != nil && *ttl < 0 {
+		errs = append(errs, &FieldError{Field: "defaults.cache_ttl", Message: "must not be negative"})
+	}
+	if ttl := c.Defaults.StarCacheTTL; ttl != nil && *ttl < 0 {
+		errs = append(errs, &FieldError{Field: "defaults.star_cache_ttl", Message: "must not be negative"})
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidColor reports whether the color is an ANSI 256 color number or a #rgb or #rrggbb hex color
+func ValidColor(color string) bool {
+	if n, err := strconv.Atoi(color); err == nil {
+		return n >= 0 && n <= 255
+	}
+	return hexColor.MatchString(color)
+}
+
+// sortedKeys returns the keys of the map in alphabetical order, so that errors are reported in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/config")
+
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath() returned an error: %v", err)
+	}
+	if want := filepath.Join("/tmp/config", "gh-lsmod", "config.yml"); path != want {
+		t.Errorf("DefaultPath() = %s, want %s", path, want)
+	}
+}
+
+// writeConfig writes the config file and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	// A missing file leaves everything to its default
+	config, err := Load(filepath.Join(t.TempDir(), "config.yml"))
+	if err != nil {
+		t.Fatalf("Load() returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(config, &Config{}) {
+		t.Errorf("Load() = %+v, want an empty config", config)
+	}
+
+	config, err = Load(writeConfig(t, `
+keys:
+  list:
+    quit: [q]
+    open_github: [G, ctrl+g]
+theme:
+  name: light
+  colors:
+    accent: "#ff8800"
+    muted: "244"
+list:
+  description: [version, size]
+details:
+  fields: [name, version]
+defaults:
+  all: true
+  cache_ttl: 1h30m
+`))
+	if err != nil {
+		t.Fatalf("Load() returned an error: %v", err)
+	}
+	if want := (Keys{"list": {"quit": {"q"}, "open_github": {"G", "ctrl+g"}}}); !reflect.DeepEqual(config.Keys, want) {
+		t.Errorf("Keys = %v, want %v", config.Keys, want)
+	}
+	if want := (Theme{Name: ThemeLight, Colors: map[string]string{"accent": "#ff8800", "muted": "244"}}); !reflect.DeepEqual(config.Theme, want) {
+		t.Errorf("Theme = %+v, want %+v", config.Theme, want)
+	}
+	if want := []string{"version", "size"}; !reflect.DeepEqual(config.List.Description, want) {
+		t.Errorf("List.Description = %v, want %v", config.List.Description, want)
+	}
+	if want := []string{"name", "version"}; !reflect.DeepEqual(config.Details.Fields, want) {
+		t.Errorf("Details.Fields = %v, want %v", config.Details.Fields, want)
+	}
+	if config.Defaults.All == nil || !*config.Defaults.All {
+		t.Errorf("Defaults.All = %v, want true", config.Defaults.All)
+	}
+	if config.Defaults.Offline != nil {
+		t.Errorf("Defaults.Offline = %v, want unset", *config.Defaults.Offline)
+	}
+	if config.Defaults.CacheTTL == nil || *config.Defaults.CacheTTL != 90*time.Minute {
+		t.Errorf("Defaults.CacheTTL = %v, want 1h30m", config.Defaults.CacheTTL)
+	}
+
+	// A theme can be given by its name only
+	config, err = Load(writeConfig(t, "theme: dark\n"))
+	if err != nil {
+		t.Fatalf("Load() returned an error: %v", err)
+	}
+	if config.Theme.Name != ThemeDark {
+		t.Errorf("Theme.Name = %q, want %q", config.Theme.Name, ThemeDark)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			name:     "Unknown entry",
+			content:  "thme: light\n",
+			expected: []string{"field thme not found"},
+		},
+		{
+			name:     "Invalid duration",
+			content:  "defaults:\n  cache_ttl: soon\n",
+			expected: []string{"failed to parse config", "soon"},
+		},
+		{
+			name: "Every invalid entry is reported",
+			content: `
+keys:
+  list:
+    quit: []
+    open_github: [""]
+theme:
+  name: solarized
+  colors:
+    accent: "256"
+    muted: "#12345"
+defaults:
+  star_cache_ttl: -1m
+`,
+			expected: []string{
+				"keys.list.open_github: empty key",
+				"keys.list.quit: no keys given",
+				`theme.name: unknown theme "solarized", expected dark or light`,
+				`theme.colors.accent: invalid color "256"`,
+				`theme.colors.muted: invalid color "#12345"`,
+				"defaults.star_cache_ttl: must not be negative",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.content))
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("Expected the error to contain %q, got:\n%v", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidColor(t *testing.T) {
+	for color, valid := range map[string]bool{
+		"0": true, "255": true, "256": false, "-1": false,
+		"#fff": true, "#FF8800": true, "#ff880": false, "red": false, "": false,
+	} {
+		if got := ValidColor(color); got != valid {
+			t.Errorf("ValidColor(%q) = %v, want %v", color, got, valid)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package deptree
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/tnagatomi/gh-lsmod/modcache"
+	"github.com/tnagatomi/gh-lsmod/model"
+	"golang.org/x/mod/modfile"
+)
+
+// readGoMod reads the go.mod file of a module version, from the module cache by default
+var readGoMod = modcache.ReadGoMod
+
+// Node is a module version in the dependency tree
+type Node struct {
+	Package   *model.Package
+	Parent    *Node
+	Children  []*Node
+	Expanded  bool
+	Duplicate bool   // The module version is already in the tree, and its requirements are shown there
+	Selected  string // Version the main module selects, when it differs from the required one
+	Err       error  // Why the requirements couldn't be read
+	loaded    bool
+}
+
+// Depth returns the number of ancestors of the node
+func (n *Node) Depth() int {
+	depth := 0
+	for p := n.Parent; p != nil; p = p.Parent {
+		depth++
+	}
+	return depth
+}
+
+// Conflict reports whether the main module selects another version than the one required
+func (n *Node) Conflict() bool {
+	return n.Selected != ""
+}
+
+// Expandable reports whether the node may have requirements to show.
+// Requirements are only read when the node is first expanded.
+func (n *Node) Expandable() bool {
+	if n.Duplicate {
+		return false
+	}
+	return !n.loaded || len(n.Children) > 0
+}
+
+// Tree is the dependency tree of the main module, with its requirements at the top level.
+// The requirements of each module are read from its go.mod file in the module cache when it's expanded.
+type Tree struct {
+	Roots    []*Node
+	selected map[string]string         // Version of every module in the main module's go.mod
+	known    map[string]*model.Package // Packages of the main module's go.mod by path, to share their metadata
+	shown    map[string]*Node          // First node of every module version in the tree
+}
+
+// New creates a tree with the packages at the top level.
+// requirements are every module in the main module's go.mod, direct and indirect, and are used to
+// point out modules whose required version differs from the selected one.
+func New(packages, requirements []*model.Package) *Tree {
+	t := &Tree{
+		selected: make(map[string]string),
+		known:    make(map[string]*model.Package),
+		shown:    make(map[string]*Node),
+	}
+	for _, pkg := range requirements {
+		t.selected[pkg.Path] = pkg.Version
+		t.known[pkg.Path] = pkg
+	}
+	for _, pkg := range packages {
+		if _, ok := t.selected[pkg.Path]; !ok {
+			t.selected[pkg.Path] = pkg.Version
+		}
+		t.known[pkg.Path] = pkg
+		t.Roots = append(t.Roots, t.newNode(pkg, nil))
+	}
+	return t
+}
+
+// newNode creates a node for the package, marking it if the module version is already in the tree
+func (t *Tree) newNode(pkg *model.Package, parent *Node) *Node {
+	node := &Node{Package: pkg, Parent: parent}
+	if selected := t.selected[pkg.Path]; selected != "" && selected != pkg.Version {
+		node.Selected = selected
+	}
+
+	id := pkg.Path + "@" + pkg.Version
+	if _, ok := t.shown[id]; ok {
+		node.Duplicate = true
+	} else {
+		t.shown[id] = node
+	}
+	return node
+}
+
+// Original returns the node showing the requirements of a duplicate node's module version
+func (t *Tree) Original(n *Node) *Node {
+	return t.shown[n.Package.Path+"@"+n.Package.Version]
+}
+
+// Expand shows the requirements of the node, reading them on first use.
+// The error is also kept in the node, to be shown in place of its requirements.
+func (t *Tree) Expand(n *Node) error {
+	if n.Duplicate {
+		return nil
+	}
+	if !n.loaded {
+		n.loaded = true
+		n.Err = t.load(n)
+	}
+	n.Expanded = n.Err == nil && len(n.Children) > 0
+	return n.Err
+}
+
+// load reads the requirements of the node's module version from its go.mod file
+func (t *Tree) load(n *Node) error {
+	pkg := n.Package
+	data, err := readGoMod(pkg.Path, pkg.Version)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("go.mod of %s@%s isn't in the module cache, run go mod download", pkg.Path, pkg.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read go.mod of %s@%s: %w", pkg.Path, pkg.Version, err)
+	}
+
+	file, err := modfile.ParseLax(pkg.Path+"@"+pkg.Version+"/go.mod", data, nil)
+	if err != nil {
+		return fmt.Errorf("failed to parse go.mod of %s@%s: %w", pkg.Path, pkg.Version, err)
+	}
+
+	for _, req := range file.Require {
+		n.Children = append(n.Children, t.newNode(t.packageFor(req), n))
+	}
+	return nil
+}
+
+// packageFor returns the package of a requirement. The main module's own package is used if the
+// versions match, so that changes such as starring show up everywhere; otherwise its metadata is shared.
+func (t *Tree) packageFor(req *modfile.Require) *model.Package {
+	known := t.known[req.Mod.Path]
+	if known != nil && known.Version == req.Mod.Version {
+		return known
+	}
+
+	pkg := model.NewPackage(req.Mod.Path, req.Mod.Version)
+	pkg.Indirect = req.Indirect
+	if known != nil {
+		pkg.IsStarred = known.IsStarred
+		pkg.IsWatched = known.IsWatched
+		pkg.Repo = known.Repo
+	}
+	return pkg
+}
+
+// Collapse hides the requirements of the node
+func (t *Tree) Collapse(n *Node) {
+	n.Expanded = false
+}
+
+// ExpandAll expands every node in the tree. Each module version is expanded once,
+// so the tree stays finite. It returns the number of modules whose requirements couldn't be read.
+func (t *Tree) ExpandAll() int {
+	failed := 0
+	var expand func(nodes []*Node)
+	expand = func(nodes []*Node) {
+		for _, n := range nodes {
+			if t.Expand(n) != nil {
+				failed++
+			}
+			expand(n.Children)
+		}
+	}
+	expand(t.Roots)
+	return failed
+}
+
+// Visible returns the nodes that aren't hidden by a collapsed ancestor, in the order they're shown
+func (t *Tree) Visible() []*Node {
+	var nodes []*Node
+	var walk func(children []*Node)
+	walk = func(children []*Node) {
+		for _, n := range children {
+			nodes = append(nodes, n)
+			if n.Expanded {
+				walk(n.Children)
+			}
+		}
+	}
+	walk(t.Roots)
+	return nodes
+}
@@ -0,0 +1,151 @@
+package deptree
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/tnagatomi/gh-lsmod/modcache"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// withGoMods serves the go.mod files from the map, keyed by path@version
+func withGoMods(t *testing.T, goMods map[string]string) {
+	t.Helper()
+	readGoMod = func(path, version string) ([]byte, error) {
+		data, ok := goMods[path+"@"+version]
+		if !ok {
+			return nil, fmt.Errorf("open %s@%s.mod: %w", path, version, fs.ErrNotExist)
+		}
+		return []byte(data), nil
+	}
+	t.Cleanup(func() {
+		readGoMod = modcache.ReadGoMod
+	})
+}
+
+// visiblePaths returns the visible nodes as indented path@version lines
+func visiblePaths(tree *Tree) []string {
+	var lines []string
+	for _, n := range tree.Visible() {
+		line := fmt.Sprintf("%*s%s@%s", n.Depth()*2, "", n.Package.Path, n.Package.Version)
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestTree(t *testing.T) {
+	withGoMods(t, map[string]string{
+		"example.com/a@v1.0.0": `module example.com/a
+
+require (
+	example.com/c v1.1.0
+	example.com/d v1.0.0 // indirect
+)
+`,
+		"example.com/b@v1.2.0": `module example.com/b
+
+require example.com/c v1.1.0
+`,
+		"example.com/c@v1.1.0": `module example.com/c
+
+require example.com/a v0.9.0
+`,
+	})
+
+	a := model.NewPackage("example.com/a", "v1.0.0")
+	a.IsStarred = true
+	b := model.NewPackage("example.com/b", "v1.2.0")
+	c := model.NewPackage("example.com/c", "v1.2.0")
+	tree := New([]*model.Package{a, b}, []*model.Package{a, b, c})
+
+	// Only the top level is shown until a node is expanded
+	if got := len(tree.Visible()); got != 2 {
+		t.Fatalf("Expected 2 visible nodes, got %d", got)
+	}
+	if !tree.Roots[0].Expandable() {
+		t.Error("Expected an unread node to be expandable")
+	}
+
+	if err := tree.Expand(tree.Roots[0]); err != nil {
+		t.Fatalf("Expand() returned an error: %v", err)
+	}
+	children := tree.Roots[0].Children
+	if len(children) != 2 {
+		t.Fatalf("Expected 2 requirements, got %d", len(children))
+	}
+
+	// The main module selects a newer version of c
+	if !children[0].Conflict() || children[0].Selected != "v1.2.0" {
+		t.Errorf("Expected a conflict with the selected version v1.2.0, got %q", children[0].Selected)
+	}
+	if !children[1].Package.Indirect || children[1].Conflict() {
+		t.Errorf("Expected d to be an indirect requirement without conflict, got %+v", children[1])
+	}
+
+	// d isn't in the module cache, so it has nothing to expand
+	if err := tree.Expand(children[1]); err == nil {
+		t.Error("Expected an error for a go.mod missing from the module cache")
+	}
+	if children[1].Err == nil || children[1].Expanded || children[1].Expandable() {
+		t.Errorf("Expected the error to be kept in a collapsed node, got %+v", children[1])
+	}
+
+	// c@v1.1.0 is already shown under a, so it's a duplicate under b.
+	// Neither d nor a@v0.9.0 are in the module cache.
+	if got := tree.ExpandAll(); got != 2 {
+		t.Errorf("ExpandAll() = %d modules that couldn't be read, want 2", got)
+	}
+	duplicate := tree.Roots[1].Children[0]
+	if !duplicate.Duplicate || duplicate.Expandable() || len(duplicate.Children) != 0 {
+		t.Errorf("Expected c under b to be a duplicate, got %+v", duplicate)
+	}
+	if tree.Original(duplicate) != children[0] {
+		t.Error("Expected the original to be c under a")
+	}
+
+	// a@v0.9.0 under c is another version than the top level one, but shares its metadata
+	older := children[0].Children[0]
+	if older.Duplicate || older.Package == a || !older.Package.IsStarred || older.Selected != "v1.0.0" {
+		t.Errorf("Expected a@v0.9.0 to be a conflicting copy sharing the star status, got %+v", older)
+	}
+
+	expected := []string{
+		"example.com/a@v1.0.0",
+		"  example.com/c@v1.1.0",
+		"    example.com/a@v0.9.0",
+		"  example.com/d@v1.0.0",
+		"example.com/b@v1.2.0",
+		"  example.com/c@v1.1.0",
+	}
+	got := visiblePaths(tree)
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Visible() = %q, want %q", got, expected)
+	}
+
+	tree.Collapse(tree.Roots[0])
+	if got := len(tree.Visible()); got != 3 {
+		t.Errorf("Expected 3 visible nodes after collapsing a, got %d", got)
+	}
+}
+
+func TestTreeSharesPackagesOfTheSameVersion(t *testing.T) {
+	withGoMods(t, map[string]string{
+		"example.com/a@v1.0.0": "module example.com/a\n\nrequire example.com/b v1.2.0\n",
+	})
+
+	a := model.NewPackage("example.com/a", "v1.0.0")
+	b := model.NewPackage("example.com/b", "v1.2.0")
+	tree := New([]*model.Package{a, b}, nil)
+
+	if err := tree.Expand(tree.Roots[0]); err != nil {
+		t.Fatalf("Expand() returned an error: %v", err)
+	}
+	child := tree.Roots[0].Children[0]
+	if child.Package != b {
+		t.Error("Expected the requirement to use the package from the top level")
+	}
+	if !child.Duplicate || child.Conflict() {
+		t.Errorf("Expected b to be a duplicate without conflict, got %+v", child)
+	}
+}
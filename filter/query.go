@@ -0,0 +1,318 @@
+package filter
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sahilm/fuzzy"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// Query is a parsed filter query. Terms of the form key:value are conditions every package
+// has to meet, @name refers to a saved filter, and any other term is matched fuzzily against the path.
+type Query struct {
+	conditions []condition
+	text       []string
+}
+
+// condition is a key:value term of a query
+type condition func(pkg *model.Package) bool
+
+// Keys lists the keys that can be used in key:value terms
+var Keys = []string{"host", "starred", "watched", "size", "license", "outdated", "pseudo", "incompatible", "prerelease", "indirect", "deprecated", "retracted"}
+
+// SyntaxError reports an invalid term in a query
+type SyntaxError struct {
+	Offset int    // Byte offset of the invalid term in the query
+	Length int    // Length of the invalid term in bytes
+	Msg    string // Description of the problem
+}
+
+// Error returns the description of the problem and where it is
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s (column %d)", e.Msg, e.Offset+1)
+}
+
+// term is a whitespace separated part of a query
+type term struct {
+	text   string
+	offset int
+}
+
+// Parse parses a filter query. Saved filters referenced with @name are looked up in saved.
+func Parse(query string, saved map[string]string) (*Query, error) {
+	q := &Query{}
+	if err := q.parse(query, saved, nil); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// parse adds the terms of the query, keeping track of the saved filters being expanded to detect cycles
+func (q *Query) parse(query string, saved map[string]string, expanding []string) error {
+	for _, t := range splitTerms(query) {
+		syntaxError := func(format string, args ...interface{}) error {
+			return &SyntaxError{Offset: t.offset, Length: len(t.text), Msg: fmt.Sprintf(format, args...)}
+		}
+
+		if name, ok := strings.CutPrefix(t.text, "@"); ok {
+			savedQuery, found := saved[name]
+			if !found {
+				return syntaxError("unknown saved filter @%s", name)
+			}
+			for _, n := range expanding {
+				if n == name {
+					return syntaxError("saved filter @%s refers to itself", name)
+				}
+			}
+			if err := q.parse(savedQuery, saved, append(expanding, name)); err != nil {
+				return syntaxError("saved filter @%s: %v", name, err)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(t.text, ":")
+		if !ok {
+			q.text = append(q.text, t.text)
+			continue
+		}
+		if value == "" {
+			return syntaxError("missing value for %s", key)
+		}
+
+		cond, err := newCondition(strings.ToLower(key), value)
+		if err != nil {
+			return syntaxError("%v", err)
+		}
+		q.conditions = append(q.conditions, cond)
+	}
+	return nil
+}
+
+// splitTerms splits the query at whitespace, remembering where each term starts
+func splitTerms(query string) []term {
+	var terms []term
+	start := -1
+	for i, r := range query {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			terms = append(terms, term{text: query[start:i], offset: start})
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		terms = append(terms, term{text: query[start:], offset: start})
+	}
+	return terms
+}
+
+// newCondition creates the condition for a key:value term
+func newCondition(key, value string) (condition, error) {
+	switch key {
+	case "host":
+		return hostCondition(value)
+	case "size":
+		return sizeCondition(value)
+	case "license":
+		return licenseCondition(value)
+	case "starred":
+		return boolCondition(key, value, func(pkg *model.Package) bool { return pkg.IsStarred })
+	case "watched":
+		return boolCondition(key, value, func(pkg *model.Package) bool { return pkg.IsWatched })
+	case "indirect":
+		return boolCondition(key, value, func(pkg *model.Package) bool { return pkg.Indirect })
+	case "deprecated":
+		return boolCondition(key, value, func(pkg *model.Package) bool { return pkg.Deprecated != "" })
+	case "retracted":
+		return boolCondition(key, value, func(pkg *model.Package) bool { return pkg.Retracted })
+	case "outdated":
+		return boolCondition(key, value, func(pkg *model.Package) bool {
+			return pkg.Outdated()
+		})
+	case "pseudo":
+		return boolCondition(key, value, func(pkg *model.Package) bool { return pkg.ParsedVersion().Pseudo })
+	case "incompatible":
+		return boolCondition(key, value, func(pkg *model.Package) bool { return pkg.ParsedVersion().Incompatible })
+	case "prerelease":
+		return boolCondition(key, value, func(pkg *model.Package) bool { return pkg.ParsedVersion().IsPrerelease() })
+	}
+	return nil, fmt.Errorf("unknown key %q, expected one of %s", key, strings.Join(Keys, ", "))
+}
+
+// boolCondition creates a condition matching packages for which has returns the given yes or no
+func boolCondition(key, value string, has func(pkg *model.Package) bool) (condition, error) {
+	var want bool
+	switch strings.ToLower(value) {
+	case "yes", "y", "true":
+		want = true
+	case "no", "n", "false":
+		want = false
+	default:
+		return nil, fmt.Errorf("invalid value %q for %s, expected yes or no", value, key)
+	}
+	return func(pkg *model.Package) bool { return has(pkg) == want }, nil
+}
+
+// hostCondition creates a condition matching the host of the module path.
+// "github" matches github.com, and globs such as *.org are supported.
+func hostCondition(value string) (condition, error) {
+	pattern := strings.ToLower(value)
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid host pattern %q", value)
+	}
+	return func(pkg *model.Package) bool {
+		host := strings.ToLower(pkg.Host())
+		if host == pattern || strings.HasPrefix(host, pattern+".") {
+			return true
+		}
+		matched, _ := path.Match(pattern, host)
+		return matched
+	}, nil
+}
+
+// licenseCondition creates a condition matching the license of the GitHub repository, such as GPL*
+func licenseCondition(value string) (condition, error) {
+	pattern := strings.ToLower(value)
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid license pattern %q", value)
+	}
+	return func(pkg *model.Package) bool {
+		if pkg.Repo == nil || pkg.Repo.License == "" {
+			return false
+		}
+		matched, _ := path.Match(pattern, strings.ToLower(pkg.Repo.License))
+		return matched
+	}, nil
+}
+
+// sizeUnits are the units accepted in size conditions, matching model.Package.FormattedSize
+var sizeUnits = map[string]int64{
+	"":   1,
+	"b":  1,
+	"k":  1 << 10,
+	"kb": 1 << 10,
+	"m":  1 << 20,
+	"mb": 1 << 20,
+	"g":  1 << 30,
+	"gb": 1 << 30,
+}
+
+// sizeCondition creates a condition comparing the size of the package, such as >5MB or <=100KB.
+// Packages whose size is unknown never match.
+func sizeCondition(value string) (condition, error) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(value, prefix) {
+			op = prefix
+			break
+		}
+	}
+	amount := strings.TrimPrefix(value, op)
+
+	// Split the number from the unit
+	i := strings.IndexFunc(amount, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+	if i < 0 {
+		i = len(amount)
+	}
+	number, err := strconv.ParseFloat(amount[:i], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid size %q, expected a size such as >5MB", value)
+	}
+	unit, ok := sizeUnits[strings.ToLower(amount[i:])]
+	if !ok {
+		return nil, fmt.Errorf("invalid size unit %q, expected B, KB, MB or GB", amount[i:])
+	}
+	limit := int64(number * float64(unit))
+
+	return func(pkg *model.Package) bool {
+		if pkg.Size == 0 {
+			return false
+		}
+		switch op {
+		case ">":
+			return pkg.Size > limit
+		case ">=":
+			return pkg.Size >= limit
+		case "<":
+			return pkg.Size < limit
+		case "<=":
+			return pkg.Size <= limit
+		}
+		return pkg.Size == limit
+	}, nil
+}
+
+// Empty reports whether the query matches every package
+func (q *Query) Empty() bool {
+	return len(q.conditions) == 0 && len(q.text) == 0
+}
+
+// Match reports whether the package meets every key:value condition of the query.
+// The free text isn't taken into account.
+func (q *Query) Match(pkg *model.Package) bool {
+	for _, cond := range q.conditions {
+		if !cond(pkg) {
+			return false
+		}
+	}
+	return true
+}
+
+// packagePaths is a fuzzy.Source of package paths
+type packagePaths []*model.Package
+
+// String returns the path of the package at index i
+func (p packagePaths) String(i int) string {
+	return p[i].Path
+}
+
+// Len returns the number of packages
+func (p packagePaths) Len() int {
+	return len(p)
+}
+
+// Filter returns the packages matching the query. When the query has free text,
+// packages are ordered by how well their path matches it.
+func (q *Query) Filter(packages []*model.Package) []*model.Package {
+	var candidates []*model.Package
+	for _, pkg := range packages {
+		if q.Match(pkg) {
+			candidates = append(candidates, pkg)
+		}
+	}
+	if len(q.text) == 0 {
+		return candidates
+	}
+
+	// Every text term has to match; the scores of all terms are added up
+	scores := make(map[int]int)
+	counts := make(map[int]int)
+	for _, text := range q.text {
+		for _, match := range fuzzy.FindFromNoSort(text, packagePaths(candidates)) {
+			scores[match.Index] += match.Score
+			counts[match.Index]++
+		}
+	}
+
+	var indexes []int
+	for i := range candidates {
+		if counts[i] == len(q.text) {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return scores[indexes[a]] > scores[indexes[b]]
+	})
+
+	matched := make([]*model.Package, len(indexes))
+	for i, index := range indexes {
+		matched[i] = candidates[index]
+	}
+	return matched
+}
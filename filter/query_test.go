@@ -0,0 +1,130 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// testPackages returns packages covering every key of the query language
+func testPackages() []*model.Package {
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.21.0-rc.1")
+	bubbles.IsStarred = true
+	bubbles.Size = 8 << 20
+	bubbles.LatestVersion = "v0.21.0"
+	bubbles.Repo = &model.RepoMetadata{License: "MIT"}
+
+	gpl := model.NewPackage("github.com/owner/gpl", "v1.0.1-0.20240102030405-0123456789ab")
+	gpl.Size = 100 << 10
+	gpl.LatestVersion = "v1.0.0"
+	gpl.Repo = &model.RepoMetadata{License: "GPL-3.0"}
+
+	mod := model.NewPackage("golang.org/x/mod", "v2.0.0+incompatible")
+	mod.Size = 2 << 20
+	mod.Indirect = true
+	mod.Deprecated = "use something else"
+
+	return []*model.Package{bubbles, gpl, mod}
+}
+
+// paths returns the paths of the packages
+func paths(packages []*model.Package) []string {
+	var result []string
+	for _, pkg := range packages {
+		result = append(result, pkg.Path)
+	}
+	return result
+}
+
+func TestFilter(t *testing.T) {
+	saved := map[string]string{
+		"big":      "size:>5MB",
+		"bigstars": "@big starred:yes",
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"", []string{"github.com/charmbracelet/bubbles", "github.com/owner/gpl", "golang.org/x/mod"}},
+		{"host:github", []string{"github.com/charmbracelet/bubbles", "github.com/owner/gpl"}},
+		{"host:*.org", []string{"golang.org/x/mod"}},
+		{"starred:no", []string{"github.com/owner/gpl", "golang.org/x/mod"}},
+		{"size:>5MB", []string{"github.com/charmbracelet/bubbles"}},
+		{"size:<=2mb", []string{"github.com/owner/gpl", "golang.org/x/mod"}},
+		{"license:GPL*", []string{"github.com/owner/gpl"}},
+		{"license:mit", []string{"github.com/charmbracelet/bubbles"}},
+		{"outdated:yes", []string{"github.com/charmbracelet/bubbles"}},
+		{"pseudo:yes", []string{"github.com/owner/gpl"}},
+		{"incompatible:yes", []string{"golang.org/x/mod"}},
+		{"prerelease:yes", []string{"github.com/charmbracelet/bubbles"}},
+		{"indirect:no deprecated:no", []string{"github.com/charmbracelet/bubbles", "github.com/owner/gpl"}},
+		{"host:github starred:no size:<5MB", []string{"github.com/owner/gpl"}},
+		{"@big", []string{"github.com/charmbracelet/bubbles"}},
+		{"@bigstars host:github", []string{"github.com/charmbracelet/bubbles"}},
+		{"mod", []string{"golang.org/x/mod"}},
+		{"chrm bbl", []string{"github.com/charmbracelet/bubbles"}},
+		{"host:github zzz", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := Parse(tt.query, saved)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned an error: %v", tt.query, err)
+			}
+			if got := paths(query.Filter(testPackages())); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Filter(%q) = %v, want %v", tt.query, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterRanksFuzzyMatches(t *testing.T) {
+	packages := []*model.Package{
+		model.NewPackage("github.com/charmbracelet/lipgloss", "v1.0.0"),
+		model.NewPackage("github.com/charmbracelet/bubbletea", "v1.0.0"),
+	}
+
+	query, err := Parse("tea", nil)
+	if err != nil {
+		t.Fatalf("Parse() returned an error: %v", err)
+	}
+	if got := paths(query.Filter(packages)); len(got) == 0 || got[0] != "github.com/charmbracelet/bubbletea" {
+		t.Errorf("Expected bubbletea to rank first, got %v", got)
+	}
+}
+
+func TestParseSyntaxErrors(t *testing.T) {
+	saved := map[string]string{"loop": "@loop", "broken": "size:huge"}
+
+	tests := []struct {
+		query  string
+		offset int
+		length int
+	}{
+		{"host:github colour:red", 12, 10},
+		{"starred:maybe", 0, 13},
+		{"  size:>5XB", 2, 9},
+		{"size:", 0, 5},
+		{"license:[", 0, 9},
+		{"@missing", 0, 8},
+		{"@loop", 0, 5},
+		{"outdated:yes @broken", 13, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query, saved)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want a SyntaxError", tt.query, err)
+			}
+			if syntaxErr.Offset != tt.offset || syntaxErr.Length != tt.length {
+				t.Errorf("Parse(%q) error at %d+%d, want %d+%d: %v", tt.query, syntaxErr.Offset, syntaxErr.Length, tt.offset, tt.length, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// validName matches the names filters can be saved as
+var validName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Saved holds the filters saved by the user, referenced in queries as @name
+type Saved struct {
+	path    string
+	Filters map[string]string `yaml:"filters"`
+}
+
+// DefaultSavedPath returns the path of the saved filters in the user config directory
+// ($XDG_CONFIG_HOME, or ~/.config)
+func DefaultSavedPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "gh-lsmod", "filters.yml"), nil
+}
+
+// NewSaved creates an empty set of saved filters stored at path.
+// With an empty path, filters are only kept in memory.
+func NewSaved(path string) *Saved {
+	return &Saved{path: path, Filters: make(map[string]string)}
+}
+
+// LoadSaved reads the saved filters. A missing file means no filters have been saved.
+func LoadSaved(path string) (*Saved, error) {
+	saved := NewSaved(path)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return saved, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved filters: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, saved); err != nil {
+		return nil, fmt.Errorf("failed to parse saved filters %s: %w", path, err)
+	}
+	if saved.Filters == nil {
+		saved.Filters = make(map[string]string)
+	}
+	return saved, nil
+}
+
+// Set saves the query under the name, after checking that it's valid
+func (s *Saved) Set(name, query string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid filter name %q, use letters, digits, - and _", name)
+	}
+
+	// The query may refer to other saved filters, but not to itself
+	filters := make(map[string]string, len(s.Filters)+1)
+	for n, q := range s.Filters {
+		filters[n] = q
+	}
+	filters[name] = query
+	if _, err := Parse(query, filters); err != nil {
+		return err
+	}
+
+	s.Filters[name] = query
+	return s.Save()
+}
+
+// Delete removes the saved filter
+func (s *Saved) Delete(name string) error {
+	delete(s.Filters, name)
+	return s.Save()
+}
+
+// Names returns the names of the saved filters in alphabetical order
+func (s *Saved) Names() []string {
+	names := make([]string, 0, len(s.Filters))
+	for name := range s.Filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save writes the saved filters to their file
+func (s *Saved) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to save filters: %w", err)
+	}
+
+	// Write to a temporary file first so that an interrupted save never leaves a partial file
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to save filters: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save filters: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save filters: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save filters: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDefaultSavedPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/config")
+
+	path, err := DefaultSavedPath()
+	if err != nil {
+		t.Fatalf("DefaultSavedPath() returned an error: %v", err)
+	}
+	if want := filepath.Join("/tmp/config", "gh-lsmod", "filters.yml"); path != want {
+		t.Errorf("DefaultSavedPath() = %s, want %s", path, want)
+	}
+}
+
+func TestSavedLoadAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gh-lsmod", "filters.yml")
+
+	// A missing file means no filters have been saved
+	saved, err := LoadSaved(path)
+	if err != nil {
+		t.Fatalf("LoadSaved() returned an error: %v", err)
+	}
+	if len(saved.Filters) != 0 {
+		t.Errorf("Expected no saved filters, got %v", saved.Filters)
+	}
+
+	if err := saved.Set("unstarred", "host:github starred:no"); err != nil {
+		t.Fatalf("Set() returned an error: %v", err)
+	}
+	if err := saved.Set("big-unstarred", "@unstarred size:>5MB"); err != nil {
+		t.Fatalf("Set() returned an error: %v", err)
+	}
+
+	// Invalid names and queries aren't saved
+	if err := saved.Set("has space", "starred:no"); err == nil {
+		t.Error("Expected an error for an invalid name")
+	}
+	if err := saved.Set("broken", "starred:maybe"); err == nil {
+		t.Error("Expected an error for an invalid query")
+	}
+	if err := saved.Set("loop", "@loop"); err == nil {
+		t.Error("Expected an error for a filter referring to itself")
+	}
+
+	loaded, err := LoadSaved(path)
+	if err != nil {
+		t.Fatalf("LoadSaved() returned an error: %v", err)
+	}
+	if want := []string{"big-unstarred", "unstarred"}; !reflect.DeepEqual(loaded.Names(), want) {
+		t.Errorf("Names() = %v, want %v", loaded.Names(), want)
+	}
+
+	if err := loaded.Delete("unstarred"); err != nil {
+		t.Fatalf("Delete() returned an error: %v", err)
+	}
+	loaded, err = LoadSaved(path)
+	if err != nil {
+		t.Fatalf("LoadSaved() returned an error: %v", err)
+	}
+	if want := []string{"big-unstarred"}; !reflect.DeepEqual(loaded.Names(), want) {
+		t.Errorf("Names() = %v, want %v", loaded.Names(), want)
+	}
+
+	// A corrupt file is an error rather than silently losing the filters
+	if err := os.WriteFile(path, []byte("filters: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSaved(path); err == nil {
+		t.Error("Expected an error for a corrupt file")
+	}
+}
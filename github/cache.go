@@ -0,0 +1,291 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Default time-to-live of cached responses
+const (
+	DefaultStarTTL     = time.Hour
+	DefaultMetadataTTL = 24 * time.Hour
+)
+
+// Cache entry classes, each stored in its own subdirectory of the cache
+const (
+	cacheClassStar     = "star"
+	cacheClassMetadata = "metadata"
+)
+
+// ErrOffline is returned for requests that can't be served from the cache in offline mode
+var ErrOffline = errors.New("not available offline")
+
+// CacheOptions configures the on-disk response cache.
+// The cache is disabled when Dir is empty.
+type CacheOptions struct {
+	Dir         string        // Directory the responses are stored in
+	StarTTL     time.Duration // How long responses including the viewer's star or watch status are used
+	MetadataTTL time.Duration // How long other responses, such as repository metadata, are used
+	Offline     bool          // Serve responses only from the cache, never from the network
+}
+
+// DefaultCacheDir returns the cache directory in the user cache directory
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-lsmod", "http"), nil
+}
+
+// cacheEntry is a response stored on disk
+type cacheEntry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	StoredAt   time.Time
+}
+
+// cacheTransport is an http.RoundTripper that stores responses on disk,
+// serves them while they're fresh, and revalidates stale ones with
+// If-None-Match/If-Modified-Since so that unchanged data doesn't count against the rate limit.
+// GitHub's GraphQL API sends no validators, so stale GraphQL responses are fetched again in full.
+type cacheTransport struct {
+	transport http.RoundTripper
+	opts      CacheOptions
+	now       func() time.Time
+}
+
+// newCacheTransport wraps the transport with the response cache
+func newCacheTransport(transport http.RoundTripper, opts CacheOptions) *cacheTransport {
+	if opts.StarTTL == 0 {
+		opts.StarTTL = DefaultStarTTL
+	}
+	if opts.MetadataTTL == 0 {
+		opts.MetadataTTL = DefaultMetadataTTL
+	}
+
+	return &cacheTransport{
+		transport: transport,
+		opts:      opts,
+		now:       time.Now,
+	}
+}
+
+// RoundTrip serves the request from the cache when possible
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isCacheableRequest(req, body) {
+		if t.opts.Offline {
+			return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrOffline)
+		}
+
+		resp, err := t.transport.RoundTrip(req)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			// Starring, unstarring and other mutations make cached star status stale
+			_ = os.RemoveAll(filepath.Join(t.opts.Dir, cacheClassStar))
+		}
+		return resp, err
+	}
+
+	class := cacheClass(req, body)
+	path := filepath.Join(t.opts.Dir, class, cacheKey(req, body))
+	entry, _ := t.load(path)
+
+	if t.opts.Offline {
+		if entry == nil {
+			return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrOffline)
+		}
+		return entry.response(req), nil
+	}
+
+	if entry != nil && t.now().Sub(entry.StoredAt) < t.ttl(class) {
+		return entry.response(req), nil
+	}
+
+	// Revalidate the stale entry instead of downloading it again
+	if entry != nil {
+		req = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		_ = resp.Body.Close()
+		entry.StoredAt = t.now()
+		_ = t.save(path, entry)
+		return entry.response(req), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	_ = t.save(path, &cacheEntry{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       respBody,
+		StoredAt:   t.now(),
+	})
+
+	return resp, nil
+}
+
+// ttl returns the time-to-live of the cache class
+func (t *cacheTransport) ttl(class string) time.Duration {
+	if class == cacheClassStar {
+		return t.opts.StarTTL
+	}
+	return t.opts.MetadataTTL
+}
+
+// load reads a cache entry from disk
+func (t *cacheTransport) load(path string) (*cacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// save writes a cache entry to disk
+func (t *cacheTransport) save(path string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// response builds an HTTP response from the cache entry
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// readRequestBody reads the request body and replaces it so that it can still be sent
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, nil
+}
+
+// isCacheableRequest reports whether the request only reads data: a GET, or a GraphQL query
+func isCacheableRequest(req *http.Request, body []byte) bool {
+	switch req.Method {
+	case http.MethodGet:
+		return true
+	case http.MethodPost:
+		return isGraphQL(req) && !isGraphQLMutation(body)
+	}
+	return false
+}
+
+// isGraphQL reports whether the request is sent to the GraphQL endpoint
+func isGraphQL(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, "/graphql")
+}
+
+// isGraphQLMutation reports whether the GraphQL request body is a mutation
+func isGraphQLMutation(body []byte) bool {
+	var payload struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimSpace(payload.Query), "mutation")
+}
+
+// cacheClass returns the class of the cached request, which determines its time-to-live
+func cacheClass(req *http.Request, body []byte) string {
+	// Star lists belong to the viewer and change whenever stars do
+	if strings.Contains(req.URL.Path, "/user/starred") || bytes.Contains(body, []byte("viewerHasStarred")) || bytes.Contains(body, []byte("UserList")) || bytes.Contains(body, []byte("lists(")) {
+		return cacheClassStar
+	}
+	return cacheClassMetadata
+}
+
+// cacheKey identifies a request in the cache.
+// The authorization header is part of the key so that users never see each other's data.
+func cacheKey(req *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n%s\n%s\n", req.Method, req.URL.String(), req.Header.Get("Authorization"), req.Header.Get("Accept"))
+	_, _ = h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
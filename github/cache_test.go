@@ -0,0 +1,260 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// etagServer serves a fixed body with an ETag and counts full and not-modified responses
+type etagServer struct {
+	mu          sync.Mutex
+	body        string
+	etag        string
+	full        int
+	notModified int
+}
+
+func (s *etagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Header.Get("If-None-Match") == s.etag {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	s.full++
+	w.Header().Set("ETag", s.etag)
+	_, _ = io.WriteString(w, s.body)
+}
+
+// cachedGet issues a request through the cache transport and returns the response body
+func cachedGet(t *testing.T, client *http.Client, method, url, body string) (string, error) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "token test-token")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	return string(data), nil
+}
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	origin := &etagServer{body: `[{"tag_name":"v1.0.0"}]`, etag: `"abc"`}
+	server := httptest.NewServer(origin)
+	defer server.Close()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	transport := newCacheTransport(http.DefaultTransport, CacheOptions{Dir: t.TempDir(), MetadataTTL: time.Hour})
+	transport.now = func() time.Time { return now }
+	client := &http.Client{Transport: transport}
+
+	url := server.URL + "/repos/charmbracelet/bubbles/releases"
+
+	// First request is downloaded and stored
+	if body, err := cachedGet(t, client, http.MethodGet, url, ""); err != nil || body != origin.body {
+		t.Fatalf("First request = %q, %v", body, err)
+	}
+
+	// Fresh entries are served without a request
+	now = now.Add(30 * time.Minute)
+	if body, err := cachedGet(t, client, http.MethodGet, url, ""); err != nil || body != origin.body {
+		t.Fatalf("Fresh request = %q, %v", body, err)
+	}
+	if origin.full != 1 || origin.notModified != 0 {
+		t.Errorf("Expected 1 full response and no revalidation, got %d full and %d not modified", origin.full, origin.notModified)
+	}
+
+	// Stale entries are revalidated and served from the cache on 304
+	now = now.Add(time.Hour)
+	if body, err := cachedGet(t, client, http.MethodGet, url, ""); err != nil || body != origin.body {
+		t.Fatalf("Revalidated request = %q, %v", body, err)
+	}
+	if origin.full != 1 || origin.notModified != 1 {
+		t.Errorf("Expected 1 full response and 1 revalidation, got %d full and %d not modified", origin.full, origin.notModified)
+	}
+
+	// Revalidation refreshes the entry
+	now = now.Add(30 * time.Minute)
+	if _, err := cachedGet(t, client, http.MethodGet, url, ""); err != nil {
+		t.Fatalf("Request after revalidation returned an error: %v", err)
+	}
+	if origin.notModified != 1 {
+		t.Errorf("Expected the revalidated entry to be fresh again, got %d revalidations", origin.notModified)
+	}
+
+	// Changed content replaces the entry
+	origin.body = `[{"tag_name":"v1.1.0"}]`
+	origin.etag = `"def"`
+	now = now.Add(2 * time.Hour)
+	if body, err := cachedGet(t, client, http.MethodGet, url, ""); err != nil || body != origin.body {
+		t.Fatalf("Changed request = %q, %v", body, err)
+	}
+}
+
+func TestCacheStarStatusIsInvalidatedByMutations(t *testing.T) {
+	var queries int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/graphql" {
+			queries++
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, `{"data":{}}`)
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	transport := newCacheTransport(http.DefaultTransport, CacheOptions{Dir: t.TempDir(), StarTTL: time.Hour, MetadataTTL: 24 * time.Hour})
+	transport.now = func() time.Time { return now }
+	client := &http.Client{Transport: transport}
+
+	query := `{"query":"query { repo0: repository(owner: \"a\", name: \"b\") { viewerHasStarred } }"}`
+	for i := 0; i < 2; i++ {
+		if _, err := cachedGet(t, client, http.MethodPost, server.URL+"/graphql", query); err != nil {
+			t.Fatalf("Query returned an error: %v", err)
+		}
+	}
+	if queries != 1 {
+		t.Errorf("Expected the second query to be served from the cache, got %d queries", queries)
+	}
+
+	// Star status uses the shorter star TTL
+	now = now.Add(2 * time.Hour)
+	if _, err := cachedGet(t, client, http.MethodPost, server.URL+"/graphql", query); err != nil {
+		t.Fatalf("Query returned an error: %v", err)
+	}
+	if queries != 2 {
+		t.Errorf("Expected star status to expire after the star TTL, got %d queries", queries)
+	}
+
+	// Starring a repository invalidates cached star status
+	if _, err := cachedGet(t, client, http.MethodPut, server.URL+"/user/starred/a/b", ""); err != nil {
+		t.Fatalf("Star request returned an error: %v", err)
+	}
+	if _, err := cachedGet(t, client, http.MethodPost, server.URL+"/graphql", query); err != nil {
+		t.Fatalf("Query returned an error: %v", err)
+	}
+	if queries != 3 {
+		t.Errorf("Expected star status to be fetched again after starring, got %d queries", queries)
+	}
+
+	// Mutations are never cached
+	mutation := `{"query":"mutation { addStar(input: {starrableId: \"x\"}) { clientMutationId } }"}`
+	for i := 0; i < 2; i++ {
+		if _, err := cachedGet(t, client, http.MethodPost, server.URL+"/graphql", mutation); err != nil {
+			t.Fatalf("Mutation returned an error: %v", err)
+		}
+	}
+	if queries != 5 {
+		t.Errorf("Expected mutations to reach the server every time, got %d queries", queries)
+	}
+}
+
+func TestCacheClassOfRepositoryQueries(t *testing.T) {
+	tests := []struct {
+		name         string
+		fragmentName string
+		fragment     string
+		expected     string
+	}{
+		{name: "Repository metadata", fragmentName: "repoFields", fragment: repoFieldsFragment, expected: cacheClassMetadata},
+		{name: "Star and watch status", fragmentName: "viewerFields", fragment: viewerFieldsFragment, expected: cacheClassStar},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, variables := buildRepositoriesQuery([]string{"charmbracelet/bubbles"}, tt.fragmentName, tt.fragment)
+			body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+			if err != nil {
+				t.Fatalf("Failed to encode the query: %v", err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "https://api.github.com/graphql", nil)
+			if got := cacheClass(req, body); got != tt.expected {
+				t.Errorf("cacheClass() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCacheOffline(t *testing.T) {
+	origin := &etagServer{body: `{"ok":true}`, etag: `"abc"`}
+	server := httptest.NewServer(origin)
+	defer server.Close()
+
+	dir := t.TempDir()
+	online := &http.Client{Transport: newCacheTransport(http.DefaultTransport, CacheOptions{Dir: dir})}
+	if _, err := cachedGet(t, online, http.MethodGet, server.URL+"/cached", ""); err != nil {
+		t.Fatalf("Online request returned an error: %v", err)
+	}
+
+	now := time.Now().Add(365 * 24 * time.Hour)
+	transport := newCacheTransport(http.DefaultTransport, CacheOptions{Dir: dir, Offline: true})
+	transport.now = func() time.Time { return now }
+	offline := &http.Client{Transport: transport}
+
+	// Cached responses are served even when stale
+	if body, err := cachedGet(t, offline, http.MethodGet, server.URL+"/cached", ""); err != nil || body != origin.body {
+		t.Errorf("Offline cached request = %q, %v", body, err)
+	}
+	if origin.full != 1 || origin.notModified != 0 {
+		t.Errorf("Expected no requests in offline mode, got %d full and %d not modified", origin.full, origin.notModified)
+	}
+
+	if _, err := cachedGet(t, offline, http.MethodGet, server.URL+"/uncached", ""); !errors.Is(err, ErrOffline) {
+		t.Errorf("Offline uncached request error = %v, want ErrOffline", err)
+	}
+	if _, err := cachedGet(t, offline, http.MethodPut, server.URL+"/user/starred/a/b", ""); !errors.Is(err, ErrOffline) {
+		t.Errorf("Offline star request error = %v, want ErrOffline", err)
+	}
+}
+
+func TestOfflineClientStarActions(t *testing.T) {
+	fake := newFakeGitHub()
+	server := httptest.NewTLSServer(fake)
+	defer server.Close()
+
+	client, err := NewClientWithCache(api.ClientOptions{
+		Host:      strings.TrimPrefix(server.URL, "https://"),
+		AuthToken: "test-token",
+		Transport: server.Client().Transport,
+	}, CacheOptions{Dir: t.TempDir(), Offline: true})
+	if err != nil {
+		t.Fatalf("NewClientWithCache() returned an error: %v", err)
+	}
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	if err := client.StarRepository(pkg); !errors.Is(err, ErrOffline) {
+		t.Errorf("StarRepository() error = %v, want ErrOffline", err)
+	}
+	if pkg.IsStarred {
+		t.Error("Expected package not to be starred offline")
+	}
+	if fake.requests != 0 {
+		t.Errorf("Expected no requests in offline mode, got %d", fake.requests)
+	}
+}
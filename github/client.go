@@ -1,60 +1,128 @@
 package github
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/tnagatomi/gh-lsmod/metadata"
 	"github.com/tnagatomi/gh-lsmod/model"
 )
 
 // GitHubClient defines the interface for GitHub operations
 type GitHubClient interface {
 	CheckStarredStatus(packages []*model.Package) error
+	FetchRepositories(packages []*model.Package) error
 	StarRepository(pkg *model.Package) error
 	UnstarRepository(pkg *model.Package) error
-	StarAllUnstarred(packages []*model.Package) (int, error)
+	StarRepositories(packages []*model.Package) (int, error)
+	UnstarRepositories(packages []*model.Package) (int, error)
+	ListReleases(pkg *model.Package) ([]Release, error)
+	FetchReadme(pkg *model.Package) (*Readme, error)
+	WatchRepository(pkg *model.Package) error
+	UnwatchRepository(pkg *model.Package) error
+	WatchAllUnwatched(packages []*model.Package) (int, error)
+	ListStarLists() ([]StarList, error)
+	CreateStarList(name, description string, private bool) (*StarList, error)
+	AddToStarList(list *StarList, packages []*model.Package) error
+	RemoveFromStarList(list *StarList, packages []*model.Package) error
 }
 
+// HasToken reports whether a token for the default host is set in the environment or gh's config.
+// The host is resolved as the API clients do, so that GH_HOST and GitHub Enterprise Server logins count.
+// Without one, every API request fails.
+func HasToken() bool {
+	host, _ := auth.DefaultHost()
+	token, _ := auth.TokenForHost(host)
+	return token != ""
+}
+
+// DefaultConcurrency is the default number of GitHub API requests run in parallel
+const DefaultConcurrency = 4
+
+// ErrRepositoryNotFound is returned when a repository doesn't exist or isn't accessible
+var ErrRepositoryNotFound = errors.New("repository not found")
+
+// ErrNoReadme is returned when a repository exists but has no README
+var ErrNoReadme = errors.New("no README")
+
 // Client handles GitHub API operations
 type Client struct {
-	restClient *api.RESTClient
+	restClient            *api.RESTClient
+	graphQLClient         *api.GraphQLClient
+	uncachedGraphQLClient *api.GraphQLClient // Bypasses the response cache
+	transport             *rateLimitTransport
+	concurrency           int
 }
 
 // NewClient creates a new GitHub client
 func NewClient() (*Client, error) {
-	restClient, err := api.DefaultRESTClient()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create GitHub REST client: %w", err)
-	}
+	return NewClientWithOptions(api.ClientOptions{})
+}
 
-	return &Client{
-		restClient: restClient,
-	}, nil
+// NewClientWithOptions creates a new GitHub client with the given API client options.
+// Unset options are resolved from the gh environment configuration.
+func NewClientWithOptions(opts api.ClientOptions) (*Client, error) {
+	return NewClientWithCache(opts, CacheOptions{})
 }
 
-// CheckStarredStatus checks if the repositories are starred by the authenticated user
-func (c *Client) CheckStarredStatus(packages []*model.Package) error {
-	for _, pkg := range packages {
-		if !pkg.IsGitHub {
-			continue
-		}
+// NewClientWithCache creates a new GitHub client that stores responses in an on-disk cache
+func NewClientWithCache(opts api.ClientOptions, cacheOpts CacheOptions) (*Client, error) {
+	transport := newRateLimitTransport(baseTransport(opts))
+	opts.Transport = transport
+	if cacheOpts.Dir != "" {
+		opts.Transport = newCacheTransport(transport, cacheOpts)
+	}
 
-		repoPath := pkg.GitHubRepoPath()
-		if repoPath == "" {
-			continue
-		}
+	restClient, err := api.NewRESTClient(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub REST client: %w", err)
+	}
+
+	graphQLClient, err := api.NewGraphQLClient(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub GraphQL client: %w", err)
+	}
 
-		// GitHub API returns 204 if starred, 404 if not starred
-		statusCode, err := c.checkStarred(repoPath)
+	// Reads that a mutation is based on skip the cache, except offline where the mutation fails anyway
+	uncachedGraphQLClient := graphQLClient
+	if cacheOpts.Dir != "" && !cacheOpts.Offline {
+		opts.Transport = transport
+		uncachedGraphQLClient, err = api.NewGraphQLClient(opts)
 		if err != nil {
-			return fmt.Errorf("failed to check star status for %s: %w", repoPath, err)
+			return nil, fmt.Errorf("failed to create GitHub GraphQL client: %w", err)
 		}
+	}
+
+	return &Client{
+		restClient:            restClient,
+		graphQLClient:         graphQLClient,
+		uncachedGraphQLClient: uncachedGraphQLClient,
+		transport:             transport,
+		concurrency:           DefaultConcurrency,
+	}, nil
+}
 
-		pkg.IsStarred = (statusCode == 204)
+// SetConcurrency sets the maximum number of GitHub API requests run in parallel
+func (c *Client) SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
 	}
+	c.concurrency = n
+}
 
-	return nil
+// RateLimit returns the last known rate limit state of the resource (e.g. "core" or "graphql")
+func (c *Client) RateLimit(resource string) (RateLimit, bool) {
+	return c.transport.RateLimit(resource)
+}
+
+// CheckStarredStatus checks if the repositories are starred and watched by the authenticated user,
+// without fetching their metadata
+func (c *Client) CheckStarredStatus(packages []*model.Package) error {
+	return c.fetchRepositories(packages, false)
 }
 
 // StarRepository stars a repository
@@ -69,6 +137,9 @@ func (c *Client) StarRepository(pkg *model.Package) error {
 	}
 
 	err := c.putStar(repoPath)
+	if isNotFound(err) {
+		err = ErrRepositoryNotFound
+	}
 	if err != nil {
 		return fmt.Errorf("failed to star repository %s: %w", repoPath, err)
 	}
@@ -89,6 +160,9 @@ func (c *Client) UnstarRepository(pkg *model.Package) error {
 	}
 
 	err := c.deleteStar(repoPath)
+	if isNotFound(err) {
+		err = ErrRepositoryNotFound
+	}
 	if err != nil {
 		return fmt.Errorf("failed to unstar repository %s: %w", repoPath, err)
 	}
@@ -97,44 +171,81 @@ func (c *Client) UnstarRepository(pkg *model.Package) error {
 	return nil
 }
 
-// StarAllUnstarred stars all unstarred GitHub repositories
-func (c *Client) StarAllUnstarred(packages []*model.Package) (int, error) {
-	count := 0
-	for _, pkg := range packages {
-		if pkg.IsGitHub && !pkg.IsStarred {
-			err := c.StarRepository(pkg)
-			if err != nil {
-				return count, err
-			}
-			count++
+// StarRepositories stars the repositories of the packages that aren't starred yet, in parallel.
+// It returns the number of packages starred, and PackageErrors for those that failed.
+func (c *Client) StarRepositories(packages []*model.Package) (int, error) {
+	return c.forEachRepository(packages, func(pkg *model.Package) bool {
+		return !pkg.IsStarred
+	}, func(pkgs []*model.Package) error {
+		if err := c.StarRepository(pkgs[0]); err != nil {
+			return err
 		}
-	}
-	return count, nil
+		for _, pkg := range pkgs {
+			pkg.IsStarred = true
+		}
+		return nil
+	})
 }
 
-// checkStarred checks if a repository is starred by the authenticated user
-func (c *Client) checkStarred(repoPath string) (int, error) {
-	parts := strings.Split(repoPath, "/")
-	if len(parts) != 2 {
-		return 0, fmt.Errorf("invalid repository path: %s", repoPath)
-	}
+// UnstarRepositories unstars the repositories of the packages that are starred, in parallel.
+// It returns the number of packages unstarred, and PackageErrors for those that failed.
+func (c *Client) UnstarRepositories(packages []*model.Package) (int, error) {
+	return c.forEachRepository(packages, func(pkg *model.Package) bool {
+		return pkg.IsStarred
+	}, func(pkgs []*model.Package) error {
+		if err := c.UnstarRepository(pkgs[0]); err != nil {
+			return err
+		}
+		for _, pkg := range pkgs {
+			pkg.IsStarred = false
+		}
+		return nil
+	})
+}
 
-	owner, repo := parts[0], parts[1]
-	path := fmt.Sprintf("user/starred/%s/%s", owner, repo)
+// forEachRepository calls fn in parallel once per GitHub repository of the packages that match the filter,
+// with every matching package in that repository, since several modules can live in the same repository.
+// It returns the number of packages fn succeeded for, and PackageErrors for those it failed for.
+func (c *Client) forEachRepository(packages []*model.Package, filter func(pkg *model.Package) bool, fn func(pkgs []*model.Package) error) (int, error) {
+	byRepo := make(map[string][]*model.Package)
+	var repoPaths []string
+	for _, pkg := range packages {
+		if !pkg.IsGitHub || !filter(pkg) {
+			continue
+		}
+		repoPath := pkg.GitHubRepoPath()
+		if _, ok := byRepo[repoPath]; !ok {
+			repoPaths = append(repoPaths, repoPath)
+		}
+		byRepo[repoPath] = append(byRepo[repoPath], pkg)
+	}
 
-	resp, err := c.restClient.Request("GET", path, nil)
-	if err != nil {
-		if strings.Contains(err.Error(), "404") {
-			return 404, nil
+	var (
+		mu    sync.Mutex
+		count int
+		errs  PackageErrors
+	)
+	c.forEach(len(repoPaths), func(i int) {
+		pkgs := byRepo[repoPaths[i]]
+		err := fn(pkgs)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			for _, pkg := range pkgs {
+				errs = append(errs, &PackageError{Package: pkg, Err: err})
+			}
+			return
 		}
+		count += len(pkgs)
+	})
 
-		return 0, err
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
+	return count, errs.errorOrNil()
+}
 
-	return resp.StatusCode, nil
+// forEach calls fn for every index in [0, n), running at most c.concurrency calls at a time
+func (c *Client) forEach(n int, fn func(i int)) {
+	metadata.ForEach(n, c.concurrency, fn)
 }
 
 // putStar stars a repository
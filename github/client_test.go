@@ -0,0 +1,636 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// fakeGitHub is an in-memory stand-in for the GitHub REST and GraphQL APIs
+type fakeGitHub struct {
+	mu       sync.Mutex
+	starred  map[string]bool
+	watched  map[string]bool
+	missing  map[string]bool
+	failing  map[string]int // repo -> HTTP status returned for every star request
+	requests int
+	inFlight int
+	maxLoad  int
+
+	// intercept can answer a request before the regular handlers do
+	intercept func(w http.ResponseWriter, r *http.Request) bool
+}
+
+func newFakeGitHub() *fakeGitHub {
+	return &fakeGitHub{
+		starred: make(map[string]bool),
+		watched: make(map[string]bool),
+		missing: make(map[string]bool),
+		failing: make(map[string]int),
+	}
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests++
+	f.inFlight++
+	if f.inFlight > f.maxLoad {
+		f.maxLoad = f.inFlight
+	}
+	intercept := f.intercept
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+
+	// Give concurrent requests a chance to overlap
+	time.Sleep(5 * time.Millisecond)
+
+	if intercept != nil && intercept(w, r) {
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/api/graphql":
+		f.serveGraphQL(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/v3/user/starred/"):
+		f.serveStar(w, r, strings.TrimPrefix(r.URL.Path, "/api/v3/user/starred/"))
+	case strings.HasPrefix(r.URL.Path, "/api/v3/repos/") && strings.HasSuffix(r.URL.Path, "/subscription"):
+		f.serveSubscription(w, r, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v3/repos/"), "/subscription"))
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (f *fakeGitHub) serveStar(w http.ResponseWriter, r *http.Request, repo string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.missing[repo] {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if status := f.failing[repo]; status != 0 {
+		writeError(w, status, "Server Error")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		f.starred[repo] = true
+	case http.MethodDelete:
+		delete(f.starred, repo)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeGitHub) serveSubscription(w http.ResponseWriter, r *http.Request, repo string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.missing[repo] {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if status := f.failing[repo]; status != 0 {
+		writeError(w, status, "Server Error")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var body struct {
+			Subscribed bool
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || !body.Subscribed {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid subscription")
+			return
+		}
+		f.watched[repo] = true
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]bool{"subscribed": true})
+	case http.MethodDelete:
+		delete(f.watched, repo)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeGitHub) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query     string
+		Variables map[string]string
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data := make(map[string]interface{})
+	var errs []map[string]interface{}
+	for i := 0; ; i++ {
+		owner, ok := req.Variables["owner"+strconv.Itoa(i)]
+		if !ok {
+			break
+		}
+		repo := owner + "/" + req.Variables["name"+strconv.Itoa(i)]
+		alias := repoAlias(i)
+
+		if f.missing[repo] {
+			data[alias] = nil
+			errs = append(errs, map[string]interface{}{
+				"type":    "NOT_FOUND",
+				"path":    []string{alias},
+				"message": "Could not resolve to a Repository with the name '" + repo + "'.",
+			})
+			continue
+		}
+
+		data[alias] = map[string]interface{}{
+			"id":                 "R_" + repo,
+			"stargazerCount":     len(repo),
+			"forkCount":          1,
+			"pushedAt":           "2025-01-02T03:04:05Z",
+			"isArchived":         false,
+			"isDisabled":         false,
+			"issues":             map[string]int{"totalCount": 3},
+			"primaryLanguage":    map[string]string{"name": "Go"},
+			"licenseInfo":        map[string]string{"spdxId": "MIT", "name": "MIT License"},
+			"description":        "Repository " + repo,
+			"viewerHasStarred":   f.starred[repo],
+			"viewerSubscription": map[bool]string{true: "SUBSCRIBED", false: "UNSUBSCRIBED"}[f.watched[repo]],
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data, "errors": errs})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+// newTestClient creates a client talking to the fake GitHub and records the backoff delays
+func newTestClient(t *testing.T, fake *fakeGitHub) (*Client, *[]time.Duration) {
+	t.Helper()
+
+	server := httptest.NewTLSServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := NewClientWithOptions(api.ClientOptions{
+		Host:      strings.TrimPrefix(server.URL, "https://"),
+		AuthToken: "test-token",
+		Transport: server.Client().Transport,
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithOptions() returned an error: %v", err)
+	}
+
+	var (
+		mu     sync.Mutex
+		sleeps []time.Duration
+	)
+	client.transport.sleep = func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		sleeps = append(sleeps, d)
+	}
+	client.transport.jitter = func(time.Duration) time.Duration { return 0 }
+
+	return client, &sleeps
+}
+
+func TestStarAndUnstarRepository(t *testing.T) {
+	fake := newFakeGitHub()
+	fake.missing["gone/away"] = true
+	client, _ := newTestClient(t, fake)
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	if err := client.StarRepository(pkg); err != nil {
+		t.Fatalf("StarRepository() returned an error: %v", err)
+	}
+	if !pkg.IsStarred || !fake.starred["charmbracelet/bubbles"] {
+		t.Error("Expected repository to be starred")
+	}
+
+	if err := client.UnstarRepository(pkg); err != nil {
+		t.Fatalf("UnstarRepository() returned an error: %v", err)
+	}
+	if pkg.IsStarred || fake.starred["charmbracelet/bubbles"] {
+		t.Error("Expected repository to be unstarred")
+	}
+
+	missing := model.NewPackage("github.com/gone/away", "v1.0.0")
+	err := client.StarRepository(missing)
+	if !errors.Is(err, ErrRepositoryNotFound) {
+		t.Errorf("StarRepository() error = %v, want ErrRepositoryNotFound", err)
+	}
+
+	notGitHub := model.NewPackage("golang.org/x/mod", "v0.27.0")
+	if err := client.StarRepository(notGitHub); err == nil {
+		t.Error("Expected an error when starring a non-GitHub package, got nil")
+	}
+}
+
+func TestStarRepositoriesReportsPartialFailures(t *testing.T) {
+	fake := newFakeGitHub()
+	fake.failing["broken/repo"] = http.StatusInternalServerError
+	fake.missing["gone/away"] = true
+	client, sleeps := newTestClient(t, fake)
+	client.SetConcurrency(3)
+
+	var packages []*model.Package
+	for i := 0; i < 10; i++ {
+		packages = append(packages, model.NewPackage(fmt.Sprintf("github.com/owner/repo%d", i), "v1.0.0"))
+	}
+	broken := model.NewPackage("github.com/broken/repo", "v1.0.0")
+	missing := model.NewPackage("github.com/gone/away", "v1.0.0")
+	packages = append(packages, broken, missing, model.NewPackage("golang.org/x/mod", "v0.27.0"))
+
+	count, err := client.StarRepositories(packages)
+	if count != 10 {
+		t.Errorf("StarRepositories() count = %d, want 10", count)
+	}
+
+	var pkgErrs PackageErrors
+	if !errors.As(err, &pkgErrs) {
+		t.Fatalf("StarRepositories() error = %v, want PackageErrors", err)
+	}
+	failed := make(map[*model.Package]error)
+	for _, pkgErr := range pkgErrs {
+		failed[pkgErr.Package] = pkgErr.Err
+	}
+	if len(failed) != 2 || failed[broken] == nil || failed[missing] == nil {
+		t.Errorf("Expected failures for the broken and missing repositories, got %v", pkgErrs)
+	}
+	if !errors.Is(failed[missing], ErrRepositoryNotFound) {
+		t.Errorf("Expected missing repository to fail with ErrRepositoryNotFound, got %v", failed[missing])
+	}
+	if broken.IsStarred || missing.IsStarred {
+		t.Error("Expected failed packages not to be marked as starred")
+	}
+
+	// The broken repository is retried with exponential backoff before giving up
+	expectedSleeps := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	if len(*sleeps) != len(expectedSleeps) {
+		t.Fatalf("Expected backoffs %v, got %v", expectedSleeps, *sleeps)
+	}
+	for i, d := range *sleeps {
+		if d != expectedSleeps[i] {
+			t.Errorf("Backoff %d = %v, want %v", i, d, expectedSleeps[i])
+		}
+	}
+
+	if fake.maxLoad > 3 {
+		t.Errorf("Expected at most 3 concurrent requests, got %d", fake.maxLoad)
+	}
+	if fake.maxLoad < 2 {
+		t.Errorf("Expected requests to run concurrently, got at most %d at a time", fake.maxLoad)
+	}
+}
+
+func TestUnstarRepositories(t *testing.T) {
+	fake := newFakeGitHub()
+	fake.starred["owner/repo"] = true
+	fake.starred["owner/kept"] = true
+	client, _ := newTestClient(t, fake)
+
+	repo := model.NewPackage("github.com/owner/repo", "v1.0.0")
+	repo.IsStarred = true
+	repoV2 := model.NewPackage("github.com/owner/repo/v2", "v2.0.0")
+	repoV2.IsStarred = true
+	unstarred := model.NewPackage("github.com/owner/unstarred", "v1.0.0")
+
+	count, err := client.UnstarRepositories([]*model.Package{repo, repoV2, unstarred})
+	if err != nil {
+		t.Fatalf("UnstarRepositories() returned an error: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("UnstarRepositories() count = %d, want 2", count)
+	}
+	if repo.IsStarred || repoV2.IsStarred || fake.starred["owner/repo"] {
+		t.Error("Expected both modules of the repository to be unstarred")
+	}
+	if !fake.starred["owner/kept"] {
+		t.Error("Expected repositories outside the set to stay starred")
+	}
+}
+
+func TestFetchRepositoriesInBatches(t *testing.T) {
+	fake := newFakeGitHub()
+	fake.starred["owner/repo7"] = true
+	fake.missing["owner/repo99"] = true
+	client, _ := newTestClient(t, fake)
+
+	var packages []*model.Package
+	for i := 0; i < 120; i++ {
+		packages = append(packages, model.NewPackage(fmt.Sprintf("github.com/owner/repo%d", i), "v1.0.0"))
+	}
+	// A second module of an already listed repository doesn't need another query
+	subModule := model.NewPackage("github.com/owner/repo7/v2", "v2.0.0")
+	packages = append(packages, subModule, model.NewPackage("golang.org/x/mod", "v0.27.0"))
+
+	err := client.FetchRepositories(packages)
+
+	var pkgErrs PackageErrors
+	if !errors.As(err, &pkgErrs) {
+		t.Fatalf("FetchRepositories() error = %v, want PackageErrors", err)
+	}
+	if len(pkgErrs) != 1 || pkgErrs[0].Package != packages[99] || !errors.Is(pkgErrs[0], ErrRepositoryNotFound) {
+		t.Errorf("Expected only owner/repo99 to fail with ErrRepositoryNotFound, got %v", pkgErrs)
+	}
+
+	// Each batch fetches the metadata and the star status in separate queries
+	if fake.requests != 6 {
+		t.Errorf("Expected 6 batched GraphQL requests, got %d", fake.requests)
+	}
+
+	for i, pkg := range packages[:120] {
+		if i == 99 {
+			if pkg.Repo != nil {
+				t.Errorf("Expected no metadata for the missing repository, got %+v", pkg.Repo)
+			}
+			continue
+		}
+		if pkg.Repo == nil || pkg.Repo.Description != "Repository "+pkg.GitHubRepoPath() {
+			t.Errorf("Package %s: unexpected metadata %+v", pkg.Path, pkg.Repo)
+		}
+	}
+	if !packages[7].IsStarred || !subModule.IsStarred {
+		t.Error("Expected both modules of owner/repo7 to be starred")
+	}
+	if packages[8].IsStarred {
+		t.Error("Expected owner/repo8 not to be starred")
+	}
+}
+
+func TestSecondaryRateLimitIsRetried(t *testing.T) {
+	fake := newFakeGitHub()
+	limited := true
+	fake.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if limited {
+			limited = false
+			w.Header().Set("Retry-After", "5")
+			writeError(w, http.StatusForbidden, "You have exceeded a secondary rate limit.")
+			return true
+		}
+		return false
+	}
+	client, sleeps := newTestClient(t, fake)
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	if err := client.StarRepository(pkg); err != nil {
+		t.Fatalf("StarRepository() returned an error: %v", err)
+	}
+	if !fake.starred["charmbracelet/bubbles"] {
+		t.Error("Expected repository to be starred after retrying")
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] != 5*time.Second {
+		t.Errorf("Expected a single backoff of 5s, got %v", *sleeps)
+	}
+}
+
+func TestSecondaryRateLimitWithoutRetryAfterBacksOff(t *testing.T) {
+	fake := newFakeGitHub()
+	limited := 2
+	fake.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if limited > 0 {
+			limited--
+			writeError(w, http.StatusForbidden, "You have exceeded a secondary rate limit. Please wait a few minutes before you try again.")
+			return true
+		}
+		return false
+	}
+	client, sleeps := newTestClient(t, fake)
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	if err := client.StarRepository(pkg); err != nil {
+		t.Fatalf("StarRepository() returned an error: %v", err)
+	}
+	if !fake.starred["charmbracelet/bubbles"] {
+		t.Error("Expected repository to be starred after retrying")
+	}
+	expected := []time.Duration{defaultSecondaryBackoff, 2 * defaultSecondaryBackoff}
+	if !slices.Equal(*sleeps, expected) {
+		t.Errorf("Expected exponential backoffs of %v, got %v", expected, *sleeps)
+	}
+}
+
+func TestSecondaryRateLimitBackoffFitsUnderCap(t *testing.T) {
+	transport := newRateLimitTransport(nil)
+	transport.jitter = func(d time.Duration) time.Duration { return d }
+
+	// Even with the most jitter, the backoffs of every retry fit in the total wait of a request
+	var total time.Duration
+	for attempt := 0; attempt < transport.maxRetries; attempt++ {
+		resp := &http.Response{
+			StatusCode: http.StatusForbidden,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader("You have exceeded a secondary rate limit.")),
+		}
+		wait, retry := transport.retryDelay(resp, attempt)
+		if !retry {
+			t.Fatalf("Expected attempt %d to be retried", attempt)
+		}
+		total += wait
+	}
+	if total > transport.maxRetryWait {
+		t.Errorf("Expected the backoffs to fit in %s, got %s", transport.maxRetryWait, total)
+	}
+}
+
+func TestSecondaryRateLimitKeepsLongBody(t *testing.T) {
+	body := `{"message": "You have exceeded a secondary rate limit.", "padding": "` + strings.Repeat("x", 100*1024) + `"}`
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader(body))}
+	if !isSecondaryRateLimit(resp) {
+		t.Fatal("Expected a secondary rate limit")
+	}
+	got, err := io.ReadAll(resp.Body)
+	if err != nil || string(got) != body {
+		t.Errorf("Expected the whole body of %d bytes to be readable, got %d bytes and error %v", len(body), len(got), err)
+	}
+}
+
+func TestSecondaryRateLimitFailsInsteadOfLongBackoff(t *testing.T) {
+	tests := []struct {
+		name          string
+		respond       func(w http.ResponseWriter)
+		expectedError string
+	}{
+		{
+			name: "Retry-After header",
+			respond: func(w http.ResponseWriter) {
+				w.Header().Set("Retry-After", "30")
+				writeError(w, http.StatusForbidden, "You have exceeded a secondary rate limit.")
+			},
+			expectedError: "retry after 30s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeGitHub()
+			fake.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+				tt.respond(w)
+				return true
+			}
+			client, sleeps := newTestClient(t, fake)
+
+			pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+			err := client.StarRepository(pkg)
+			if !errors.Is(err, ErrRateLimited) || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("StarRepository() error = %v, want ErrRateLimited with %q", err, tt.expectedError)
+			}
+			if len(*sleeps) != 0 {
+				t.Errorf("Expected no backoff longer than the cap, got %v", *sleeps)
+			}
+			if fake.requests != 1 {
+				t.Errorf("Expected 1 request, got %d", fake.requests)
+			}
+		})
+	}
+}
+
+func TestPrimaryRateLimitExhausted(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+
+	fake := newFakeGitHub()
+	fake.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Used", "5000")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.Header().Set("X-RateLimit-Resource", "core")
+		writeError(w, http.StatusForbidden, "API rate limit exceeded")
+		return true
+	}
+	client, sleeps := newTestClient(t, fake)
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	err := client.StarRepository(pkg)
+	if statusCode(err) != http.StatusForbidden {
+		t.Errorf("StarRepository() error = %v, want HTTP 403", err)
+	}
+
+	rateLimit, ok := client.RateLimit("core")
+	if !ok || rateLimit.Limit != 5000 || rateLimit.Remaining != 0 || rateLimit.Reset.Unix() != reset.Unix() {
+		t.Errorf("RateLimit() = %+v, %v", rateLimit, ok)
+	}
+
+	// Further requests fail without reaching the API until the limit resets
+	err = client.StarRepository(pkg)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("StarRepository() error = %v, want ErrRateLimited", err)
+	}
+	if fake.requests != 1 {
+		t.Errorf("Expected 1 request, got %d", fake.requests)
+	}
+	if len(*sleeps) != 0 {
+		t.Errorf("Expected no backoff for a distant reset, got %v", *sleeps)
+	}
+}
+
+func TestPrimaryRateLimitWaitsForImminentReset(t *testing.T) {
+	reset := time.Now().Add(5 * time.Second)
+
+	fake := newFakeGitHub()
+	exhausted := true
+	fake.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if !exhausted {
+			return false
+		}
+		exhausted = false
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		writeError(w, http.StatusForbidden, "API rate limit exceeded")
+		return true
+	}
+	client, sleeps := newTestClient(t, fake)
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	if err := client.StarRepository(pkg); err != nil {
+		t.Fatalf("StarRepository() returned an error: %v", err)
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] <= 0 || (*sleeps)[0] > 6*time.Second {
+		t.Errorf("Expected to wait for the rate limit reset, got %v", *sleeps)
+	}
+}
+
+func TestHasToken(t *testing.T) {
+	// Keep the user's gh config and tokens out of the test
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	for _, name := range []string{"GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN", "GH_HOST"} {
+		t.Setenv(name, "")
+	}
+
+	if HasToken() {
+		t.Error("Expected no token without environment or config")
+	}
+
+	// A GitHub Enterprise Server host set with GH_HOST uses its own token
+	t.Setenv("GH_HOST", "github.example.com")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "enterprise-token")
+	if !HasToken() {
+		t.Error("Expected the enterprise token to be found for GH_HOST")
+	}
+
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GH_TOKEN", "github-token")
+	t.Setenv("GH_HOST", "")
+	if !HasToken() {
+		t.Error("Expected the github.com token to be found")
+	}
+}
+
+func TestClientUsesUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "gh.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("Unix sockets are unavailable: %v", err)
+	}
+	fake := newFakeGitHub()
+	server := httptest.NewUnstartedServer(fake)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	// gh's http_unix_socket setting is kept when the client wraps the transport
+	client, err := NewClientWithOptions(api.ClientOptions{
+		Host:             "github.example.com",
+		AuthToken:        "test-token",
+		UnixDomainSocket: socket,
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithOptions() returned an error: %v", err)
+	}
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	if err := client.StarRepository(pkg); err != nil {
+		t.Fatalf("StarRepository() returned an error: %v", err)
+	}
+	if !fake.starred["charmbracelet/bubbles"] {
+		t.Error("Expected the request to go through the socket")
+	}
+}
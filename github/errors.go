@@ -0,0 +1,76 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// PackageError is an error that occurred while processing a single package
+type PackageError struct {
+	Package *model.Package
+	Err     error
+}
+
+// Error returns the error message prefixed with the package path
+func (e *PackageError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Package.Path, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *PackageError) Unwrap() error {
+	return e.Err
+}
+
+// PackageErrors collects the per-package failures of a bulk operation.
+// Packages that aren't listed were processed successfully.
+type PackageErrors []*PackageError
+
+// Error returns a summary of the failures
+func (e PackageErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d packages failed: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the individual package errors
+func (e PackageErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// errorOrNil returns nil when there are no failures, so that a nil PackageErrors
+// isn't returned as a non-nil error interface
+func (e PackageErrors) errorOrNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// statusCode returns the HTTP status code of a GitHub API error, or 0 if it isn't one
+func statusCode(err error) int {
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode
+	}
+	return 0
+}
+
+// isNotFound reports whether the error is a GitHub API 404 response
+func isNotFound(err error) bool {
+	return statusCode(err) == http.StatusNotFound
+}
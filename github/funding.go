@@ -0,0 +1,93 @@
+package github
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+	"gopkg.in/yaml.v3"
+)
+
+// fundingURLFormats maps the FUNDING.yml platforms to the URL of an account's funding page
+var fundingURLFormats = map[string]string{
+	"github":           "https://github.com/sponsors/%s",
+	"patreon":          "https://www.patreon.com/%s",
+	"open_collective":  "https://opencollective.com/%s",
+	"ko_fi":            "https://ko-fi.com/%s",
+	"tidelift":         "https://tidelift.com/funding/github/%s",
+	"community_bridge": "https://funding.communitybridge.org/projects/%s",
+	"liberapay":        "https://liberapay.com/%s",
+	"issuehunt":        "https://issuehunt.io/r/%s",
+	"lfx_crowdfunding": "https://crowdfunding.lfx.linuxfoundation.org/projects/%s",
+	"polar":            "https://polar.sh/%s",
+	"buy_me_a_coffee":  "https://buymeacoffee.com/%s",
+	"thanks_dev":       "https://thanks.dev/%s",
+}
+
+// ParseFundingFile parses a FUNDING.yml file into funding links.
+// Every platform accepts a single account or a list of accounts; unknown platforms are ignored.
+func ParseFundingFile(data []byte) ([]model.FundingLink, error) {
+	var file map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse FUNDING.yml: %w", err)
+	}
+
+	// Map iteration order is random, so sort the platforms for a stable result
+	platforms := make([]string, 0, len(file))
+	for platform := range file {
+		platforms = append(platforms, platform)
+	}
+	sort.Slice(platforms, func(i, j int) bool {
+		ri, rj := fundingPlatformRank(platforms[i]), fundingPlatformRank(platforms[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return platforms[i] < platforms[j]
+	})
+
+	var links []model.FundingLink
+	for _, platform := range platforms {
+		node := file[platform]
+		format, known := fundingURLFormats[platform]
+		if !known && platform != "custom" {
+			continue
+		}
+
+		var accounts []string
+		switch node.Kind {
+		case yaml.ScalarNode:
+			accounts = []string{node.Value}
+		case yaml.SequenceNode:
+			if err := node.Decode(&accounts); err != nil {
+				return nil, fmt.Errorf("failed to parse %s in FUNDING.yml: %w", platform, err)
+			}
+		}
+
+		for _, account := range accounts {
+			account = strings.TrimSpace(account)
+			if account == "" {
+				continue
+			}
+
+			url := account
+			if platform != "custom" {
+				url = fmt.Sprintf(format, account)
+			}
+			links = append(links, model.FundingLink{Platform: platform, Account: account, URL: url})
+		}
+	}
+
+	return links, nil
+}
+
+// fundingPlatformRank orders the funding platforms: GitHub Sponsors first, custom links last
+func fundingPlatformRank(platform string) int {
+	switch platform {
+	case "github":
+		return 0
+	case "custom":
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,42 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+func TestParseFundingFile(t *testing.T) {
+	data := []byte(`# These are supported funding model platforms
+custom: ["https://example.com/donate", "https://example.org/support"]
+github: [octocat, hubot]
+open_collective: charmbracelet
+patreon: # Replace with a single Patreon username
+unknown_platform: ignored
+`)
+
+	links, err := ParseFundingFile(data)
+	if err != nil {
+		t.Fatalf("ParseFundingFile() returned an error: %v", err)
+	}
+
+	expected := []model.FundingLink{
+		{Platform: "github", Account: "octocat", URL: "https://github.com/sponsors/octocat"},
+		{Platform: "github", Account: "hubot", URL: "https://github.com/sponsors/hubot"},
+		{Platform: "open_collective", Account: "charmbracelet", URL: "https://opencollective.com/charmbracelet"},
+		{Platform: "custom", Account: "https://example.com/donate", URL: "https://example.com/donate"},
+		{Platform: "custom", Account: "https://example.org/support", URL: "https://example.org/support"},
+	}
+	if len(links) != len(expected) {
+		t.Fatalf("ParseFundingFile() = %v, want %v", links, expected)
+	}
+	for i := range links {
+		if links[i] != expected[i] {
+			t.Errorf("ParseFundingFile()[%d] = %+v, want %+v", i, links[i], expected[i])
+		}
+	}
+
+	if _, err := ParseFundingFile([]byte("github: [unclosed")); err == nil {
+		t.Error("Expected an error for an invalid FUNDING.yml, got nil")
+	}
+}
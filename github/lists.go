@@ -0,0 +1,320 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// StarList is one of the user's GitHub star lists
+type StarList struct {
+	ID           string
+	Name         string
+	Description  string
+	IsPrivate    bool
+	Repositories []string // Repositories in the list (owner/repo)
+}
+
+// Contains reports whether the package's repository is in the list
+func (l *StarList) Contains(pkg *model.Package) bool {
+	repoPath := pkg.GitHubRepoPath()
+	for _, repo := range l.Repositories {
+		if strings.EqualFold(repo, repoPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// starListNode is the GraphQL representation of a star list
+type starListNode struct {
+	ID          string
+	Name        string
+	Description string
+	IsPrivate   bool
+	Items       starListItems
+}
+
+// starListItems is a page of repositories in a star list
+type starListItems struct {
+	Nodes []struct {
+		NameWithOwner string
+	}
+	PageInfo pageInfo
+}
+
+// pageInfo is the GraphQL pagination information of a connection
+type pageInfo struct {
+	HasNextPage bool
+	EndCursor   string
+}
+
+const starListFields = `id
+	name
+	description
+	isPrivate
+	items(first: 100) {
+		nodes { ... on Repository { nameWithOwner } }
+		pageInfo { hasNextPage endCursor }
+	}`
+
+// ListStarLists returns the authenticated user's star lists with the repositories in each
+func (c *Client) ListStarLists() ([]StarList, error) {
+	return c.listStarLists(c.graphQLClient)
+}
+
+// listStarLists lists the star lists with the GraphQL client, which may bypass the cache
+func (c *Client) listStarLists(graphQLClient *api.GraphQLClient) ([]StarList, error) {
+	query := `query($cursor: String) {
+	viewer {
+		lists(first: 100, after: $cursor) {
+			nodes { ` + starListFields + ` }
+			pageInfo { hasNextPage endCursor }
+		}
+	}
+}`
+
+	var lists []StarList
+	var cursor *string
+	for {
+		var response struct {
+			Viewer struct {
+				Lists struct {
+					Nodes    []starListNode
+					PageInfo pageInfo
+				}
+			}
+		}
+		if err := graphQLClient.Do(query, map[string]interface{}{"cursor": cursor}, &response); err != nil {
+			return nil, fmt.Errorf("failed to list star lists: %w", err)
+		}
+
+		for _, node := range response.Viewer.Lists.Nodes {
+			list, err := starListFromNode(graphQLClient, node)
+			if err != nil {
+				return nil, err
+			}
+			lists = append(lists, *list)
+		}
+
+		if !response.Viewer.Lists.PageInfo.HasNextPage {
+			return lists, nil
+		}
+		endCursor := response.Viewer.Lists.PageInfo.EndCursor
+		cursor = &endCursor
+	}
+}
+
+// FindStarList returns the star list with the given name, ignoring case
+func FindStarList(lists []StarList, name string) *StarList {
+	for i := range lists {
+		if strings.EqualFold(lists[i].Name, name) {
+			return &lists[i]
+		}
+	}
+	return nil
+}
+
+// starListFromNode converts a star list node, fetching the rest of its repositories if there are more than one page
+func starListFromNode(graphQLClient *api.GraphQLClient, node starListNode) (*StarList, error) {
+	list := &StarList{
+		ID:          node.ID,
+		Name:        node.Name,
+		Description: node.Description,
+		IsPrivate:   node.IsPrivate,
+	}
+
+	query := `query($id: ID!, $cursor: String) {
+	node(id: $id) {
+		... on UserList {
+			items(first: 100, after: $cursor) {
+				nodes { ... on Repository { nameWithOwner } }
+				pageInfo { hasNextPage endCursor }
+			}
+		}
+	}
+}`
+
+	items := node.Items
+	for {
+		for _, item := range items.Nodes {
+			// Items that aren't repositories decode with an empty name
+			if item.NameWithOwner != "" {
+				list.Repositories = append(list.Repositories, item.NameWithOwner)
+			}
+		}
+		if !items.PageInfo.HasNextPage {
+			return list, nil
+		}
+
+		var response struct {
+			Node struct {
+				Items starListItems
+			}
+		}
+		variables := map[string]interface{}{"id": node.ID, "cursor": items.PageInfo.EndCursor}
+		if err := graphQLClient.Do(query, variables, &response); err != nil {
+			return nil, fmt.Errorf("failed to list repositories in star list %s: %w", node.Name, err)
+		}
+		items = response.Node.Items
+	}
+}
+
+// CreateStarList creates a new star list
+func (c *Client) CreateStarList(name, description string, private bool) (*StarList, error) {
+	mutation := `mutation($name: String!, $description: String, $isPrivate: Boolean) {
+	createUserList(input: {name: $name, description: $description, isPrivate: $isPrivate}) {
+		list { id name description isPrivate }
+	}
+}`
+
+	var response struct {
+		CreateUserList struct {
+			List starListNode
+		}
+	}
+	variables := map[string]interface{}{
+		"name":        name,
+		"description": description,
+		"isPrivate":   private,
+	}
+	if err := c.graphQLClient.Do(mutation, variables, &response); err != nil {
+		return nil, fmt.Errorf("failed to create star list %s: %w", name, err)
+	}
+
+	node := response.CreateUserList.List
+	return &StarList{
+		ID:          node.ID,
+		Name:        node.Name,
+		Description: node.Description,
+		IsPrivate:   node.IsPrivate,
+	}, nil
+}
+
+// AddToStarList adds the packages' repositories to the star list.
+// Lists only hold starred repositories, so unstarred ones are starred first.
+func (c *Client) AddToStarList(list *StarList, packages []*model.Package) error {
+	return c.updateStarListMembership(list, packages, true)
+}
+
+// RemoveFromStarList removes the packages' repositories from the star list
+func (c *Client) RemoveFromStarList(list *StarList, packages []*model.Package) error {
+	return c.updateStarListMembership(list, packages, false)
+}
+
+// updateStarListMembership adds the packages' repositories to the list, or removes them from it
+func (c *Client) updateStarListMembership(list *StarList, packages []*model.Package, add bool) error {
+	var errs PackageErrors
+
+	// Repository node IDs come from the repository metadata
+	var missing []*model.Package
+	for _, pkg := range packages {
+		if pkg.IsGitHub && pkg.Repo == nil {
+			missing = append(missing, pkg)
+		}
+	}
+	if len(missing) > 0 {
+		// Packages that fail to fetch are reported below as having no metadata
+		_ = c.FetchRepositories(missing)
+	}
+
+	// The mutation replaces every list an item belongs to, so the current membership is needed.
+	// A cached one could be up to the star TTL old, and would drop lists changed on github.com since then.
+	lists, err := c.listStarLists(c.uncachedGraphQLClient)
+	if err != nil {
+		return err
+	}
+
+	byRepo := make(map[string][]*model.Package)
+	var repoPaths []string
+	for _, pkg := range packages {
+		switch {
+		case !pkg.IsGitHub:
+			errs = append(errs, &PackageError{Package: pkg, Err: fmt.Errorf("not a GitHub repository")})
+		case pkg.Repo == nil || pkg.Repo.NodeID == "":
+			errs = append(errs, &PackageError{Package: pkg, Err: ErrRepositoryNotFound})
+		default:
+			repoPath := pkg.GitHubRepoPath()
+			if _, ok := byRepo[repoPath]; !ok {
+				repoPaths = append(repoPaths, repoPath)
+			}
+			byRepo[repoPath] = append(byRepo[repoPath], pkg)
+		}
+	}
+
+	var mu sync.Mutex
+	c.forEach(len(repoPaths), func(i int) {
+		pkgs := byRepo[repoPaths[i]]
+		err := c.updateListsForRepository(pkgs[0], lists, list.ID, add)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			for _, pkg := range pkgs {
+				errs = append(errs, &PackageError{Package: pkg, Err: err})
+			}
+			return
+		}
+
+		if !add {
+			list.Repositories = removeRepository(list.Repositories, repoPaths[i])
+			return
+		}
+		for _, pkg := range pkgs {
+			pkg.IsStarred = true
+		}
+		if !list.Contains(pkgs[0]) {
+			list.Repositories = append(list.Repositories, repoPaths[i])
+		}
+	})
+
+	return errs.errorOrNil()
+}
+
+// updateListsForRepository sets the lists the repository belongs to, adding or removing the given list
+func (c *Client) updateListsForRepository(pkg *model.Package, lists []StarList, listID string, add bool) error {
+	if add && !pkg.IsStarred {
+		if err := c.StarRepository(pkg); err != nil {
+			return err
+		}
+	}
+
+	// An empty slice removes the repository from every list, so it must not be sent as null
+	listIDs := []string{}
+	for _, l := range lists {
+		if l.ID != listID && l.Contains(pkg) {
+			listIDs = append(listIDs, l.ID)
+		}
+	}
+	if add {
+		listIDs = append(listIDs, listID)
+	}
+
+	mutation := `mutation($itemId: ID!, $listIds: [ID!]!) {
+	updateUserListsForItem(input: {itemId: $itemId, listIds: $listIds}) { clientMutationId }
+}`
+	variables := map[string]interface{}{
+		"itemId":  pkg.Repo.NodeID,
+		"listIds": listIDs,
+	}
+
+	var response struct{}
+	if err := c.graphQLClient.Do(mutation, variables, &response); err != nil {
+		return fmt.Errorf("failed to update star lists of %s: %w", pkg.GitHubRepoPath(), err)
+	}
+	return nil
+}
+
+// removeRepository returns the repositories without the given one
+func removeRepository(repos []string, repoPath string) []string {
+	var result []string
+	for _, repo := range repos {
+		if !strings.EqualFold(repo, repoPath) {
+			result = append(result, repo)
+		}
+	}
+	return result
+}
@@ -0,0 +1,206 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// fakeStarLists serves the star list queries and mutations on top of the fake GitHub
+type fakeStarLists struct {
+	mu    sync.Mutex
+	names map[string]string          // list ID -> name
+	items map[string]map[string]bool // list ID -> repository node IDs
+}
+
+// install answers the star list requests of the fake GitHub
+func (l *fakeStarLists) install(fake *fakeGitHub) {
+	fake.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path != "/api/graphql" {
+			return false
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var req struct {
+			Query     string
+			Variables map[string]interface{}
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return false
+		}
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		var data interface{}
+		switch {
+		case strings.Contains(req.Query, "lists("):
+			var nodes []interface{}
+			for _, id := range l.sortedIDs() {
+				var items []interface{}
+				for item := range l.items[id] {
+					items = append(items, map[string]string{"nameWithOwner": strings.TrimPrefix(item, "R_")})
+				}
+				nodes = append(nodes, map[string]interface{}{
+					"id":    id,
+					"name":  l.names[id],
+					"items": map[string]interface{}{"nodes": items, "pageInfo": map[string]bool{"hasNextPage": false}},
+				})
+			}
+			data = map[string]interface{}{"viewer": map[string]interface{}{"lists": map[string]interface{}{
+				"nodes":    nodes,
+				"pageInfo": map[string]bool{"hasNextPage": false},
+			}}}
+		case strings.Contains(req.Query, "createUserList"):
+			id := "UL_" + req.Variables["name"].(string)
+			l.names[id] = req.Variables["name"].(string)
+			l.items[id] = make(map[string]bool)
+			data = map[string]interface{}{"createUserList": map[string]interface{}{
+				"list": map[string]string{"id": id, "name": l.names[id]},
+			}}
+		case strings.Contains(req.Query, "updateUserListsForItem"):
+			item := req.Variables["itemId"].(string)
+			for _, items := range l.items {
+				delete(items, item)
+			}
+			for _, id := range req.Variables["listIds"].([]interface{}) {
+				l.items[id.(string)][item] = true
+			}
+			data = map[string]interface{}{"updateUserListsForItem": map[string]interface{}{}}
+		default:
+			return false
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+		return true
+	}
+}
+
+// sortedIDs returns the list IDs in a stable order
+func (l *fakeStarLists) sortedIDs() []string {
+	var ids []string
+	for id := range l.names {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestStarLists(t *testing.T) {
+	fake := newFakeGitHub()
+	fake.missing["gone/away"] = true
+	lists := &fakeStarLists{
+		names: map[string]string{"UL_tui": "TUI"},
+		items: map[string]map[string]bool{"UL_tui": {"R_charmbracelet/bubbles": true}},
+	}
+	lists.install(fake)
+	client, _ := newTestClient(t, fake)
+
+	got, err := client.ListStarLists()
+	if err != nil {
+		t.Fatalf("ListStarLists() returned an error: %v", err)
+	}
+	tui := FindStarList(got, "tui")
+	if tui == nil {
+		t.Fatalf("Expected to find the TUI list, got %+v", got)
+	}
+
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	if !tui.Contains(bubbles) {
+		t.Errorf("Expected the TUI list to contain bubbles, got %v", tui.Repositories)
+	}
+
+	created, err := client.CreateStarList("Go deps", "", true)
+	if err != nil {
+		t.Fatalf("CreateStarList() returned an error: %v", err)
+	}
+
+	lipgloss := model.NewPackage("github.com/charmbracelet/lipgloss", "v1.0.0")
+	missing := model.NewPackage("github.com/gone/away", "v1.0.0")
+	err = client.AddToStarList(created, []*model.Package{bubbles, lipgloss, missing})
+
+	var pkgErrs PackageErrors
+	if !errors.As(err, &pkgErrs) || len(pkgErrs) != 1 || pkgErrs[0].Package != missing {
+		t.Fatalf("AddToStarList() error = %v, want an error for the missing repository only", err)
+	}
+	if !lipgloss.IsStarred || !fake.starred["charmbracelet/lipgloss"] {
+		t.Error("Expected repositories added to a list to be starred")
+	}
+	if !created.Contains(bubbles) || !created.Contains(lipgloss) {
+		t.Errorf("Expected the new list to contain both repositories, got %v", created.Repositories)
+	}
+
+	// Adding to one list keeps the repository in the others
+	if !lists.items["UL_tui"]["R_charmbracelet/bubbles"] || !lists.items["UL_Go deps"]["R_charmbracelet/bubbles"] {
+		t.Errorf("Expected bubbles to be in both lists, got %v", lists.items)
+	}
+
+	if err := client.RemoveFromStarList(created, []*model.Package{bubbles}); err != nil {
+		t.Fatalf("RemoveFromStarList() returned an error: %v", err)
+	}
+	if created.Contains(bubbles) || lists.items["UL_Go deps"]["R_charmbracelet/bubbles"] {
+		t.Error("Expected bubbles to be removed from the new list")
+	}
+	if !lists.items["UL_tui"]["R_charmbracelet/bubbles"] {
+		t.Error("Expected bubbles to stay in the TUI list")
+	}
+}
+
+func TestAddToStarListWithStaleCache(t *testing.T) {
+	fake := newFakeGitHub()
+	lists := &fakeStarLists{
+		names: map[string]string{"UL_tui": "TUI", "UL_web": "Web", "UL_go": "Go"},
+		items: map[string]map[string]bool{
+			"UL_tui": {"R_charmbracelet/bubbles": true},
+			"UL_web": {},
+			"UL_go":  {},
+		},
+	}
+	lists.install(fake)
+	server := httptest.NewTLSServer(fake)
+	defer server.Close()
+
+	client, err := NewClientWithCache(api.ClientOptions{
+		Host:      strings.TrimPrefix(server.URL, "https://"),
+		AuthToken: "test-token",
+		Transport: server.Client().Transport,
+	}, CacheOptions{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewClientWithCache() returned an error: %v", err)
+	}
+
+	cached, err := client.ListStarLists()
+	if err != nil {
+		t.Fatalf("ListStarLists() returned an error: %v", err)
+	}
+
+	// The repository is added to another list on github.com while the cached lists are still fresh
+	lists.mu.Lock()
+	lists.items["UL_web"]["R_charmbracelet/bubbles"] = true
+	lists.mu.Unlock()
+
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	bubbles.IsStarred = true
+	if err := client.AddToStarList(FindStarList(cached, "Go"), []*model.Package{bubbles}); err != nil {
+		t.Fatalf("AddToStarList() returned an error: %v", err)
+	}
+
+	for _, id := range []string{"UL_tui", "UL_web", "UL_go"} {
+		if !lists.items[id]["R_charmbracelet/bubbles"] {
+			t.Errorf("Expected bubbles to be in %s, got %v", id, lists.items)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package github
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// Readme is the README file of a repository
+type Readme struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Content string `json:"content"`
+	HTMLURL string `json:"html_url"`
+}
+
+// readmeResponse is the response of the README endpoint, with the content encoded
+type readmeResponse struct {
+	Readme
+	Encoding string `json:"encoding"`
+}
+
+// FetchReadme fetches the README of the package at its pinned version.
+// For a module in a subdirectory, the README of the subdirectory is preferred.
+// When the version can't be found, the README of the default branch is returned instead.
+// A repository without a README fails with ErrNoReadme, and a missing one with ErrRepositoryNotFound.
+func (c *Client) FetchReadme(pkg *model.Package) (*Readme, error) {
+	if !pkg.IsGitHub {
+		return nil, fmt.Errorf("not a GitHub repository: %s", pkg.Path)
+	}
+
+	repoPath := pkg.GitHubRepoPath()
+	if repoPath == "" {
+		return nil, fmt.Errorf("invalid GitHub repository path: %s", pkg.Path)
+	}
+
+	dir := pkg.GitHubSubdir()
+	ref := pkg.GitRef()
+
+	var attempts []string
+	if dir != "" {
+		attempts = append(attempts, fmt.Sprintf("repos/%s/readme/%s?ref=%s", repoPath, dir, url.QueryEscape(ref)))
+	}
+	attempts = append(attempts, fmt.Sprintf("repos/%s/readme?ref=%s", repoPath, url.QueryEscape(ref)))
+	attempts = append(attempts, fmt.Sprintf("repos/%s/readme", repoPath))
+
+	var err error
+	for _, path := range attempts {
+		var resp readmeResponse
+		err = c.getJSON(path, &resp)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch the README of %s: %w", repoPath, err)
+		}
+		return decodeReadme(resp)
+	}
+
+	// The README endpoint answers 404 for both a missing README and a missing repository
+	var repo struct{}
+	err = c.getJSON("repos/"+repoPath, &repo)
+	if isNotFound(err) {
+		err = ErrRepositoryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the README of %s: %w", repoPath, err)
+	}
+	return nil, fmt.Errorf("failed to fetch the README of %s: %w", repoPath, ErrNoReadme)
+}
+
+// decodeReadme decodes the content of the README
+func decodeReadme(resp readmeResponse) (*Readme, error) {
+	if resp.Encoding != "base64" {
+		return &resp.Readme, nil
+	}
+
+	// The content is wrapped at 60 characters
+	content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(resp.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", resp.Name, err)
+	}
+	resp.Readme.Content = string(content)
+	return &resp.Readme, nil
+}
@@ -0,0 +1,103 @@
+package github
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// serveReadmes answers README requests with the READMEs keyed by request path and ref
+func serveReadmes(readmes map[string]string) func(w http.ResponseWriter, r *http.Request) bool {
+	return func(w http.ResponseWriter, r *http.Request) bool {
+		content, ok := readmes[r.URL.Path+"?ref="+r.URL.Query().Get("ref")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return true
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"name":     "README.md",
+			"path":     "README.md",
+			"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+			"encoding": "base64",
+		})
+		return true
+	}
+}
+
+func TestFetchReadme(t *testing.T) {
+	fake := newFakeGitHub()
+	fake.intercept = serveReadmes(map[string]string{
+		"/api/v3/repos/charmbracelet/bubbles/readme?ref=v0.20.0":     "# Bubbles v0.20.0",
+		"/api/v3/repos/charmbracelet/x/readme/ansi?ref=ansi/v0.10.1": "# ansi",
+		"/api/v3/repos/owner/repo/readme?ref=":                       "# Default branch",
+		"/api/v3/repos/owner/pseudo/readme?ref=0123456789ab":         "# Commit",
+		"/api/v3/repos/owner/incompatible/readme?ref=v3.0.0":         "# Incompatible",
+		"/api/v3/repos/owner/major/readme?ref=v2.1.0":                "# Major",
+		"/api/v3/repos/owner/bare?ref=":                              "",
+	})
+	client, _ := newTestClient(t, fake)
+
+	tests := []struct {
+		name     string
+		pkg      *model.Package
+		expected string
+	}{
+		{
+			name:     "Tagged version",
+			pkg:      model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0"),
+			expected: "# Bubbles v0.20.0",
+		},
+		{
+			name:     "Module in a subdirectory",
+			pkg:      model.NewPackage("github.com/charmbracelet/x/ansi", "v0.10.1"),
+			expected: "# ansi",
+		},
+		{
+			name:     "Missing tag falls back to the default branch",
+			pkg:      model.NewPackage("github.com/owner/repo", "v9.9.9"),
+			expected: "# Default branch",
+		},
+		{
+			name:     "Pseudo-version",
+			pkg:      model.NewPackage("github.com/owner/pseudo", "v0.0.0-20240101000000-0123456789ab"),
+			expected: "# Commit",
+		},
+		{
+			name:     "Incompatible version",
+			pkg:      model.NewPackage("github.com/owner/incompatible", "v3.0.0+incompatible"),
+			expected: "# Incompatible",
+		},
+		{
+			name:     "Major version suffix",
+			pkg:      model.NewPackage("github.com/owner/major/v2", "v2.1.0"),
+			expected: "# Major",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readme, err := client.FetchReadme(tt.pkg)
+			if err != nil {
+				t.Fatalf("FetchReadme() returned an error: %v", err)
+			}
+			if readme.Content != tt.expected {
+				t.Errorf("FetchReadme() content = %q, want %q", readme.Content, tt.expected)
+			}
+		})
+	}
+
+	if _, err := client.FetchReadme(model.NewPackage("github.com/owner/bare", "v1.0.0")); !errors.Is(err, ErrNoReadme) {
+		t.Errorf("FetchReadme() error = %v, want ErrNoReadme for a repository without README", err)
+	}
+	if _, err := client.FetchReadme(model.NewPackage("github.com/gone/away", "v1.0.0")); !errors.Is(err, ErrRepositoryNotFound) {
+		t.Errorf("FetchReadme() error = %v, want ErrRepositoryNotFound for a missing repository", err)
+	}
+	if _, err := client.FetchReadme(model.NewPackage("golang.org/x/mod", "v0.27.0")); err == nil {
+		t.Error("Expected an error for a non-GitHub package")
+	}
+}
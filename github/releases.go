@@ -0,0 +1,125 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+	"golang.org/x/mod/semver"
+)
+
+// maxReleasePages limits how many pages of releases are fetched for a repository
+const maxReleasePages = 5
+
+// Release represents a GitHub release
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	HTMLURL     string    `json:"html_url"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// ListReleases returns the releases published after the package's pinned version
+// up to and including its latest version, newest first
+func (c *Client) ListReleases(pkg *model.Package) ([]Release, error) {
+	if !pkg.IsGitHub {
+		return nil, fmt.Errorf("not a GitHub repository: %s", pkg.Path)
+	}
+
+	repoPath := pkg.GitHubRepoPath()
+	if repoPath == "" {
+		return nil, fmt.Errorf("invalid GitHub repository path: %s", pkg.Path)
+	}
+
+	var releases []Release
+	for page := 1; page <= maxReleasePages; page++ {
+		var pageReleases []Release
+		err := c.getJSON(fmt.Sprintf("repos/%s/releases?per_page=100&page=%d", repoPath, page), &pageReleases)
+		if isNotFound(err) {
+			err = ErrRepositoryNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list releases for %s: %w", repoPath, err)
+		}
+
+		releases = append(releases, pageReleases...)
+
+		// Releases are returned newest first, so stop once the pinned version is reached
+		if len(pageReleases) < 100 || containsTag(pageReleases, pkg.GitRef()) {
+			break
+		}
+	}
+
+	return FilterReleases(releases, pkg.GitHubSubdir(), pkg.Version, pkg.LatestVersion), nil
+}
+
+// FilterReleases returns the releases whose tags are newer than the pinned version
+// and not newer than the latest version, sorted newest first.
+// A module in a subdirectory is tagged with the directory as a prefix, such as sub/v1.2.3,
+// so only tags with that prefix are kept. Drafts and tags that aren't semantic versions are skipped.
+// An empty latest version doesn't bound the releases.
+func FilterReleases(releases []Release, subdir, pinned, latest string) []Release {
+	var filtered []Release
+	for _, release := range releases {
+		version, ok := tagVersion(release.TagName, subdir)
+		if release.Draft || !ok {
+			continue
+		}
+		if semver.Compare(version, pinned) <= 0 {
+			continue
+		}
+		if latest != "" && semver.Compare(version, latest) > 0 {
+			continue
+		}
+		filtered = append(filtered, release)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		vi, _ := tagVersion(filtered[i].TagName, subdir)
+		vj, _ := tagVersion(filtered[j].TagName, subdir)
+		return semver.Compare(vi, vj) > 0
+	})
+
+	return filtered
+}
+
+// tagVersion returns the version of a tag of the module in the subdirectory.
+// It reports false for tags of other directories and tags that aren't semantic versions.
+func tagVersion(tag, subdir string) (string, bool) {
+	if subdir != "" {
+		var ok bool
+		if tag, ok = strings.CutPrefix(tag, subdir+"/"); !ok {
+			return "", false
+		}
+	}
+	return tag, semver.IsValid(tag)
+}
+
+// containsTag reports whether any of the releases is tagged with the given version
+func containsTag(releases []Release, tag string) bool {
+	for _, release := range releases {
+		if strings.EqualFold(release.TagName, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// getJSON issues a GET request and decodes the JSON response
+func (c *Client) getJSON(path string, v interface{}) error {
+	resp, err := c.restClient.Request("GET", path, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
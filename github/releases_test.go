@@ -0,0 +1,80 @@
+package github
+
+import (
+	"testing"
+)
+
+func TestFilterReleases(t *testing.T) {
+	releases := []Release{
+		{TagName: "v1.0.0"},
+		{TagName: "v1.3.0"},
+		{TagName: "v1.1.0"},
+		{TagName: "v1.2.0-rc.1", Prerelease: true},
+		{TagName: "v1.2.0"},
+		{TagName: "v1.4.0", Draft: true},
+		{TagName: "nightly"},
+		{TagName: "v2.0.0"},
+	}
+
+	tests := []struct {
+		name     string
+		pinned   string
+		latest   string
+		expected []string
+	}{
+		{
+			name:     "Between pinned and latest",
+			pinned:   "v1.0.0",
+			latest:   "v1.3.0",
+			expected: []string{"v1.3.0", "v1.2.0", "v1.2.0-rc.1", "v1.1.0"},
+		},
+		{
+			name:     "Unknown latest version",
+			pinned:   "v1.2.0",
+			latest:   "",
+			expected: []string{"v2.0.0", "v1.3.0"},
+		},
+		{
+			name:     "Already at latest version",
+			pinned:   "v1.3.0",
+			latest:   "v1.3.0",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterReleases(releases, "", tt.pinned, tt.latest)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("FilterReleases() returned %d releases, want %d: %v", len(got), len(tt.expected), got)
+			}
+			for i, release := range got {
+				if release.TagName != tt.expected[i] {
+					t.Errorf("FilterReleases()[%d] = %s, want %s", i, release.TagName, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilterReleasesInSubdirectory(t *testing.T) {
+	releases := []Release{
+		{TagName: "v1.5.0"},
+		{TagName: "sub/v1.0.0"},
+		{TagName: "sub/v1.2.0"},
+		{TagName: "sub/v1.1.0"},
+		{TagName: "other/v1.3.0"},
+		{TagName: "sub/nested/v1.4.0"},
+	}
+
+	got := FilterReleases(releases, "sub", "v1.0.0", "v1.2.0")
+	expected := []string{"sub/v1.2.0", "sub/v1.1.0"}
+	if len(got) != len(expected) {
+		t.Fatalf("FilterReleases() returned %d releases, want %d: %v", len(got), len(expected), got)
+	}
+	for i, release := range got {
+		if release.TagName != expected[i] {
+			t.Errorf("FilterReleases()[%d] = %s, want %s", i, release.TagName, expected[i])
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// repoBatchSize is the number of repositories fetched in a single GraphQL query
+const repoBatchSize = 50
+
+// repoFieldsFragment selects the repository fields fetched for every dependency
+const repoFieldsFragment = `fragment repoFields on Repository {
+	id
+	stargazerCount
+	forkCount
+	pushedAt
+	isArchived
+	isDisabled
+	issues(states: OPEN) { totalCount }
+	primaryLanguage { name }
+	licenseInfo { spdxId name }
+	description
+	owner {
+		login
+		... on Sponsorable { hasSponsorsListing }
+	}
+	fundingFile: object(expression: "HEAD:.github/FUNDING.yml") {
+		... on Blob { text }
+	}
+}`
+
+// viewerFieldsFragment selects the viewer's star and watch status of a repository.
+// It's fetched in its own query so that the cache can expire it sooner than the metadata.
+const viewerFieldsFragment = `fragment viewerFields on Repository {
+	viewerHasStarred
+	viewerSubscription
+}`
+
+// repositoryNode is the GraphQL representation of a repository
+type repositoryNode struct {
+	ID             string
+	StargazerCount int
+	ForkCount      int
+	PushedAt       time.Time
+	IsArchived     bool
+	IsDisabled     bool
+	Issues         struct {
+		TotalCount int
+	}
+	PrimaryLanguage *struct {
+		Name string
+	}
+	LicenseInfo *struct {
+		SpdxID string `json:"spdxId"`
+		Name   string
+	}
+	Description string
+	Owner       struct {
+		Login              string
+		HasSponsorsListing bool
+	}
+	FundingFile *struct {
+		Text string
+	}
+}
+
+// viewerNode is the GraphQL representation of the viewer's status of a repository
+type viewerNode struct {
+	ViewerHasStarred   bool
+	ViewerSubscription string
+}
+
+// FetchRepositories fetches the metadata and star status of the packages' GitHub repositories,
+// batching up to repoBatchSize repositories into a single GraphQL query and running batches in parallel.
+// Packages whose repository couldn't be fetched are reported as PackageErrors.
+func (c *Client) FetchRepositories(packages []*model.Package) error {
+	return c.fetchRepositories(packages, true)
+}
+
+// fetchRepositories fetches the star status of the packages' GitHub repositories, and their metadata if requested.
+// The metadata and the star status are separate queries, so that each is cached for its own TTL.
+func (c *Client) fetchRepositories(packages []*model.Package, withMetadata bool) error {
+	// Several modules can live in the same repository
+	byRepo := make(map[string][]*model.Package)
+	var repoPaths []string
+	for _, pkg := range packages {
+		repoPath := pkg.GitHubRepoPath()
+		if repoPath == "" || strings.Count(repoPath, "/") != 1 {
+			continue
+		}
+		if _, ok := byRepo[repoPath]; !ok {
+			repoPaths = append(repoPaths, repoPath)
+		}
+		byRepo[repoPath] = append(byRepo[repoPath], pkg)
+	}
+
+	var batches [][]string
+	for start := 0; start < len(repoPaths); start += repoBatchSize {
+		end := start + repoBatchSize
+		if end > len(repoPaths) {
+			end = len(repoPaths)
+		}
+		batches = append(batches, repoPaths[start:end])
+	}
+
+	var (
+		mu   sync.Mutex
+		errs PackageErrors
+	)
+	c.forEach(len(batches), func(i int) {
+		batch := batches[i]
+		var nodes map[string]*repositoryNode
+		var metadataErr error
+		if withMetadata {
+			nodes, metadataErr = queryRepositories[repositoryNode](c, batch, "repoFields", repoFieldsFragment)
+		}
+		viewerNodes, viewerErr := queryRepositories[viewerNode](c, batch, "viewerFields", viewerFieldsFragment)
+
+		mu.Lock()
+		defer mu.Unlock()
+		for j, repoPath := range batch {
+			alias := repoAlias(j)
+			for _, pkg := range byRepo[repoPath] {
+				switch {
+				case metadataErr != nil:
+					errs = append(errs, &PackageError{Package: pkg, Err: fmt.Errorf("failed to fetch repository %s: %w", repoPath, metadataErr)})
+					continue
+				case withMetadata && nodes[alias] == nil:
+					errs = append(errs, &PackageError{Package: pkg, Err: fmt.Errorf("failed to fetch repository %s: %w", repoPath, ErrRepositoryNotFound)})
+					continue
+				case withMetadata:
+					applyRepositoryNode(pkg, nodes[alias])
+				}
+
+				switch {
+				case viewerErr != nil:
+					errs = append(errs, &PackageError{Package: pkg, Err: fmt.Errorf("failed to fetch star status of %s: %w", repoPath, viewerErr)})
+				case viewerNodes[alias] == nil:
+					// A missing repository is already reported with its metadata
+					if !withMetadata {
+						errs = append(errs, &PackageError{Package: pkg, Err: fmt.Errorf("failed to fetch star status of %s: %w", repoPath, ErrRepositoryNotFound)})
+					}
+				default:
+					applyViewerNode(pkg, viewerNodes[alias])
+				}
+			}
+		}
+	})
+
+	return errs.errorOrNil()
+}
+
+// queryRepositories fetches the fields of the fragment for a batch of repositories in one aliased GraphQL query.
+// Repositories that can't be found are missing from the result.
+func queryRepositories[T any](c *Client, repoPaths []string, fragmentName, fragment string) (map[string]*T, error) {
+	query, variables := buildRepositoriesQuery(repoPaths, fragmentName, fragment)
+
+	var response map[string]*T
+	err := c.graphQLClient.Do(query, variables, &response)
+
+	var gqlErr *api.GraphQLError
+	if errors.As(err, &gqlErr) && onlyNotFoundErrors(gqlErr) {
+		// Deleted or renamed repositories are reported as errors alongside the partial data
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// buildRepositoriesQuery builds an aliased GraphQL query fetching the fragment of every repository
+func buildRepositoriesQuery(repoPaths []string, fragmentName, fragment string) (string, map[string]interface{}) {
+	var params, fields []string
+	variables := make(map[string]interface{}, len(repoPaths)*2)
+
+	for i, repoPath := range repoPaths {
+		owner, name, _ := strings.Cut(repoPath, "/")
+		params = append(params, fmt.Sprintf("$owner%d: String!, $name%d: String!", i, i))
+		fields = append(fields, fmt.Sprintf("\t%s: repository(owner: $owner%d, name: $name%d) { ...%s }", repoAlias(i), i, i, fragmentName))
+		variables[fmt.Sprintf("owner%d", i)] = owner
+		variables[fmt.Sprintf("name%d", i)] = name
+	}
+
+	query := fmt.Sprintf("query(%s) {\n%s\n}\n%s", strings.Join(params, ", "), strings.Join(fields, "\n"), fragment)
+	return query, variables
+}
+
+// repoAlias returns the GraphQL alias of the i-th repository in a batch
+func repoAlias(i int) string {
+	return fmt.Sprintf("repo%d", i)
+}
+
+// onlyNotFoundErrors reports whether every GraphQL error is a NOT_FOUND error
+func onlyNotFoundErrors(err *api.GraphQLError) bool {
+	for _, item := range err.Errors {
+		if item.Type != "NOT_FOUND" {
+			return false
+		}
+	}
+	return true
+}
+
+// applyRepositoryNode copies the fetched repository fields into the package
+func applyRepositoryNode(pkg *model.Package, node *repositoryNode) {
+	repo := &model.RepoMetadata{
+		NodeID:      node.ID,
+		Stars:       node.StargazerCount,
+		Forks:       node.ForkCount,
+		OpenIssues:  node.Issues.TotalCount,
+		PushedAt:    node.PushedAt,
+		Archived:    node.IsArchived,
+		Disabled:    node.IsDisabled,
+		Description: node.Description,
+	}
+	if node.PrimaryLanguage != nil {
+		repo.Language = node.PrimaryLanguage.Name
+	}
+	if node.LicenseInfo != nil {
+		// Licenses GitHub can't identify have no SPDX ID, only a name such as "Other"
+		repo.License = node.LicenseInfo.SpdxID
+		if repo.License == "" || repo.License == "NOASSERTION" {
+			repo.License = node.LicenseInfo.Name
+		}
+	}
+
+	repo.Owner = node.Owner.Login
+	repo.OwnerSponsorable = node.Owner.HasSponsorsListing
+	if node.FundingFile != nil {
+		// A malformed FUNDING.yml only means there are no funding links to show
+		repo.FundingLinks, _ = ParseFundingFile([]byte(node.FundingFile.Text))
+	}
+
+	pkg.Repo = repo
+}
+
+// applyViewerNode copies the viewer's star and watch status into the package
+func applyViewerNode(pkg *model.Package, node *viewerNode) {
+	pkg.IsStarred = node.ViewerHasStarred
+	pkg.IsWatched = node.ViewerSubscription == "SUBSCRIBED"
+	pkg.IsIgnored = node.ViewerSubscription == "IGNORED"
+}
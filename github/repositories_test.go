@@ -0,0 +1,123 @@
+package github
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+func TestBuildRepositoriesQuery(t *testing.T) {
+	query, variables := buildRepositoriesQuery([]string{"charmbracelet/bubbles", "cli/go-gh"}, "repoFields", repoFieldsFragment)
+
+	expected := []string{
+		"query($owner0: String!, $name0: String!, $owner1: String!, $name1: String!)",
+		"repo0: repository(owner: $owner0, name: $name0) { ...repoFields }",
+		"repo1: repository(owner: $owner1, name: $name1) { ...repoFields }",
+		"fragment repoFields on Repository",
+	}
+	for _, e := range expected {
+		if !strings.Contains(query, e) {
+			t.Errorf("Expected query to contain %q, but it didn't.\nGot: %s", e, query)
+		}
+	}
+
+	// The star status is a separate query, so that the metadata is cached for the metadata TTL
+	if strings.Contains(query, "viewer") {
+		t.Errorf("Expected the metadata query not to fetch the viewer's status.\nGot: %s", query)
+	}
+	viewerQuery, _ := buildRepositoriesQuery([]string{"charmbracelet/bubbles"}, "viewerFields", viewerFieldsFragment)
+	if !strings.Contains(viewerQuery, "{ ...viewerFields }") || !strings.Contains(viewerQuery, "viewerHasStarred") {
+		t.Errorf("Expected the viewer query to fetch the star status.\nGot: %s", viewerQuery)
+	}
+
+	expectedVariables := map[string]string{
+		"owner0": "charmbracelet",
+		"name0":  "bubbles",
+		"owner1": "cli",
+		"name1":  "go-gh",
+	}
+	if len(variables) != len(expectedVariables) {
+		t.Errorf("Expected %d variables, got %d", len(expectedVariables), len(variables))
+	}
+	for k, v := range expectedVariables {
+		if variables[k] != v {
+			t.Errorf("variables[%q] = %v, want %v", k, variables[k], v)
+		}
+	}
+}
+
+func TestApplyRepositoryNode(t *testing.T) {
+	node := &repositoryNode{
+		StargazerCount: 100,
+		ForkCount:      10,
+		PushedAt:       time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		IsArchived:     true,
+		Description:    "A library",
+	}
+	node.Issues.TotalCount = 5
+	node.PrimaryLanguage = &struct{ Name string }{Name: "Go"}
+	node.LicenseInfo = &struct {
+		SpdxID string `json:"spdxId"`
+		Name   string
+	}{SpdxID: "MIT", Name: "MIT License"}
+	node.Owner.Login = "charmbracelet"
+	node.Owner.HasSponsorsListing = true
+	node.FundingFile = &struct{ Text string }{Text: "open_collective: charmbracelet\n"}
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	applyRepositoryNode(pkg, node)
+
+	expected := model.RepoMetadata{
+		Stars:            100,
+		Forks:            10,
+		OpenIssues:       5,
+		PushedAt:         time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		Archived:         true,
+		Language:         "Go",
+		License:          "MIT",
+		Description:      "A library",
+		Owner:            "charmbracelet",
+		OwnerSponsorable: true,
+		FundingLinks: []model.FundingLink{
+			{Platform: "open_collective", Account: "charmbracelet", URL: "https://opencollective.com/charmbracelet"},
+		},
+	}
+	if pkg.Repo == nil || !reflect.DeepEqual(*pkg.Repo, expected) {
+		t.Errorf("Repo = %+v, want %+v", pkg.Repo, expected)
+	}
+}
+
+func TestApplyViewerNode(t *testing.T) {
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	applyViewerNode(pkg, &viewerNode{ViewerHasStarred: true, ViewerSubscription: "SUBSCRIBED"})
+	if !pkg.IsStarred || !pkg.IsWatched {
+		t.Errorf("Expected package to be starred and watched, got starred=%v watched=%v", pkg.IsStarred, pkg.IsWatched)
+	}
+
+	applyViewerNode(pkg, &viewerNode{ViewerSubscription: "UNSUBSCRIBED"})
+	if pkg.IsStarred || pkg.IsWatched || pkg.IsIgnored {
+		t.Errorf("Expected package not to be starred, watched or ignored, got %+v", pkg)
+	}
+
+	// Ignoring is kept apart from not watching, so that watching all doesn't override it
+	applyViewerNode(pkg, &viewerNode{ViewerSubscription: "IGNORED"})
+	if pkg.IsWatched || !pkg.IsIgnored {
+		t.Errorf("Expected package to be ignored, got watched=%v ignored=%v", pkg.IsWatched, pkg.IsIgnored)
+	}
+}
+
+func TestOnlyNotFoundErrors(t *testing.T) {
+	notFound := &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "NOT_FOUND"}}}
+	if !onlyNotFoundErrors(notFound) {
+		t.Error("Expected NOT_FOUND errors to be tolerated")
+	}
+
+	mixed := &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "NOT_FOUND"}, {Type: "FORBIDDEN"}}}
+	if onlyNotFoundErrors(mixed) {
+		t.Error("Expected other errors not to be tolerated")
+	}
+}
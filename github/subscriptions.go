@@ -0,0 +1,103 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// Watching only releases is a custom subscription that GitHub doesn't expose through
+// the REST or GraphQL APIs, so repositories are watched for all activity.
+// Users who only want release notifications can switch to "Custom > Releases" on the repository page.
+
+// WatchRepository subscribes the authenticated user to the repository's notifications
+func (c *Client) WatchRepository(pkg *model.Package) error {
+	repoPath, err := subscriptionRepoPath(pkg)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]bool{"subscribed": true})
+	if err != nil {
+		return err
+	}
+
+	err = c.doSubscription("PUT", repoPath, bytes.NewReader(body))
+	if isNotFound(err) {
+		err = ErrRepositoryNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to watch repository %s: %w", repoPath, err)
+	}
+
+	pkg.IsWatched = true
+	pkg.IsIgnored = false
+	return nil
+}
+
+// UnwatchRepository unsubscribes the authenticated user from the repository's notifications
+func (c *Client) UnwatchRepository(pkg *model.Package) error {
+	repoPath, err := subscriptionRepoPath(pkg)
+	if err != nil {
+		return err
+	}
+
+	err = c.doSubscription("DELETE", repoPath, nil)
+	if isNotFound(err) {
+		err = ErrRepositoryNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unwatch repository %s: %w", repoPath, err)
+	}
+
+	pkg.IsWatched = false
+	pkg.IsIgnored = false
+	return nil
+}
+
+// WatchAllUnwatched watches all unwatched GitHub repositories in parallel.
+// Repositories the user chose to ignore are left as they are.
+// It returns the number of packages watched, and PackageErrors for those that failed.
+func (c *Client) WatchAllUnwatched(packages []*model.Package) (int, error) {
+	return c.forEachRepository(packages, func(pkg *model.Package) bool {
+		return !pkg.IsWatched && !pkg.IsIgnored
+	}, func(pkgs []*model.Package) error {
+		if err := c.WatchRepository(pkgs[0]); err != nil {
+			return err
+		}
+		for _, pkg := range pkgs {
+			pkg.IsWatched = true
+			pkg.IsIgnored = false
+		}
+		return nil
+	})
+}
+
+// subscriptionRepoPath returns the repository path of the package to manage the subscription of
+func subscriptionRepoPath(pkg *model.Package) (string, error) {
+	if !pkg.IsGitHub {
+		return "", fmt.Errorf("not a GitHub repository: %s", pkg.Path)
+	}
+
+	repoPath := pkg.GitHubRepoPath()
+	if repoPath == "" {
+		return "", fmt.Errorf("invalid GitHub repository path: %s", pkg.Path)
+	}
+	return repoPath, nil
+}
+
+// doSubscription sends a request to the subscription endpoint of the repository
+func (c *Client) doSubscription(method, repoPath string, body io.Reader) error {
+	resp, err := c.restClient.Request(method, fmt.Sprintf("repos/%s/subscription", repoPath), body)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+func TestWatchAndUnwatchRepository(t *testing.T) {
+	fake := newFakeGitHub()
+	fake.missing["gone/away"] = true
+	client, _ := newTestClient(t, fake)
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	if err := client.WatchRepository(pkg); err != nil {
+		t.Fatalf("WatchRepository() returned an error: %v", err)
+	}
+	if !pkg.IsWatched || !fake.watched["charmbracelet/bubbles"] {
+		t.Error("Expected repository to be watched")
+	}
+
+	// The watch state is read back with the repository metadata
+	pkg.IsWatched = false
+	if err := client.FetchRepositories([]*model.Package{pkg}); err != nil {
+		t.Fatalf("FetchRepositories() returned an error: %v", err)
+	}
+	if !pkg.IsWatched {
+		t.Error("Expected the fetched repository to be watched")
+	}
+
+	if err := client.UnwatchRepository(pkg); err != nil {
+		t.Fatalf("UnwatchRepository() returned an error: %v", err)
+	}
+	if pkg.IsWatched || fake.watched["charmbracelet/bubbles"] {
+		t.Error("Expected repository to be unwatched")
+	}
+
+	missing := model.NewPackage("github.com/gone/away", "v1.0.0")
+	if err := client.WatchRepository(missing); !errors.Is(err, ErrRepositoryNotFound) {
+		t.Errorf("WatchRepository() error = %v, want ErrRepositoryNotFound", err)
+	}
+
+	notGitHub := model.NewPackage("golang.org/x/mod", "v0.27.0")
+	if err := client.WatchRepository(notGitHub); err == nil {
+		t.Error("Expected an error when watching a non-GitHub package, got nil")
+	}
+}
+
+func TestWatchAllUnwatched(t *testing.T) {
+	fake := newFakeGitHub()
+	fake.failing["broken/repo"] = http.StatusUnprocessableEntity
+	client, _ := newTestClient(t, fake)
+
+	watched := model.NewPackage("github.com/owner/watched", "v1.0.0")
+	watched.IsWatched = true
+	ignored := model.NewPackage("github.com/owner/ignored", "v1.0.0")
+	ignored.IsIgnored = true
+	broken := model.NewPackage("github.com/broken/repo", "v1.0.0")
+	packages := []*model.Package{
+		model.NewPackage("github.com/owner/repo", "v1.0.0"),
+		model.NewPackage("github.com/owner/repo/v2", "v2.0.0"),
+		watched,
+		ignored,
+		broken,
+		model.NewPackage("golang.org/x/mod", "v0.27.0"),
+	}
+
+	count, err := client.WatchAllUnwatched(packages)
+	if count != 2 {
+		t.Errorf("WatchAllUnwatched() count = %d, want 2", count)
+	}
+
+	var pkgErrs PackageErrors
+	if !errors.As(err, &pkgErrs) || len(pkgErrs) != 1 || pkgErrs[0].Package != broken {
+		t.Errorf("WatchAllUnwatched() error = %v, want an error for the broken repository only", err)
+	}
+	if !packages[0].IsWatched || !packages[1].IsWatched {
+		t.Error("Expected both modules in the same repository to be watched")
+	}
+	if fake.watched["owner/watched"] {
+		t.Error("Expected already watched repositories to be skipped")
+	}
+	if fake.watched["owner/ignored"] || ignored.IsWatched {
+		t.Error("Expected ignored repositories to be skipped")
+	}
+}
@@ -0,0 +1,285 @@
+package github
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/config"
+)
+
+// ErrRateLimited is returned when the primary rate limit is exhausted and won't reset soon enough
+// to wait for it, or when a rate limit asks to back off for longer than a request may block
+var ErrRateLimited = errors.New("GitHub API rate limit exceeded")
+
+// Retry and backoff settings for the rate limit aware transport
+const (
+	defaultMaxRetries         = 3
+	defaultSecondaryBackoff   = time.Second
+	defaultServerErrorBackoff = time.Second
+	defaultMaxResetWait       = 10 * time.Second
+	defaultMaxRetryWait       = 10 * time.Second
+)
+
+// RateLimit is the primary rate limit state reported by the GitHub API for a resource
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Used      int
+	Reset     time.Time
+}
+
+// rateLimitTransport is an http.RoundTripper that tracks the X-RateLimit-* headers,
+// waits for the primary rate limit to reset, and retries requests that hit a
+// secondary rate limit or a transient server error
+type rateLimitTransport struct {
+	transport          http.RoundTripper
+	maxRetries         int
+	secondaryBackoff   time.Duration
+	serverErrorBackoff time.Duration
+	maxResetWait       time.Duration
+	maxRetryWait       time.Duration // Total backoff of a request, after which it fails instead of blocking the caller
+	sleep              func(time.Duration)
+	jitter             func(time.Duration) time.Duration // Random extra wait of up to the duration
+	now                func() time.Time
+
+	mu         sync.Mutex
+	rateLimits map[string]RateLimit
+}
+
+// newRateLimitTransport wraps the transport, or http.DefaultTransport if it's nil
+func newRateLimitTransport(transport http.RoundTripper) *rateLimitTransport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &rateLimitTransport{
+		transport:          transport,
+		maxRetries:         defaultMaxRetries,
+		secondaryBackoff:   defaultSecondaryBackoff,
+		serverErrorBackoff: defaultServerErrorBackoff,
+		maxResetWait:       defaultMaxResetWait,
+		maxRetryWait:       defaultMaxRetryWait,
+		sleep:              time.Sleep,
+		jitter:             randomJitter,
+		now:                time.Now,
+		rateLimits:         make(map[string]RateLimit),
+	}
+}
+
+// randomJitter returns a random duration of up to d, so that concurrent requests don't retry in lockstep
+func randomJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d)
+}
+
+// baseTransport returns the transport go-gh would send the requests with:
+// the one of the options, a connection to gh's http_unix_socket, or http.DefaultTransport
+func baseTransport(opts api.ClientOptions) http.RoundTripper {
+	if opts.Transport != nil {
+		return opts.Transport
+	}
+
+	socket := opts.UnixDomainSocket
+	if socket == "" {
+		if cfg, err := config.Read(nil); err == nil {
+			socket, _ = cfg.Get([]string{"http_unix_socket"})
+		}
+	}
+	if socket == "" {
+		return http.DefaultTransport
+	}
+
+	dial := func(network, addr string) (net.Conn, error) {
+		return net.Dial("unix", socket)
+	}
+	return &http.Transport{
+		Dial:              dial,
+		DialTLS:           dial,
+		DisableKeepAlives: true,
+	}
+}
+
+// RoundTrip sends the request, retrying it when GitHub asks to back off.
+// The backoffs of a request are capped in total, so that a rate limit fails with ErrRateLimited
+// rather than blocking the caller for minutes.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resource := rateLimitResource(req)
+
+	var waited time.Duration
+	for attempt := 0; ; attempt++ {
+		if err := t.waitForReset(resource); err != nil {
+			return nil, err
+		}
+
+		attemptReq := req
+		if attempt > 0 {
+			var err error
+			attemptReq, err = rewindRequest(req)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.transport.RoundTrip(attemptReq)
+		if err != nil {
+			return nil, err
+		}
+		t.record(resp)
+
+		wait, retry := t.retryDelay(resp, attempt)
+		if !retry || attempt >= t.maxRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		if waited+wait > t.maxRetryWait {
+			if !isRateLimitStatus(resp.StatusCode) {
+				return resp, nil
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("%w, retry after %s", ErrRateLimited, wait.Round(time.Second))
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		if wait > 0 {
+			t.sleep(wait)
+			waited += wait
+		}
+	}
+}
+
+// RateLimit returns the last known rate limit state of the resource (e.g. "core" or "graphql")
+func (t *rateLimitTransport) RateLimit(resource string) (RateLimit, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rateLimit, ok := t.rateLimits[resource]
+	return rateLimit, ok
+}
+
+// waitForReset blocks until the primary rate limit of the resource resets,
+// or fails right away if the reset is too far in the future
+func (t *rateLimitTransport) waitForReset(resource string) error {
+	rateLimit, ok := t.RateLimit(resource)
+	if !ok || rateLimit.Remaining > 0 {
+		return nil
+	}
+
+	wait := rateLimit.Reset.Sub(t.now())
+	if wait <= 0 {
+		return nil
+	}
+	if wait > t.maxResetWait {
+		return fmt.Errorf("%w, resets at %s", ErrRateLimited, rateLimit.Reset.Format(time.Kitchen))
+	}
+
+	t.sleep(wait)
+	return nil
+}
+
+// record stores the rate limit state reported in the response headers
+func (t *rateLimitTransport) record(resp *http.Response) {
+	limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	used, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Used"))
+	reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+
+	resource := resp.Header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = rateLimitResource(resp.Request)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rateLimits[resource] = RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Used:      used,
+		Reset:     time.Unix(reset, 0),
+	}
+}
+
+// retryDelay decides whether the response should be retried and how long to wait before that
+func (t *rateLimitTransport) retryDelay(resp *http.Response, attempt int) (time.Duration, bool) {
+	switch resp.StatusCode {
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+
+		// The primary rate limit is exhausted; waitForReset decides whether to wait for it
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+			return 0, time.Unix(reset, 0).Sub(t.now()) <= t.maxResetWait
+		}
+
+		// Back off exponentially, never longer than a request may wait in total
+		if isSecondaryRateLimit(resp) {
+			backoff := t.secondaryBackoff << attempt
+			return min(backoff+t.jitter(backoff/4), t.maxRetryWait), true
+		}
+
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return t.serverErrorBackoff << attempt, true
+	}
+
+	return 0, false
+}
+
+// isRateLimitStatus reports whether GitHub uses the status code for rate limit responses
+func isRateLimitStatus(statusCode int) bool {
+	return statusCode == http.StatusForbidden || statusCode == http.StatusTooManyRequests
+}
+
+// isSecondaryRateLimit reports whether the response body is a secondary rate limit error.
+// The start of the body is read and put back, so that the caller still reads all of it.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	prefix, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(string(prefix)), "secondary rate limit")
+}
+
+// rateLimitResource returns the rate limit resource a request counts against
+func rateLimitResource(req *http.Request) string {
+	if req != nil && strings.HasSuffix(req.URL.Path, "/graphql") {
+		return "graphql"
+	}
+	return "core"
+}
+
+// rewindRequest returns a copy of the request with a fresh body so that it can be sent again
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body == nil || req.GetBody == nil {
+		return clone, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+	return clone, nil
+}
@@ -0,0 +1,227 @@
+package goproxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tnagatomi/gh-lsmod/modcache"
+	"github.com/tnagatomi/gh-lsmod/model"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// DefaultProxyURL is the module proxy used when GOPROXY is not set
+const DefaultProxyURL = "https://proxy.golang.org"
+
+// ErrNoProxy is returned when a module can't be fetched from a proxy and isn't cached
+var ErrNoProxy = errors.New("no module proxy available")
+
+// ErrNotFound is returned when the proxy doesn't know the module or version
+var ErrNotFound = errors.New("module not found in proxy")
+
+// Client reads module metadata from the Go module proxy, falling back to the module cache
+type Client struct {
+	proxyURL   string
+	private    string
+	httpClient *http.Client
+}
+
+// VersionInfo is the metadata returned by the proxy for a module version
+type VersionInfo struct {
+	Version string
+	Time    time.Time
+}
+
+// NewClient creates a new module proxy client configured from GOPROXY and GOPRIVATE
+func NewClient() *Client {
+	private := os.Getenv("GONOPROXY")
+	if private == "" {
+		private = os.Getenv("GOPRIVATE")
+	}
+
+	return NewClientWithURL(proxyFromEnv(os.Getenv("GOPROXY")), private)
+}
+
+// NewClientWithURL creates a new module proxy client for the given proxy URL.
+// Modules matching the comma-separated private patterns are never requested from the proxy.
+func NewClientWithURL(proxyURL, private string) *Client {
+	return &Client{
+		proxyURL: strings.TrimSuffix(proxyURL, "/"),
+		private:  private,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// proxyFromEnv returns the first proxy URL in a GOPROXY list, or empty string if there is none
+func proxyFromEnv(goproxy string) string {
+	if goproxy == "" {
+		return DefaultProxyURL
+	}
+
+	for _, entry := range strings.FieldsFunc(goproxy, func(r rune) bool { return r == ',' || r == '|' }) {
+		entry = strings.TrimSpace(entry)
+		if strings.HasPrefix(entry, "https://") || strings.HasPrefix(entry, "http://") {
+			return entry
+		}
+		if entry == "off" {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// CheckModuleStatus fills in the latest version, deprecation and retraction of each package.
+// Packages whose metadata can't be retrieved are left unchanged.
+func (c *Client) CheckModuleStatus(packages []*model.Package) {
+	for _, pkg := range packages {
+		_ = c.UpdateModuleStatus(pkg)
+	}
+}
+
+// UpdateModuleStatus fills in the latest version, deprecation and retraction of a package
+// from the go.mod of the module's latest version, and when the pinned version was published
+func (c *Client) UpdateModuleStatus(pkg *model.Package) error {
+	// The publication time is only used for sorting, so it's fine without it
+	if info, err := c.Info(pkg.Path, pkg.Version); err == nil {
+		pkg.VersionTime = info.Time
+	}
+
+	latest, err := c.Latest(pkg.Path)
+	if err != nil {
+		return fmt.Errorf("failed to get latest version of %s: %w", pkg.Path, err)
+	}
+
+	data, err := c.GoMod(pkg.Path, latest.Version)
+	if err != nil {
+		return fmt.Errorf("failed to get go.mod of %s@%s: %w", pkg.Path, latest.Version, err)
+	}
+
+	file, err := modfile.Parse(pkg.Path+"@"+latest.Version+"/go.mod", data, nil)
+	if err != nil {
+		return fmt.Errorf("failed to parse go.mod of %s@%s: %w", pkg.Path, latest.Version, err)
+	}
+
+	pkg.LatestVersion = latest.Version
+	pkg.Deprecated = ""
+	if file.Module != nil {
+		pkg.Deprecated = file.Module.Deprecated
+	}
+
+	pkg.Retracted = false
+	pkg.RetractRationale = ""
+	for _, r := range file.Retract {
+		if semver.Compare(r.Low, pkg.Version) <= 0 && semver.Compare(pkg.Version, r.High) <= 0 {
+			pkg.Retracted = true
+			pkg.RetractRationale = r.Rationale
+			break
+		}
+	}
+
+	return nil
+}
+
+// Latest returns the latest version of the module.
+// The module cache's version list is used when the proxy can't be reached.
+func (c *Client) Latest(path string) (*VersionInfo, error) {
+	data, proxyErr := c.fetch(path, "@latest")
+	if proxyErr == nil {
+		var info VersionInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			return nil, fmt.Errorf("failed to decode latest version info: %w", err)
+		}
+		return &info, nil
+	}
+
+	versions, err := modcache.ReadVersionList(path)
+	if err != nil || len(versions) == 0 {
+		return nil, proxyErr
+	}
+
+	latest := ""
+	for _, v := range versions {
+		if semver.IsValid(v) && semver.Compare(v, latest) > 0 {
+			latest = v
+		}
+	}
+	if latest == "" {
+		return nil, proxyErr
+	}
+
+	return &VersionInfo{Version: latest}, nil
+}
+
+// Info returns the metadata of the given module version,
+// reading it from the module cache when available
+func (c *Client) Info(path, version string) (*VersionInfo, error) {
+	data, err := modcache.ReadInfo(path, version)
+	if err != nil {
+		escapedVersion, escapeErr := module.EscapeVersion(version)
+		if escapeErr != nil {
+			return nil, escapeErr
+		}
+		if data, err = c.fetch(path, "@v/"+escapedVersion+".info"); err != nil {
+			return nil, err
+		}
+	}
+
+	var info VersionInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to decode version info: %w", err)
+	}
+	return &info, nil
+}
+
+// GoMod returns the go.mod file of the given module version,
+// reading it from the module cache when available
+func (c *Client) GoMod(path, version string) ([]byte, error) {
+	data, err := modcache.ReadGoMod(path, version)
+	if err == nil {
+		return data, nil
+	}
+
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fetch(path, "@v/"+escapedVersion+".mod")
+}
+
+// fetch requests a file of the module from the proxy
+func (c *Client) fetch(path, suffix string) ([]byte, error) {
+	if c.proxyURL == "" || module.MatchPrefixPatterns(c.private, path) {
+		return nil, ErrNoProxy
+	}
+
+	escapedPath, err := module.EscapePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Get(c.proxyURL + "/" + escapedPath + "/" + suffix)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("module proxy returned %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,212 @@
+package goproxy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+func newTestProxy(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/example.com/deprecated/@latest", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Version":"v1.2.0","Time":"2024-01-02T03:04:05Z"}`))
+	})
+	mux.HandleFunc("/example.com/deprecated/@v/v1.2.0.mod", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`// Deprecated: use example.com/replacement instead.
+module example.com/deprecated
+
+go 1.21
+
+retract (
+	v1.0.1 // Published accidentally.
+	[v1.1.0, v1.1.3] // Contains a security vulnerability.
+)
+`))
+	})
+	mux.HandleFunc("/example.com/deprecated/@v/v1.2.0.info", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Version":"v1.2.0","Time":"2024-01-02T03:04:05Z"}`))
+	})
+	mux.HandleFunc("/example.com/!upper/@latest", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Version":"v0.3.0"}`))
+	})
+	mux.HandleFunc("/example.com/!upper/@v/v0.3.0.mod", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("module example.com/Upper\n"))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestUpdateModuleStatus(t *testing.T) {
+	t.Setenv("GOMODCACHE", t.TempDir())
+	server := newTestProxy(t)
+	client := NewClientWithURL(server.URL, "")
+
+	tests := []struct {
+		name             string
+		path             string
+		version          string
+		latest           string
+		deprecated       string
+		retracted        bool
+		retractRationale string
+		versionTime      time.Time
+	}{
+		{
+			name:        "Deprecated module with a current version",
+			path:        "example.com/deprecated",
+			version:     "v1.2.0",
+			latest:      "v1.2.0",
+			deprecated:  "use example.com/replacement instead.",
+			versionTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			name:             "Single retracted version",
+			path:             "example.com/deprecated",
+			version:          "v1.0.1",
+			latest:           "v1.2.0",
+			deprecated:       "use example.com/replacement instead.",
+			retracted:        true,
+			retractRationale: "Published accidentally.",
+		},
+		{
+			name:             "Version within a retracted range",
+			path:             "example.com/deprecated",
+			version:          "v1.1.2",
+			latest:           "v1.2.0",
+			deprecated:       "use example.com/replacement instead.",
+			retracted:        true,
+			retractRationale: "Contains a security vulnerability.",
+		},
+		{
+			name:    "Module path with upper case letters",
+			path:    "example.com/Upper",
+			version: "v0.1.0",
+			latest:  "v0.3.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := model.NewPackage(tt.path, tt.version)
+			if err := client.UpdateModuleStatus(pkg); err != nil {
+				t.Fatalf("UpdateModuleStatus() returned an error: %v", err)
+			}
+
+			if pkg.LatestVersion != tt.latest {
+				t.Errorf("LatestVersion = %q, want %q", pkg.LatestVersion, tt.latest)
+			}
+			if pkg.Deprecated != tt.deprecated {
+				t.Errorf("Deprecated = %q, want %q", pkg.Deprecated, tt.deprecated)
+			}
+			if pkg.Retracted != tt.retracted {
+				t.Errorf("Retracted = %v, want %v", pkg.Retracted, tt.retracted)
+			}
+			if pkg.RetractRationale != tt.retractRationale {
+				t.Errorf("RetractRationale = %q, want %q", pkg.RetractRationale, tt.retractRationale)
+			}
+			if !pkg.VersionTime.Equal(tt.versionTime) {
+				t.Errorf("VersionTime = %v, want %v", pkg.VersionTime, tt.versionTime)
+			}
+		})
+	}
+
+	// Modules the proxy doesn't know are reported as not found
+	unknown := model.NewPackage("example.com/unknown", "v1.0.0")
+	if err := client.UpdateModuleStatus(unknown); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateModuleStatus() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUpdateModuleStatusFromModuleCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("GOMODCACHE", cacheDir)
+
+	downloadDir := filepath.Join(cacheDir, "cache", "download", "example.com", "cached", "@v")
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		t.Fatalf("Failed to create download cache: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(downloadDir, "list"), []byte("v1.0.0\nv1.1.0\n"), 0644); err != nil {
+		t.Fatalf("Failed to write version list: %v", err)
+	}
+	goMod := "// Deprecated: no longer maintained.\nmodule example.com/cached\n\nretract v1.0.0\n"
+	if err := os.WriteFile(filepath.Join(downloadDir, "v1.1.0.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+
+	// Without a proxy, everything must come from the module cache
+	client := NewClientWithURL("", "")
+	pkg := model.NewPackage("example.com/cached", "v1.0.0")
+	if err := client.UpdateModuleStatus(pkg); err != nil {
+		t.Fatalf("UpdateModuleStatus() returned an error: %v", err)
+	}
+
+	if pkg.LatestVersion != "v1.1.0" {
+		t.Errorf("LatestVersion = %q, want %q", pkg.LatestVersion, "v1.1.0")
+	}
+	if pkg.Deprecated != "no longer maintained." {
+		t.Errorf("Deprecated = %q, want %q", pkg.Deprecated, "no longer maintained.")
+	}
+	if !pkg.Retracted {
+		t.Errorf("Expected v1.0.0 to be retracted")
+	}
+
+	// Unknown modules return an error and are left unchanged
+	unknown := model.NewPackage("example.com/unknown", "v1.0.0")
+	if err := client.UpdateModuleStatus(unknown); err == nil {
+		t.Error("Expected an error for a module that is neither cached nor reachable, got nil")
+	}
+	if unknown.LatestVersion != "" {
+		t.Errorf("LatestVersion = %q, want empty", unknown.LatestVersion)
+	}
+}
+
+func TestPrivateModulesSkipProxy(t *testing.T) {
+	t.Setenv("GOMODCACHE", t.TempDir())
+
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClientWithURL(server.URL, "example.com/private")
+	_, err := client.Latest("example.com/private/repo")
+	if err == nil {
+		t.Error("Expected an error for a private module, got nil")
+	}
+	if requested {
+		t.Error("Expected private module not to be requested from the proxy")
+	}
+}
+
+func TestProxyFromEnv(t *testing.T) {
+	tests := []struct {
+		goproxy  string
+		expected string
+	}{
+		{goproxy: "", expected: DefaultProxyURL},
+		{goproxy: "https://proxy.golang.org,direct", expected: "https://proxy.golang.org"},
+		{goproxy: "direct", expected: ""},
+		{goproxy: "off", expected: ""},
+		{goproxy: "direct|https://goproxy.example.com", expected: "https://goproxy.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goproxy, func(t *testing.T) {
+			if got := proxyFromEnv(tt.goproxy); got != tt.expected {
+				t.Errorf("proxyFromEnv(%q) = %q, want %q", tt.goproxy, got, tt.expected)
+			}
+		})
+	}
+}
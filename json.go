@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/metadata"
+	"github.com/tnagatomi/gh-lsmod/model"
+	"github.com/tnagatomi/gh-lsmod/report"
+)
+
+// errNoToken is reported when GitHub data is skipped because there's no token to authenticate with
+var errNoToken = errors.New("GitHub data skipped: no token found (run gh auth login or set GH_TOKEN)")
+
+// jsonOptions are the flags that affect how the metadata of the JSON output is loaded
+type jsonOptions struct {
+	module       string
+	checker      metadata.ModuleStatusChecker
+	offline      bool
+	cacheTTL     time.Duration
+	starCacheTTL time.Duration
+}
+
+// runJSON loads the metadata of the packages and writes them as JSON to w.
+// Metadata that can't be loaded is left null, and the errors are written to errOut and into the report.
+func runJSON(w, errOut io.Writer, packages []*model.Package, opts jsonOptions) error {
+	var errs []error
+	githubClient, err := newJSONGitHubClient(opts)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	errs = append(errs, report.Load(packages, opts.checker, githubClient)...)
+	for _, err := range errs {
+		fmt.Fprintf(errOut, "Warning: %v\n", err)
+	}
+
+	if err := report.Write(w, report.New(opts.module, packages, errs)); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// newJSONGitHubClient creates the GitHub client for the JSON output.
+// Without a token it returns nil, so that GitHub data is skipped rather than every request failing.
+func newJSONGitHubClient(opts jsonOptions) (github.GitHubClient, error) {
+	if !github.HasToken() {
+		return nil, errNoToken
+	}
+
+	// The cache is only an optimization here, so it's skipped if there's nowhere to put it
+	cacheDir, _ := github.DefaultCacheDir()
+	client, err := github.NewClientWithCache(api.ClientOptions{}, github.CacheOptions{
+		Dir:         cacheDir,
+		StarTTL:     opts.starCacheTTL,
+		MetadataTTL: opts.cacheTTL,
+		Offline:     opts.offline,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GitHub data skipped: %w", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/model"
+	"github.com/tnagatomi/gh-lsmod/parser"
+)
+
+const listsUsage = `Usage:
+  gh lsmod lists                                 Show your star lists
+  gh lsmod lists add <list> [module...]          Add dependencies to a star list
+  gh lsmod lists remove <list> [module...]       Remove dependencies from a star list
+
+Without module paths, every GitHub-hosted direct dependency in go.mod is used.`
+
+// runLists runs the lists subcommand, which manages the GitHub star lists of the dependencies
+func runLists(args []string) error {
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help" || args[0] == "help") {
+		fmt.Println(listsUsage)
+		return nil
+	}
+
+	if len(args) > 0 && args[0] != "add" && args[0] != "remove" {
+		return fmt.Errorf("unknown lists command %q\n\n%s", args[0], listsUsage)
+	}
+
+	githubClient, err := github.NewClient()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return printStarLists(githubClient)
+	}
+	return updateStarList(githubClient, args[1:], args[0] == "add")
+}
+
+// printStarLists prints the user's star lists
+func printStarLists(githubClient github.GitHubClient) error {
+	lists, err := githubClient.ListStarLists()
+	if err != nil {
+		return err
+	}
+
+	if len(lists) == 0 {
+		fmt.Println("You don't have any star lists.")
+		return nil
+	}
+	for _, list := range lists {
+		visibility := ""
+		if list.IsPrivate {
+			visibility = " (private)"
+		}
+		fmt.Printf("%s%s: %d repositories\n", list.Name, visibility, len(list.Repositories))
+	}
+	return nil
+}
+
+// updateStarList adds the dependencies to the named star list, or removes them from it
+func updateStarList(githubClient github.GitHubClient, args []string, add bool) error {
+	command := "remove"
+	if add {
+		command = "add"
+	}
+
+	flags := flag.NewFlagSet("lists "+command, flag.ContinueOnError)
+	create := flags.Bool("create", false, "create the list if it doesn't exist")
+	private := flags.Bool("private", false, "make a created list private")
+	description := flags.String("description", "", "description of a created list")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: gh lsmod lists %s <list> [module...]\n", command)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("missing list name")
+	}
+	name := flags.Arg(0)
+
+	gomodParser, err := parser.NewParserForCurrentDirectory()
+	if err != nil {
+		return err
+	}
+	packages, err := selectDependencies(gomodParser, flags.Args()[1:])
+	if err != nil {
+		return err
+	}
+	if len(packages) == 0 {
+		fmt.Println("No GitHub-hosted dependencies to update.")
+		return nil
+	}
+
+	lists, err := githubClient.ListStarLists()
+	if err != nil {
+		return err
+	}
+	list := github.FindStarList(lists, name)
+	if list == nil {
+		if !add || !*create {
+			return fmt.Errorf("star list %q not found (use --create to create it)", name)
+		}
+		list, err = githubClient.CreateStarList(name, *description, *private)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created star list %s\n", list.Name)
+	}
+
+	if add {
+		err = recordingClient(githubClient, gomodParser).AddToStarList(list, packages)
+	} else {
+		err = githubClient.RemoveFromStarList(list, packages)
+	}
+
+	// Report the repositories that were updated even if some failed
+	var pkgErrs github.PackageErrors
+	failed := 0
+	if errors.As(err, &pkgErrs) {
+		failed = len(pkgErrs)
+	} else if err != nil {
+		return err
+	}
+
+	format := "Removed %d of %d dependencies from %s\n"
+	if add {
+		format = "Added %d of %d dependencies to %s\n"
+	}
+	fmt.Printf(format, len(packages)-failed, len(packages), list.Name)
+	return err
+}
+
+// selectDependencies returns the GitHub-hosted direct dependencies, limited to the given module paths if any
+func selectDependencies(gomodParser *parser.GoModParser, paths []string) ([]*model.Package, error) {
+	all, err := gomodParser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	byPath := make(map[string]*model.Package, len(all))
+	var packages []*model.Package
+	for _, pkg := range all {
+		byPath[pkg.Path] = pkg
+		if len(paths) == 0 && pkg.IsGitHub {
+			packages = append(packages, pkg)
+		}
+	}
+
+	for _, path := range paths {
+		pkg, ok := byPath[path]
+		if !ok {
+			return nil, fmt.Errorf("%s is not a direct dependency in go.mod", path)
+		}
+		if !pkg.IsGitHub {
+			fmt.Fprintf(os.Stderr, "Skipping %s: not hosted on GitHub\n", path)
+			continue
+		}
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
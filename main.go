@@ -1,15 +1,53 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/tnagatomi/gh-lsmod/config"
+	"github.com/tnagatomi/gh-lsmod/filter"
 	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/goproxy"
 	"github.com/tnagatomi/gh-lsmod/parser"
 	"github.com/tnagatomi/gh-lsmod/ui"
 )
 
 func main() {
+	// Read the config file first, as it sets the defaults of the flags
+	cfg, settings, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defaults := cfg.Defaults
+
+	offline := flag.Bool("offline", valueOr(defaults.Offline, false), "serve GitHub data only from the cache and disable star actions")
+	cacheTTL := flag.Duration("cache-ttl", valueOr(defaults.CacheTTL, github.DefaultMetadataTTL), "how long cached repository metadata is used before fetching it again")
+	starCacheTTL := flag.Duration("star-cache-ttl", valueOr(defaults.StarCacheTTL, github.DefaultStarTTL), "how long cached star and watch status is used before fetching it again")
+	all := flag.Bool("all", valueOr(defaults.All, false), "include indirect dependencies")
+	jsonOutput := flag.Bool("json", false, "write the dependencies and their metadata as JSON instead of starting the TUI")
+	flag.Parse()
+
+	// Manage star lists without starting the TUI
+	if flag.Arg(0) == "lists" {
+		if err := runLists(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Remove stars for dependencies the project no longer uses
+	if flag.Arg(0) == "stars" {
+		if err := runStars(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Create a parser for the go.mod file in the current directory
 	gomodParser, err := parser.NewParserForCurrentDirectory()
 	if err != nil {
@@ -17,36 +55,131 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Extract direct dependencies
-	packages, err := gomodParser.Parse()
+	// Extract direct dependencies, and indirect ones if requested
+	parse := gomodParser.Parse
+	if *all {
+		parse = gomodParser.ParseAll
+	}
+	packages, err := parse()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Look up deprecations and retractions from the latest go.mod of each module
+	// In offline mode, only the module cache is read
+	proxyClient := goproxy.NewClient()
+	if *offline {
+		proxyClient = goproxy.NewClientWithURL("", "")
+	}
+
+	// Write the dependencies as JSON for scripts, including when there are none
+	if *jsonOutput {
+		module, err := gomodParser.ModulePath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		err = runJSON(os.Stdout, os.Stderr, packages, jsonOptions{
+			module:       module,
+			checker:      proxyClient,
+			offline:      *offline,
+			cacheTTL:     *cacheTTL,
+			starCacheTTL: *starCacheTTL,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if len(packages) == 0 {
+		if *all {
+			fmt.Println("No dependencies found in go.mod file.")
+			os.Exit(0)
+		}
 		fmt.Println("No direct dependencies found in go.mod file.")
 		os.Exit(0)
 	}
 
-	// Initialize GitHub client
-	githubClient, err := github.NewClient()
+	// Initialize GitHub client with the on-disk response cache
+	// Failures are shown in the TUI, which still lists the modules without GitHub data
+	var startupErrors []error
+	cacheDir, err := github.DefaultCacheDir()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		startupErrors = append(startupErrors, fmt.Errorf("GitHub responses won't be cached: %w", err))
 	}
-
-	// Check starred status
-	err = githubClient.CheckStarredStatus(packages)
+	var githubClient github.GitHubClient
+	client, err := github.NewClientWithCache(api.ClientOptions{}, github.CacheOptions{
+		Dir:         cacheDir,
+		StarTTL:     *starCacheTTL,
+		MetadataTTL: *cacheTTL,
+		Offline:     *offline,
+	})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		startupErrors = append(startupErrors, fmt.Errorf("GitHub features are unavailable: %w", err))
+	} else {
+		// Stars added from the TUI are recorded so that "stars sync" can remove them later
+		githubClient = recordingClient(client, gomodParser)
+	}
+
+	// Every module in go.mod is needed to point out version conflicts in the dependency tree
+	requirements := packages
+	if !*all {
+		if requirements, err = gomodParser.ParseAll(); err != nil {
+			startupErrors = append(startupErrors, fmt.Errorf("version conflicts won't be shown in the dependency tree: %w", err))
+		}
+	}
+
+	// Load the filters saved from the TUI
+	// If they can't be read, filters are only saved for this session so the file isn't overwritten
+	savedFilters := filter.NewSaved("")
+	if path, err := filter.DefaultSavedPath(); err != nil {
+		startupErrors = append(startupErrors, err)
+	} else if saved, err := filter.LoadSaved(path); err != nil {
+		startupErrors = append(startupErrors, err)
+	} else {
+		savedFilters = saved
 	}
 
 	// Run TUI application
-	err = ui.Run(packages, githubClient)
+	// Module status and GitHub metadata are loaded after the list is shown
+	err = ui.Run(packages, githubClient, ui.Options{
+		Offline:       *offline,
+		ModuleStatus:  proxyClient,
+		StartupErrors: startupErrors,
+		SavedFilters:  savedFilters,
+		Requirements:  requirements,
+		Settings:      settings,
+	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// loadConfig reads the config file and checks its key bindings, theme and fields against the TUI
+func loadConfig() (*config.Config, *ui.Settings, error) {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	settings, err := ui.NewSettings(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid config %s:\n%w", path, err)
+	}
+	return cfg, settings, nil
+}
+
+// valueOr returns the value the pointer points to, or the fallback if it's nil
+func valueOr[T any](value *T, fallback T) T {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
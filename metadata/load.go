@@ -0,0 +1,39 @@
+package metadata
+
+import (
+	"sync"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+	"github.com/tnagatomi/gh-lsmod/size"
+)
+
+// Concurrency is the number of packages whose size and module status are loaded in parallel
+const Concurrency = 8
+
+// ModuleStatusChecker looks up the latest version, deprecation and retraction of a module
+type ModuleStatusChecker interface {
+	UpdateModuleStatus(pkg *model.Package) error
+}
+
+// CalculateSize calculates the size of a package in the module cache.
+// It's a variable so that tests don't depend on the module cache.
+var CalculateSize = size.CalculatePackageSize
+
+// ForEach calls fn for every index in [0, n), running at most limit calls at a time,
+// and returns once they have all returned
+func ForEach(n, limit int, fn func(i int)) {
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,33 @@
+package metadata
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEach(t *testing.T) {
+	var running, peak atomic.Int32
+	called := make([]bool, 20)
+	ForEach(len(called), 3, func(i int) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		called[i] = true
+		running.Add(-1)
+	})
+
+	for i, ok := range called {
+		if !ok {
+			t.Errorf("Expected fn to be called for index %d", i)
+		}
+	}
+	if peak.Load() > 3 {
+		t.Errorf("Expected at most 3 calls at a time, got %d", peak.Load())
+	}
+}
@@ -0,0 +1,144 @@
+package modcache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// Dir returns the root of the module cache (GOMODCACHE, or GOPATH/pkg/mod)
+func Dir() (string, error) {
+	goModCache := os.Getenv("GOMODCACHE")
+	if goModCache != "" {
+		return goModCache, nil
+	}
+
+	goPath := os.Getenv("GOPATH")
+	if goPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		goPath = filepath.Join(home, "go")
+	}
+	return filepath.Join(goPath, "pkg", "mod"), nil
+}
+
+// PackageDir returns the directory where the module is extracted in the module cache
+func PackageDir(path, version string) (string, error) {
+	root, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	escapedPath, err := module.EscapePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	dir := escapedPath
+	if version != "" {
+		escapedVersion, err := module.EscapeVersion(version)
+		if err != nil {
+			return "", err
+		}
+		dir = escapedPath + "@" + escapedVersion
+	}
+
+	return filepath.Join(root, filepath.FromSlash(dir)), nil
+}
+
+// DownloadDir returns the download cache directory holding the .info, .mod
+// and .zip files of every fetched version of the module
+func DownloadDir(path string) (string, error) {
+	root, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	escapedPath, err := module.EscapePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "cache", "download", filepath.FromSlash(escapedPath), "@v"), nil
+}
+
+// ReadGoMod reads the go.mod file of the given module version from the download cache
+func ReadGoMod(path, version string) ([]byte, error) {
+	dir, err := DownloadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(filepath.Join(dir, escapedVersion+".mod"))
+}
+
+// ReadInfo reads the .info file, holding the version and its publication time, from the download cache
+func ReadInfo(path, version string) ([]byte, error) {
+	dir, err := DownloadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(filepath.Join(dir, escapedVersion+".info"))
+}
+
+// ReadVersionList returns the versions of the module recorded in the download cache
+func ReadVersionList(path string) ([]string, error) {
+	dir, err := DownloadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "list"))
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(data)), nil
+}
+
+// readmeNames lists the README file names looked for, in order of preference
+var readmeNames = []string{"README.md", "README.markdown", "README", "README.txt", "README.rst"}
+
+// ReadReadme reads the README file at the root of the module, as extracted in the module cache.
+// File names are matched case-insensitively. It returns the name of the file and its content.
+func ReadReadme(path, version string) (string, []byte, error) {
+	dir, err := PackageDir(path, version)
+	if err != nil {
+		return "", nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, name := range readmeNames {
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.EqualFold(entry.Name(), name) {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return "", nil, err
+			}
+			return entry.Name(), data, nil
+		}
+	}
+	return "", nil, fmt.Errorf("no README in %s: %w", dir, os.ErrNotExist)
+}
@@ -0,0 +1,139 @@
+package modcache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDir(t *testing.T) {
+	t.Setenv("GOMODCACHE", "/tmp/modcache")
+	if got, err := Dir(); err != nil || got != "/tmp/modcache" {
+		t.Errorf("Dir() = %q, %v, want %q", got, err, "/tmp/modcache")
+	}
+
+	t.Setenv("GOMODCACHE", "")
+	t.Setenv("GOPATH", "/tmp/gopath")
+	if got, err := Dir(); err != nil || got != filepath.Join("/tmp/gopath", "pkg", "mod") {
+		t.Errorf("Dir() = %q, %v, want %q", got, err, filepath.Join("/tmp/gopath", "pkg", "mod"))
+	}
+}
+
+func TestPackageDir(t *testing.T) {
+	t.Setenv("GOMODCACHE", "/tmp/modcache")
+
+	tests := []struct {
+		name     string
+		path     string
+		version  string
+		expected string
+	}{
+		{
+			name:     "Regular package path",
+			path:     "github.com/charmbracelet/bubbles",
+			version:  "v0.20.0",
+			expected: filepath.Join("/tmp/modcache", "github.com", "charmbracelet", "bubbles@v0.20.0"),
+		},
+		{
+			name:     "Package path with upper case letters",
+			path:     "github.com/BurntSushi/toml",
+			version:  "v1.4.0",
+			expected: filepath.Join("/tmp/modcache", "github.com", "!burnt!sushi", "toml@v1.4.0"),
+		},
+		{
+			name:     "Package without version",
+			path:     "golang.org/x/mod",
+			version:  "",
+			expected: filepath.Join("/tmp/modcache", "golang.org", "x", "mod"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PackageDir(tt.path, tt.version)
+			if err != nil {
+				t.Fatalf("PackageDir() returned an error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("PackageDir() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadGoModAndVersionList(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("GOMODCACHE", cacheDir)
+
+	downloadDir := filepath.Join(cacheDir, "cache", "download", "github.com", "!burnt!sushi", "toml", "@v")
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		t.Fatalf("Failed to create download cache: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(downloadDir, "v1.4.0.mod"), []byte("module github.com/BurntSushi/toml\n"), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(downloadDir, "list"), []byte("v1.3.2\nv1.4.0\n"), 0644); err != nil {
+		t.Fatalf("Failed to write version list: %v", err)
+	}
+	info := `{"Version":"v1.4.0","Time":"2024-06-12T18:15:43Z"}`
+	if err := os.WriteFile(filepath.Join(downloadDir, "v1.4.0.info"), []byte(info), 0644); err != nil {
+		t.Fatalf("Failed to write version info: %v", err)
+	}
+
+	data, err := ReadGoMod("github.com/BurntSushi/toml", "v1.4.0")
+	if err != nil {
+		t.Fatalf("ReadGoMod() returned an error: %v", err)
+	}
+	if string(data) != "module github.com/BurntSushi/toml\n" {
+		t.Errorf("ReadGoMod() = %q", data)
+	}
+
+	data, err = ReadInfo("github.com/BurntSushi/toml", "v1.4.0")
+	if err != nil {
+		t.Fatalf("ReadInfo() returned an error: %v", err)
+	}
+	if string(data) != info {
+		t.Errorf("ReadInfo() = %q", data)
+	}
+
+	versions, err := ReadVersionList("github.com/BurntSushi/toml")
+	if err != nil {
+		t.Fatalf("ReadVersionList() returned an error: %v", err)
+	}
+	if len(versions) != 2 || versions[0] != "v1.3.2" || versions[1] != "v1.4.0" {
+		t.Errorf("ReadVersionList() = %v, want [v1.3.2 v1.4.0]", versions)
+	}
+
+	if _, err := ReadGoMod("github.com/BurntSushi/toml", "v9.9.9"); err == nil {
+		t.Error("Expected an error for a version that isn't cached, got nil")
+	}
+}
+
+func TestReadReadme(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("GOMODCACHE", cacheDir)
+
+	moduleDir := filepath.Join(cacheDir, "github.com", "owner", "repo@v1.0.0")
+	if err := os.MkdirAll(moduleDir, 0755); err != nil {
+		t.Fatalf("Failed to create module directory: %v", err)
+	}
+	for name, content := range map[string]string{"readme.rst": "rst", "Readme.md": "markdown"} {
+		if err := os.WriteFile(filepath.Join(moduleDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// Markdown is preferred, whatever the case of the file name
+	name, data, err := ReadReadme("github.com/owner/repo", "v1.0.0")
+	if err != nil {
+		t.Fatalf("ReadReadme() returned an error: %v", err)
+	}
+	if name != "Readme.md" || string(data) != "markdown" {
+		t.Errorf("ReadReadme() = %q, %q, want Readme.md, markdown", name, data)
+	}
+
+	if _, _, err := ReadReadme("github.com/owner/repo", "v2.0.0"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadReadme() error = %v, want os.ErrNotExist for a module that isn't cached", err)
+	}
+}
@@ -2,7 +2,11 @@ package model
 
 import (
 	"fmt"
+	"path"
 	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
 )
 
 // Package represents a Go module dependency
@@ -11,7 +15,18 @@ type Package struct {
 	Version   string // Version
 	IsGitHub  bool   // Whether it's a GitHub repository
 	IsStarred bool   // Whether it's starred by the user
+	IsWatched bool   // Whether the user is subscribed to the repository's notifications
+	IsIgnored bool   // Whether the user chose to ignore the repository's notifications
+	Indirect  bool   // Whether it's an indirect dependency
 	Size      int64  // Size in bytes
+
+	VersionTime      time.Time // When the pinned version was published
+	LatestVersion    string    // Latest version published for the module
+	Deprecated       string    // Deprecation message from the latest go.mod
+	Retracted        bool      // Whether the pinned version is retracted
+	RetractRationale string    // Rationale given for the retraction
+
+	Repo *RepoMetadata // GitHub repository metadata, if fetched
 }
 
 // NewPackage creates a new Package instance
@@ -25,6 +40,12 @@ func NewPackage(path, version string) *Package {
 	}
 }
 
+// Host returns the host of the module path, such as github.com or golang.org
+func (p *Package) Host() string {
+	host, _, _ := strings.Cut(p.Path, "/")
+	return host
+}
+
 // GitHubRepoPath returns the GitHub repository path (owner/repo)
 // Returns empty string if not a GitHub repository
 func (p *Package) GitHubRepoPath() string {
@@ -35,18 +56,9 @@ func (p *Package) GitHubRepoPath() string {
 	// Remove github.com/ prefix
 	repoPath := strings.TrimPrefix(p.Path, "github.com/")
 
-	// Handle version suffix (e.g., github.com/cli/go-gh/v2)
+	// The repository is the first two elements, followed by an optional
+	// subdirectory and major version suffix (e.g., github.com/owner/repo/sub/v2)
 	parts := strings.Split(repoPath, "/")
-	if len(parts) >= 2 {
-		// Check if the last part starts with 'v' followed by a number
-		lastPart := parts[len(parts)-1]
-		if len(lastPart) > 1 && lastPart[0] == 'v' && '0' <= lastPart[1] && lastPart[1] <= '9' {
-			// Remove the version suffix
-			return strings.Join(parts[:len(parts)-1], "/")
-		}
-	}
-
-	// Handle case with no version suffix
 	if len(parts) >= 2 {
 		return strings.Join(parts[:2], "/")
 	}
@@ -54,18 +66,104 @@ func (p *Package) GitHubRepoPath() string {
 	return repoPath
 }
 
-// GitHubURL returns the GitHub repository URL
+// GitHubRepoURL returns the GitHub repository URL, regardless of the version
 // Returns empty string if not a GitHub repository
-func (p *Package) GitHubURL() string {
+func (p *Package) GitHubRepoURL() string {
 	if !p.IsGitHub {
 		return ""
 	}
 	return fmt.Sprintf("https://github.com/%s", p.GitHubRepoPath())
 }
 
-// PkgGoDevURL returns the pkg.go.dev URL for the package
+// GitHubURL returns the GitHub URL of the module at the pinned version: the tree of its tag,
+// or of its commit for a pseudo-version, in the directory of a module in a subdirectory
+// Returns the repository URL if the version is unknown, and empty string if not a GitHub repository
+func (p *Package) GitHubURL() string {
+	if !p.IsGitHub || p.Version == "" {
+		return p.GitHubRepoURL()
+	}
+	url := fmt.Sprintf("%s/tree/%s", p.GitHubRepoURL(), p.GitRef())
+	if dir := p.GitHubSubdir(); dir != "" {
+		url += "/" + dir
+	}
+	return url
+}
+
+// GitHubSubdir returns the directory of the module in its GitHub repository, without the major version suffix
+// Returns empty string for a module at the root of its repository
+func (p *Package) GitHubSubdir() string {
+	if !p.IsGitHub {
+		return ""
+	}
+	prefix, _, ok := module.SplitPathVersion(p.Path)
+	if !ok {
+		prefix = p.Path
+	}
+	return strings.Trim(strings.TrimPrefix(prefix, "github.com/"+p.GitHubRepoPath()), "/")
+}
+
+// GitRef returns the git ref of the pinned version: the commit of a pseudo-version,
+// or the tag, prefixed with the directory of a module in a subdirectory
+func (p *Package) GitRef() string {
+	return p.gitRef(p.Version)
+}
+
+// gitRef returns the git ref of a version of the module
+func (p *Package) gitRef(version string) string {
+	if module.IsPseudoVersion(version) {
+		if rev, err := module.PseudoVersionRev(version); err == nil {
+			return rev
+		}
+	}
+	tag := strings.TrimSuffix(version, "+incompatible")
+	if dir := p.GitHubSubdir(); dir != "" {
+		return dir + "/" + tag
+	}
+	return tag
+}
+
+// GitHubBlobURL returns the URL of a file of the module on GitHub at the pinned version,
+// pointing at the line if it's not 0
+// Returns empty string if not a GitHub repository
+func (p *Package) GitHubBlobURL(file string, line int) string {
+	if !p.IsGitHub {
+		return ""
+	}
+	url := fmt.Sprintf("%s/blob/%s/%s", p.GitHubRepoURL(), p.GitRef(), path.Join(p.GitHubSubdir(), file))
+	if line > 0 {
+		url += fmt.Sprintf("#L%d", line)
+	}
+	return url
+}
+
+// CompareURL returns the GitHub compare view URL between the refs of the pinned and the latest version
+// Returns empty string if not a GitHub repository or if the latest version is unknown
+func (p *Package) CompareURL() string {
+	if !p.IsGitHub || !p.Outdated() {
+		return ""
+	}
+	return fmt.Sprintf("%s/compare/%s...%s", p.GitHubRepoURL(), p.gitRef(p.Version), p.gitRef(p.LatestVersion))
+}
+
+// PkgGoDevURL returns the pkg.go.dev URL for the package at the pinned version
 func (p *Package) PkgGoDevURL() string {
-	return fmt.Sprintf("https://pkg.go.dev/%s", p.Path)
+	if p.Version == "" {
+		return fmt.Sprintf("https://pkg.go.dev/%s", p.Path)
+	}
+	return fmt.Sprintf("https://pkg.go.dev/%s@%s", p.Path, p.Version)
+}
+
+// ModuleVersion returns the module path at the pinned version, as in path@version
+func (p *Package) ModuleVersion() string {
+	return p.Path + "@" + p.Version
+}
+
+// RequireLine returns the requirement of the module as written in a go.mod require block
+func (p *Package) RequireLine() string {
+	if p.Indirect {
+		return p.Path + " " + p.Version + " // indirect"
+	}
+	return p.Path + " " + p.Version
 }
 
 // StarSymbol returns the star symbol based on the starred status
@@ -80,12 +178,55 @@ func (p *Package) StarSymbol() string {
 	return "☆"
 }
 
+// ParsedVersion returns the pinned version parsed into its parts
+func (p *Package) ParsedVersion() Version {
+	return ParseVersion(p.Version)
+}
+
+// Outdated reports whether a version newer than the pinned one is known.
+// A pseudo-version of a commit after the latest tag isn't outdated.
+func (p *Package) Outdated() bool {
+	return p.LatestVersion != "" && p.ParsedVersion().Compare(ParseVersion(p.LatestVersion)) < 0
+}
+
+// VersionDate returns when the pinned version was published,
+// or the commit time of a pseudo-version until the publication time is known
+func (p *Package) VersionDate() time.Time {
+	if p.VersionTime.IsZero() {
+		return p.ParsedVersion().Time
+	}
+	return p.VersionTime
+}
+
+// Warnings returns the warnings to be shown for the package
+func (p *Package) Warnings() []string {
+	var warnings []string
+
+	if p.Deprecated != "" {
+		warnings = append(warnings, "Deprecated: "+p.Deprecated)
+	}
+
+	if p.Retracted {
+		warning := "Retracted: " + p.Version
+		if p.RetractRationale != "" {
+			warning += " (" + p.RetractRationale + ")"
+		}
+		warnings = append(warnings, warning)
+	}
+
+	return warnings
+}
+
 // FormattedSize returns the size in a human-readable format
 func (p *Package) FormattedSize() string {
 	if p.Size == 0 {
 		return "unknown"
 	}
+	return FormatSize(p.Size)
+}
 
+// FormatSize returns the number of bytes in a human-readable format
+func FormatSize(size int64) string {
 	const (
 		_          = iota
 		KB float64 = 1 << (10 * iota)
@@ -99,17 +240,17 @@ func (p *Package) FormattedSize() string {
 	)
 
 	switch {
-	case p.Size >= int64(GB):
-		value = float64(p.Size) / GB
+	case size >= int64(GB):
+		value = float64(size) / GB
 		unit = "GB"
-	case p.Size >= int64(MB):
-		value = float64(p.Size) / MB
+	case size >= int64(MB):
+		value = float64(size) / MB
 		unit = "MB"
-	case p.Size >= int64(KB):
-		value = float64(p.Size) / KB
+	case size >= int64(KB):
+		value = float64(size) / KB
 		unit = "KB"
 	default:
-		value = float64(p.Size)
+		value = float64(size)
 		unit = "B"
 	}
 
@@ -4,6 +4,39 @@ import (
 	"testing"
 )
 
+func TestHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "Module in a subdirectory",
+			path:     "golang.org/x/mod",
+			expected: "golang.org",
+		},
+		{
+			name:     "GitHub repository",
+			path:     "github.com/cli/go-gh/v2",
+			expected: "github.com",
+		},
+		{
+			name:     "Module without a slash",
+			path:     "example.com",
+			expected: "example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := NewPackage(tt.path, "v1.0.0")
+			if got := pkg.Host(); got != tt.expected {
+				t.Errorf("Host() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestGitHubRepoPath(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -30,6 +63,11 @@ func TestGitHubRepoPath(t *testing.T) {
 			path:     "github.com/charmbracelet/bubbles/list/item",
 			expected: "charmbracelet/bubbles",
 		},
+		{
+			name:     "Module in a subdirectory with version suffix",
+			path:     "github.com/owner/repo/sub/v2",
+			expected: "owner/repo",
+		},
 	}
 
 	for _, tt := range tests {
@@ -46,28 +84,56 @@ func TestGitHubURL(t *testing.T) {
 	tests := []struct {
 		name     string
 		path     string
+		version  string
 		expected string
 	}{
 		{
 			name:     "Non-GitHub repository",
 			path:     "golang.org/x/mod",
+			version:  "v1.0.0",
 			expected: "",
 		},
 		{
 			name:     "GitHub repository with version suffix",
 			path:     "github.com/cli/go-gh/v2",
-			expected: "https://github.com/cli/go-gh",
+			version:  "v2.12.2",
+			expected: "https://github.com/cli/go-gh/tree/v2.12.2",
 		},
 		{
 			name:     "GitHub repository without version suffix",
 			path:     "github.com/charmbracelet/bubbles",
+			version:  "v1.0.0",
+			expected: "https://github.com/charmbracelet/bubbles/tree/v1.0.0",
+		},
+		{
+			name:     "Module in a subdirectory",
+			path:     "github.com/charmbracelet/x/ansi",
+			version:  "v0.10.1",
+			expected: "https://github.com/charmbracelet/x/tree/ansi/v0.10.1/ansi",
+		},
+		{
+			name:     "Module in a subdirectory with version suffix",
+			path:     "github.com/owner/repo/sub/v2",
+			version:  "v2.1.0",
+			expected: "https://github.com/owner/repo/tree/sub/v2.1.0/sub",
+		},
+		{
+			name:     "Pseudo-version",
+			path:     "github.com/owner/repo",
+			version:  "v0.0.0-20240101000000-0123456789ab",
+			expected: "https://github.com/owner/repo/tree/0123456789ab",
+		},
+		{
+			name:     "Unknown version",
+			path:     "github.com/charmbracelet/bubbles",
+			version:  "",
 			expected: "https://github.com/charmbracelet/bubbles",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			pkg := NewPackage(tt.path, "v1.0.0")
+			pkg := NewPackage(tt.path, tt.version)
 			if got := pkg.GitHubURL(); got != tt.expected {
 				t.Errorf("GitHubURL() = %v, want %v", got, tt.expected)
 			}
@@ -75,6 +141,134 @@ func TestGitHubURL(t *testing.T) {
 	}
 }
 
+func TestCompareURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		latest   string
+		expected string
+	}{
+		{
+			name:     "Non-GitHub repository",
+			path:     "golang.org/x/mod",
+			latest:   "v1.1.0",
+			expected: "",
+		},
+		{
+			name:     "GitHub repository with unknown latest version",
+			path:     "github.com/charmbracelet/bubbles",
+			latest:   "",
+			expected: "",
+		},
+		{
+			name:     "GitHub repository already at latest version",
+			path:     "github.com/charmbracelet/bubbles",
+			latest:   "v1.0.0",
+			expected: "",
+		},
+		{
+			name:     "GitHub repository with newer version",
+			path:     "github.com/cli/go-gh/v2",
+			latest:   "v1.1.0",
+			expected: "https://github.com/cli/go-gh/compare/v1.0.0...v1.1.0",
+		},
+		{
+			name:     "Module in a subdirectory",
+			path:     "github.com/charmbracelet/x/ansi",
+			latest:   "v1.1.0",
+			expected: "https://github.com/charmbracelet/x/compare/ansi/v1.0.0...ansi/v1.1.0",
+		},
+		{
+			name:     "Module in a subdirectory with version suffix",
+			path:     "github.com/owner/repo/sub/v2",
+			latest:   "v1.1.0",
+			expected: "https://github.com/owner/repo/compare/sub/v1.0.0...sub/v1.1.0",
+		},
+		{
+			name:     "Newer pseudo-version",
+			path:     "github.com/owner/repo",
+			latest:   "v1.0.1-0.20240101000000-0123456789ab",
+			expected: "https://github.com/owner/repo/compare/v1.0.0...0123456789ab",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := NewPackage(tt.path, "v1.0.0")
+			pkg.LatestVersion = tt.latest
+			if got := pkg.CompareURL(); got != tt.expected {
+				t.Errorf("CompareURL() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGitHubBlobURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		version  string
+		file     string
+		line     int
+		expected string
+	}{
+		{
+			name:     "Tagged version",
+			path:     "github.com/charmbracelet/bubbles",
+			version:  "v0.20.0",
+			file:     "list/list.go",
+			line:     42,
+			expected: "https://github.com/charmbracelet/bubbles/blob/v0.20.0/list/list.go#L42",
+		},
+		{
+			name:     "Module in a subdirectory",
+			path:     "github.com/charmbracelet/x/ansi",
+			version:  "v0.10.1",
+			file:     "ansi.go",
+			expected: "https://github.com/charmbracelet/x/blob/ansi/v0.10.1/ansi/ansi.go",
+		},
+		{
+			name:     "Module in a subdirectory with version suffix",
+			path:     "github.com/owner/repo/sub/v2",
+			version:  "v2.1.0",
+			file:     "sub.go",
+			line:     7,
+			expected: "https://github.com/owner/repo/blob/sub/v2.1.0/sub/sub.go#L7",
+		},
+		{
+			name:     "Pseudo-version",
+			path:     "github.com/owner/repo/v2",
+			version:  "v2.0.0-20240101000000-0123456789ab",
+			file:     "main.go",
+			line:     1,
+			expected: "https://github.com/owner/repo/blob/0123456789ab/main.go#L1",
+		},
+		{
+			name:     "Incompatible version",
+			path:     "github.com/owner/repo",
+			version:  "v3.1.0+incompatible",
+			file:     "main.go",
+			expected: "https://github.com/owner/repo/blob/v3.1.0/main.go",
+		},
+		{
+			name:     "Non-GitHub repository",
+			path:     "golang.org/x/mod",
+			version:  "v0.27.0",
+			file:     "go.mod",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := NewPackage(tt.path, tt.version)
+			if got := pkg.GitHubBlobURL(tt.file, tt.line); got != tt.expected {
+				t.Errorf("GitHubBlobURL() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestPkgGoDevURL(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -84,17 +278,17 @@ func TestPkgGoDevURL(t *testing.T) {
 		{
 			name:     "Non-GitHub repository",
 			path:     "golang.org/x/mod",
-			expected: "https://pkg.go.dev/golang.org/x/mod",
+			expected: "https://pkg.go.dev/golang.org/x/mod@v1.0.0",
 		},
 		{
 			name:     "GitHub repository with version suffix",
 			path:     "github.com/cli/go-gh/v2",
-			expected: "https://pkg.go.dev/github.com/cli/go-gh/v2",
+			expected: "https://pkg.go.dev/github.com/cli/go-gh/v2@v1.0.0",
 		},
 		{
 			name:     "GitHub repository without version suffix",
 			path:     "github.com/charmbracelet/bubbles",
-			expected: "https://pkg.go.dev/github.com/charmbracelet/bubbles",
+			expected: "https://pkg.go.dev/github.com/charmbracelet/bubbles@v1.0.0",
 		},
 	}
 
@@ -108,6 +302,37 @@ func TestPkgGoDevURL(t *testing.T) {
 	}
 }
 
+func TestRequireLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		indirect bool
+		expected string
+	}{
+		{
+			name:     "Direct dependency",
+			expected: "golang.org/x/mod v0.27.0",
+		},
+		{
+			name:     "Indirect dependency",
+			indirect: true,
+			expected: "golang.org/x/mod v0.27.0 // indirect",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := NewPackage("golang.org/x/mod", "v0.27.0")
+			pkg.Indirect = tt.indirect
+			if got := pkg.RequireLine(); got != tt.expected {
+				t.Errorf("RequireLine() = %v, want %v", got, tt.expected)
+			}
+			if got := pkg.ModuleVersion(); got != "golang.org/x/mod@v0.27.0" {
+				t.Errorf("ModuleVersion() = %v, want golang.org/x/mod@v0.27.0", got)
+			}
+		})
+	}
+}
+
 func TestStarSymbol(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -146,6 +371,63 @@ func TestStarSymbol(t *testing.T) {
 	}
 }
 
+func TestWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		pkg      *Package
+		expected []string
+	}{
+		{
+			name:     "No warnings",
+			pkg:      NewPackage("golang.org/x/mod", "v1.0.0"),
+			expected: nil,
+		},
+		{
+			name: "Deprecated module",
+			pkg: func() *Package {
+				pkg := NewPackage("github.com/golang/protobuf", "v1.5.4")
+				pkg.Deprecated = "Use the google.golang.org/protobuf module instead."
+				return pkg
+			}(),
+			expected: []string{"Deprecated: Use the google.golang.org/protobuf module instead."},
+		},
+		{
+			name: "Retracted version with rationale",
+			pkg: func() *Package {
+				pkg := NewPackage("example.com/retracted", "v1.0.1")
+				pkg.Retracted = true
+				pkg.RetractRationale = "Published accidentally."
+				return pkg
+			}(),
+			expected: []string{"Retracted: v1.0.1 (Published accidentally.)"},
+		},
+		{
+			name: "Deprecated module and retracted version without rationale",
+			pkg: func() *Package {
+				pkg := NewPackage("example.com/retracted", "v1.0.1")
+				pkg.Deprecated = "Unmaintained."
+				pkg.Retracted = true
+				return pkg
+			}(),
+			expected: []string{"Deprecated: Unmaintained.", "Retracted: v1.0.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.pkg.Warnings()
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Warnings() = %v, want %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Warnings()[%d] = %v, want %v", i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
 func TestFormattedSize(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// RepoMetadata holds the health metadata of a GitHub repository
+type RepoMetadata struct {
+	NodeID      string    // GraphQL node ID of the repository
+	Stars       int       // Number of stargazers
+	Forks       int       // Number of forks
+	OpenIssues  int       // Number of open issues
+	PushedAt    time.Time // Time of the last push
+	Archived    bool      // Whether the repository is archived
+	Disabled    bool      // Whether the repository is disabled
+	Language    string    // Primary language
+	License     string    // SPDX ID of the license, or its name if GitHub can't identify it
+	Description string    // Repository description
+
+	Owner            string        // Login of the repository owner
+	OwnerSponsorable bool          // Whether the owner has a GitHub Sponsors listing
+	FundingLinks     []FundingLink // Funding links from the repository's FUNDING.yml
+}
+
+// FundingLink is a place where a repository's maintainers accept funding
+type FundingLink struct {
+	Platform string // Funding platform as named in FUNDING.yml (e.g. github, open_collective, custom)
+	Account  string // Account on the platform, or the URL for custom links
+	URL      string // Page where the maintainers can be funded
+}
+
+// GitHubSponsorsURL returns the GitHub Sponsors page of the user or organization
+func GitHubSponsorsURL(login string) string {
+	return "https://github.com/sponsors/" + login
+}
+
+// Fundable reports whether the repository's maintainers accept funding
+func (r *RepoMetadata) Fundable() bool {
+	return r.OwnerSponsorable || len(r.FundingLinks) > 0
+}
+
+// Sponsors returns the places the repository's maintainers can be funded,
+// starting with the owner's GitHub Sponsors listing
+func (r *RepoMetadata) Sponsors() []FundingLink {
+	var links []FundingLink
+	seen := make(map[string]bool)
+
+	if r.OwnerSponsorable && r.Owner != "" {
+		link := FundingLink{Platform: "github", Account: r.Owner, URL: GitHubSponsorsURL(r.Owner)}
+		links = append(links, link)
+		seen[link.URL] = true
+	}
+
+	for _, link := range r.FundingLinks {
+		if !seen[link.URL] {
+			links = append(links, link)
+			seen[link.URL] = true
+		}
+	}
+
+	return links
+}
+
+// Status returns a warning about the repository's status
+// Returns empty string if the repository is active
+func (r *RepoMetadata) Status() string {
+	switch {
+	case r.Disabled:
+		return "disabled"
+	case r.Archived:
+		return "archived"
+	}
+	return ""
+}
+
+// FormattedCount returns a count in a compact human-readable format (e.g. 1.2k)
+func FormattedCount(n int) string {
+	switch {
+	case n >= 1000000:
+		return fmt.Sprintf("%.1fM", float64(n)/1000000)
+	case n >= 1000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	}
+	return fmt.Sprintf("%d", n)
+}
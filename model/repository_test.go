@@ -0,0 +1,76 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestRepoMetadataStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     RepoMetadata
+		expected string
+	}{
+		{name: "Active repository", repo: RepoMetadata{}, expected: ""},
+		{name: "Archived repository", repo: RepoMetadata{Archived: true}, expected: "archived"},
+		{name: "Disabled repository", repo: RepoMetadata{Archived: true, Disabled: true}, expected: "disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.repo.Status(); got != tt.expected {
+				t.Errorf("Status() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormattedCount(t *testing.T) {
+	tests := []struct {
+		count    int
+		expected string
+	}{
+		{count: 0, expected: "0"},
+		{count: 999, expected: "999"},
+		{count: 1234, expected: "1.2k"},
+		{count: 2500000, expected: "2.5M"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := FormattedCount(tt.count); got != tt.expected {
+				t.Errorf("FormattedCount(%d) = %v, want %v", tt.count, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRepoMetadataSponsors(t *testing.T) {
+	repo := RepoMetadata{
+		Owner:            "charmbracelet",
+		OwnerSponsorable: true,
+		FundingLinks: []FundingLink{
+			{Platform: "github", Account: "charmbracelet", URL: "https://github.com/sponsors/charmbracelet"},
+			{Platform: "open_collective", Account: "charmbracelet", URL: "https://opencollective.com/charmbracelet"},
+		},
+	}
+
+	if !repo.Fundable() {
+		t.Error("Expected repository to be fundable")
+	}
+
+	// The owner's listing isn't repeated when FUNDING.yml also lists it
+	sponsors := repo.Sponsors()
+	expected := []string{"https://github.com/sponsors/charmbracelet", "https://opencollective.com/charmbracelet"}
+	if len(sponsors) != len(expected) {
+		t.Fatalf("Sponsors() = %v, want URLs %v", sponsors, expected)
+	}
+	for i, link := range sponsors {
+		if link.URL != expected[i] {
+			t.Errorf("Sponsors()[%d].URL = %v, want %v", i, link.URL, expected[i])
+		}
+	}
+
+	if (&RepoMetadata{Owner: "someone"}).Fundable() {
+		t.Error("Expected repository without listing or FUNDING.yml not to be fundable")
+	}
+}
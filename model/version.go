@@ -0,0 +1,88 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Version is a module version parsed into its semver parts and pseudo-version metadata
+type Version struct {
+	Raw          string    // Version as written in go.mod
+	Valid        bool      // Whether it's a valid semantic version
+	Major        int       // Major version
+	Minor        int       // Minor version
+	Patch        int       // Patch version
+	Prerelease   string    // Pre-release of the version, or of the base of a pseudo-version, without the leading dash
+	Pseudo       bool      // Whether it's a pseudo-version pointing at an untagged commit
+	Base         string    // Tagged version a pseudo-version is based on, empty if there's none
+	Time         time.Time // Commit time of a pseudo-version
+	Revision     string    // Commit hash of a pseudo-version
+	Incompatible bool      // Whether it's a v2+ version of a module without a go.mod or a major version suffix
+}
+
+// ParseVersion parses a module version. Invalid versions are kept as they are, with Valid unset.
+func ParseVersion(version string) Version {
+	v := Version{Raw: version, Valid: semver.IsValid(version)}
+	if !v.Valid {
+		return v
+	}
+
+	// The canonical form is always vMAJOR.MINOR.PATCH followed by the pre-release and build
+	core := strings.TrimPrefix(semver.Canonical(version), "v")
+	core, _, _ = strings.Cut(core, "-")
+	core, _, _ = strings.Cut(core, "+")
+	parts := strings.Split(core, ".")
+	v.Major, _ = strconv.Atoi(parts[0])
+	v.Minor, _ = strconv.Atoi(parts[1])
+	v.Patch, _ = strconv.Atoi(parts[2])
+
+	v.Incompatible = semver.Build(version) == "+incompatible"
+	v.Prerelease = strings.TrimPrefix(semver.Prerelease(version), "-")
+
+	if module.IsPseudoVersion(version) {
+		v.Pseudo = true
+		v.Base, _ = module.PseudoVersionBase(version)
+		v.Time, _ = module.PseudoVersionTime(version)
+		v.Revision, _ = module.PseudoVersionRev(version)
+		v.Prerelease = strings.TrimPrefix(semver.Prerelease(v.Base), "-")
+	}
+	return v
+}
+
+// IsPrerelease reports whether it's a tagged pre-release, such as v1.2.0-rc.1
+func (v Version) IsPrerelease() bool {
+	return v.Prerelease != "" && !v.Pseudo
+}
+
+// Flags returns the traits of the version that call for a review: a pseudo-version,
+// a +incompatible module or a pre-release
+func (v Version) Flags() []string {
+	var flags []string
+	if v.Pseudo {
+		flags = append(flags, "pseudo-version")
+	}
+	if v.Incompatible {
+		flags = append(flags, "+incompatible")
+	}
+	if v.IsPrerelease() {
+		flags = append(flags, "pre-release")
+	}
+	return flags
+}
+
+// Compare compares the versions by semver precedence, with invalid versions before every valid one.
+// Pseudo-versions sort between the version they're based on and the next release,
+// and two pseudo-versions of the same base by commit time, then revision.
+func (v Version) Compare(w Version) int {
+	if v.Pseudo && w.Pseudo && v.Base == w.Base {
+		if c := v.Time.Compare(w.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(v.Revision, w.Revision)
+	}
+	return semver.Compare(v.Raw, w.Raw)
+}
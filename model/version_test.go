@@ -0,0 +1,143 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		expected Version
+		flags    []string
+	}{
+		{
+			name:     "Release",
+			version:  "v1.2.3",
+			expected: Version{Raw: "v1.2.3", Valid: true, Major: 1, Minor: 2, Patch: 3},
+		},
+		{
+			name:     "Pre-release",
+			version:  "v2.0.0-rc.1",
+			expected: Version{Raw: "v2.0.0-rc.1", Valid: true, Major: 2, Prerelease: "rc.1"},
+			flags:    []string{"pre-release"},
+		},
+		{
+			name:    "Pseudo-version without a tag before it",
+			version: "v0.0.0-20240102030405-0123456789ab",
+			expected: Version{
+				Raw: "v0.0.0-20240102030405-0123456789ab", Valid: true, Pseudo: true,
+				Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Revision: "0123456789ab",
+			},
+			flags: []string{"pseudo-version"},
+		},
+		{
+			name:    "Pseudo-version after a release",
+			version: "v1.2.4-0.20240102030405-0123456789ab",
+			expected: Version{
+				Raw: "v1.2.4-0.20240102030405-0123456789ab", Valid: true, Major: 1, Minor: 2, Patch: 4, Pseudo: true,
+				Base: "v1.2.3", Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Revision: "0123456789ab",
+			},
+			flags: []string{"pseudo-version"},
+		},
+		{
+			name:    "Pseudo-version after a pre-release",
+			version: "v1.3.0-beta.2.0.20240102030405-0123456789ab",
+			expected: Version{
+				Raw: "v1.3.0-beta.2.0.20240102030405-0123456789ab", Valid: true, Major: 1, Minor: 3, Prerelease: "beta.2", Pseudo: true,
+				Base: "v1.3.0-beta.2", Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Revision: "0123456789ab",
+			},
+			flags: []string{"pseudo-version"},
+		},
+		{
+			name:     "Incompatible version",
+			version:  "v4.5.0+incompatible",
+			expected: Version{Raw: "v4.5.0+incompatible", Valid: true, Major: 4, Minor: 5, Incompatible: true},
+			flags:    []string{"+incompatible"},
+		},
+		{
+			name:     "Invalid version",
+			version:  "latest",
+			expected: Version{Raw: "latest"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseVersion(tt.version)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseVersion() = %+v, want %+v", got, tt.expected)
+			}
+			if flags := got.Flags(); !reflect.DeepEqual(flags, tt.flags) {
+				t.Errorf("Flags() = %v, want %v", flags, tt.flags)
+			}
+		})
+	}
+}
+
+func TestOutdated(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		latest   string
+		expected bool
+	}{
+		{name: "Unknown latest version", version: "v1.0.0", latest: "", expected: false},
+		{name: "Latest version", version: "v1.0.0", latest: "v1.0.0", expected: false},
+		{name: "Newer release", version: "v1.0.0", latest: "v1.1.0", expected: true},
+		{name: "Pseudo-version before the latest release", version: "v1.0.1-0.20240102030405-0123456789ab", latest: "v1.1.0", expected: true},
+		{name: "Pseudo-version after the latest release", version: "v1.1.1-0.20240102030405-0123456789ab", latest: "v1.1.0", expected: false},
+		{name: "Incompatible version", version: "v4.5.0+incompatible", latest: "v4.6.0+incompatible", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := NewPackage("github.com/owner/repo", tt.version)
+			pkg.LatestVersion = tt.latest
+			if got := pkg.Outdated(); got != tt.expected {
+				t.Errorf("Outdated() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		v, w     string
+		expected int
+	}{
+		{name: "Same release", v: "v1.2.3", w: "v1.2.3", expected: 0},
+		{name: "Older release", v: "v1.2.3", w: "v1.10.0", expected: -1},
+		{name: "Pre-release before its release", v: "v2.0.0-rc.1", w: "v2.0.0", expected: -1},
+		{name: "Pseudo-version after its base", v: "v1.2.4-0.20240102030405-0123456789ab", w: "v1.2.3", expected: 1},
+		{name: "Pseudo-version before the next release", v: "v1.2.4-0.20240102030405-0123456789ab", w: "v1.2.4", expected: -1},
+		{name: "Pseudo-versions of the same base by time", v: "v1.2.4-0.20240102030405-fedcba987654", w: "v1.2.4-0.20240203040506-0123456789ab", expected: -1},
+		{name: "Pseudo-versions of the same commit time by revision", v: "v0.0.0-20240102030405-fedcba987654", w: "v0.0.0-20240102030405-0123456789ab", expected: 1},
+		{name: "Pseudo-versions of different bases", v: "v1.3.1-0.20200101000000-0123456789ab", w: "v1.2.4-0.20240102030405-0123456789ab", expected: 1},
+		{name: "Invalid version first", v: "master", w: "v0.0.1", expected: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseVersion(tt.v).Compare(ParseVersion(tt.w)); got != tt.expected {
+				t.Errorf("Compare() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestVersionDate(t *testing.T) {
+	pkg := NewPackage("github.com/owner/repo", "v0.0.0-20240102030405-0123456789ab")
+	if got := pkg.VersionDate(); !got.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("VersionDate() = %v, want the commit time", got)
+	}
+
+	published := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	pkg.VersionTime = published
+	if got := pkg.VersionDate(); !got.Equal(published) {
+		t.Errorf("VersionDate() = %v, want the publication time", got)
+	}
+}
@@ -1,11 +1,11 @@
 package parser
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/tnagatomi/gh-lsmod/model"
-	"github.com/tnagatomi/gh-lsmod/size"
 	"golang.org/x/mod/modfile"
 )
 
@@ -30,8 +30,33 @@ func NewParserForCurrentDirectory() (*GoModParser, error) {
 	return NewGoModParser(filepath.Join(cwd, "go.mod")), nil
 }
 
-// Parse parses the go.mod file and returns a list of direct dependencies
+// ModulePath returns the path of the module declared in the go.mod file
+func (p *GoModParser) ModulePath() (string, error) {
+	data, err := os.ReadFile(p.filePath)
+	if err != nil {
+		return "", err
+	}
+
+	path := modfile.ModulePath(data)
+	if path == "" {
+		return "", fmt.Errorf("no module directive in %s", p.filePath)
+	}
+	return path, nil
+}
+
+// Parse parses the go.mod file and returns a list of direct dependencies.
+// Sizes and other metadata aren't filled in, so that parsing stays fast.
 func (p *GoModParser) Parse() ([]*model.Package, error) {
+	return p.parse(false)
+}
+
+// ParseAll parses the go.mod file and returns a list of direct and indirect dependencies
+func (p *GoModParser) ParseAll() ([]*model.Package, error) {
+	return p.parse(true)
+}
+
+// parse parses the go.mod file, including indirect dependencies if requested
+func (p *GoModParser) parse(includeIndirect bool) ([]*model.Package, error) {
 	data, err := os.ReadFile(p.filePath)
 	if err != nil {
 		return nil, err
@@ -44,18 +69,14 @@ func (p *GoModParser) Parse() ([]*model.Package, error) {
 
 	var packages []*model.Package
 
-	// Add direct requires
+	// Add direct requires, and indirect ones if requested
 	for _, req := range file.Require {
-		if !req.Indirect {
-			pkg := model.NewPackage(req.Mod.Path, req.Mod.Version)
-
-			pkgSize, err := size.CalculatePackageSize(pkg)
-			if err == nil {
-				pkg.Size = pkgSize
-			}
-
-			packages = append(packages, pkg)
+		if req.Indirect && !includeIndirect {
+			continue
 		}
+		pkg := model.NewPackage(req.Mod.Path, req.Mod.Version)
+		pkg.Indirect = req.Indirect
+		packages = append(packages, pkg)
 	}
 
 	return packages, nil
@@ -69,6 +69,30 @@ require (
 		}
 	}
 
+	// Verify that indirect dependencies are included on request
+	allPackages, err := parser.ParseAll()
+	if err != nil {
+		t.Fatalf("ParseAll() returned an error: %v", err)
+	}
+	if len(allPackages) != 4 {
+		t.Fatalf("Expected 4 packages, got %d", len(allPackages))
+	}
+	if allPackages[0].Indirect {
+		t.Errorf("Expected %s to be direct", allPackages[0].Path)
+	}
+	if last := allPackages[3]; last.Path != "github.com/charmbracelet/x/ansi" || !last.Indirect {
+		t.Errorf("Expected the last package to be the indirect github.com/charmbracelet/x/ansi, got %s (indirect: %v)", last.Path, last.Indirect)
+	}
+
+	// Verify the module path
+	modulePath, err := parser.ModulePath()
+	if err != nil {
+		t.Fatalf("ModulePath() returned an error: %v", err)
+	}
+	if modulePath != "github.com/tnagatomi/gh-lsmod" {
+		t.Errorf("ModulePath() = %s, want github.com/tnagatomi/gh-lsmod", modulePath)
+	}
+
 	// Test parsing a non-existent file
 	nonExistentParser := NewGoModParser(filepath.Join(tempDir, "non-existent.mod"))
 	_, err = nonExistentParser.Parse()
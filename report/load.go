@@ -0,0 +1,58 @@
+package report
+
+import (
+	"errors"
+
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/metadata"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// Load fills in the sizes, module status and GitHub metadata of the packages, as the TUI loads them
+// in the background. A nil module status checker or GitHub client skips that metadata.
+// Metadata that fails to load is left unknown, and the errors are returned.
+func Load(packages []*model.Package, checker metadata.ModuleStatusChecker, githubClient github.GitHubClient) []error {
+	// Errors are kept by package, so that they're reported in go.mod order
+	statusErrs := make([]error, len(packages))
+	metadata.ForEach(len(packages), metadata.Concurrency, func(i int) {
+		pkg := packages[i]
+		// Modules that haven't been downloaded have no size, which isn't worth reporting
+		if size, err := metadata.CalculateSize(pkg); err == nil {
+			pkg.Size = size
+		}
+		if checker == nil {
+			return
+		}
+		statusErrs[i] = checker.UpdateModuleStatus(pkg)
+	})
+
+	var errs []error
+	for _, err := range statusErrs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if githubClient != nil {
+		var githubPackages []*model.Package
+		for _, pkg := range packages {
+			if pkg.IsGitHub {
+				githubPackages = append(githubPackages, pkg)
+			}
+		}
+		if len(githubPackages) > 0 {
+			if err := githubClient.FetchRepositories(githubPackages); err != nil {
+				// Repositories that failed are listed separately, so each gets its own error
+				var pkgErrs github.PackageErrors
+				if errors.As(err, &pkgErrs) {
+					for _, pkgErr := range pkgErrs {
+						errs = append(errs, pkgErr)
+					}
+				} else {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/metadata"
+	"github.com/tnagatomi/gh-lsmod/model"
+	"github.com/tnagatomi/gh-lsmod/size"
+)
+
+// fakeChecker sets a latest version for every module except the failing ones
+type fakeChecker struct {
+	failing map[string]error
+}
+
+// UpdateModuleStatus fakes looking up the module status
+func (f *fakeChecker) UpdateModuleStatus(pkg *model.Package) error {
+	if err := f.failing[pkg.Path]; err != nil {
+		return err
+	}
+	pkg.LatestVersion = "v9.9.9"
+	return nil
+}
+
+// fakeGitHub fetches repositories without calling GitHub.
+// Methods Load doesn't use panic through the nil embedded interface.
+type fakeGitHub struct {
+	github.GitHubClient
+	fetched []*model.Package
+	failing map[string]error
+}
+
+// FetchRepositories fakes fetching the repository metadata
+func (f *fakeGitHub) FetchRepositories(packages []*model.Package) error {
+	f.fetched = packages
+	var errs github.PackageErrors
+	for _, pkg := range packages {
+		if err := f.failing[pkg.Path]; err != nil {
+			errs = append(errs, &github.PackageError{Package: pkg, Err: err})
+			continue
+		}
+		pkg.Repo = &model.RepoMetadata{Stars: 42}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func TestLoad(t *testing.T) {
+	metadata.CalculateSize = func(pkg *model.Package) (int64, error) {
+		if pkg.Path == "golang.org/x/mod" {
+			return 0, errors.New("not in the module cache")
+		}
+		return 2048, nil
+	}
+	defer func() { metadata.CalculateSize = size.CalculatePackageSize }()
+
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	missing := model.NewPackage("github.com/example/missing", "v1.0.0")
+	mod := model.NewPackage("golang.org/x/mod", "v0.27.0")
+	packages := []*model.Package{bubbles, missing, mod}
+
+	checker := &fakeChecker{failing: map[string]error{mod.Path: errors.New("proxy unavailable")}}
+	client := &fakeGitHub{failing: map[string]error{missing.Path: github.ErrRepositoryNotFound}}
+	errs := Load(packages, checker, client)
+
+	if bubbles.Size != 2048 || mod.Size != 0 {
+		t.Errorf("Expected sizes 2048 and 0, got %d and %d", bubbles.Size, mod.Size)
+	}
+	if bubbles.LatestVersion != "v9.9.9" || mod.LatestVersion != "" {
+		t.Errorf("Expected the module status of all but the failing module, got %q and %q", bubbles.LatestVersion, mod.LatestVersion)
+	}
+	if len(client.fetched) != 2 {
+		t.Errorf("Expected only the GitHub-hosted packages to be fetched, got %d", len(client.fetched))
+	}
+	if bubbles.Repo == nil || missing.Repo != nil {
+		t.Errorf("Expected metadata for all but the missing repository")
+	}
+
+	// The module status error and the repository error are reported separately
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", errs)
+	}
+	if !errors.Is(errs[1], github.ErrRepositoryNotFound) {
+		t.Errorf("Expected the missing repository to be reported, got %v", errs[1])
+	}
+}
+
+func TestLoadWithoutClients(t *testing.T) {
+	metadata.CalculateSize = func(pkg *model.Package) (int64, error) {
+		return 1024, nil
+	}
+	defer func() { metadata.CalculateSize = size.CalculatePackageSize }()
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	if errs := Load([]*model.Package{pkg}, nil, nil); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+	if pkg.Size != 1024 || pkg.Repo != nil || pkg.LatestVersion != "" {
+		t.Errorf("Expected only the size to be loaded, got %+v", pkg)
+	}
+}
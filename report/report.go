@@ -0,0 +1,225 @@
+// Package report writes the dependencies of a module as JSON for scripts and CI
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// SchemaVersion is the version of the JSON schema.
+// It's increased when a field is removed or changes meaning; new fields keep the version.
+const SchemaVersion = 1
+
+// Report is the JSON document listing the dependencies of a module
+type Report struct {
+	SchemaVersion int       `json:"schema_version"`
+	Module        string    `json:"module"`   // Path of the module whose go.mod was read
+	Packages      []Package `json:"packages"` // Dependencies in go.mod order
+	Errors        []string  `json:"errors"`   // Metadata that couldn't be loaded
+}
+
+// Package is a dependency with its computed fields and the metadata loaded for it.
+// Unknown values are null rather than left out, so that every key is always present.
+type Package struct {
+	Path             string     `json:"path"`
+	Version          string     `json:"version"`
+	VersionInfo      Version    `json:"version_info"`
+	VersionTime      *time.Time `json:"version_time"` // When the version was published
+	LatestVersion    *string    `json:"latest_version"`
+	Outdated         bool       `json:"outdated"`
+	Host             string     `json:"host"`
+	Indirect         bool       `json:"indirect"`
+	Size             *int64     `json:"size"` // In bytes, null if the module isn't in the module cache
+	Deprecated       *string    `json:"deprecated"`
+	Retracted        bool       `json:"retracted"`
+	RetractRationale *string    `json:"retract_rationale"`
+	Warnings         []string   `json:"warnings"`
+	URLs             URLs       `json:"urls"`
+	GitHub           *GitHub    `json:"github"` // Null if the module isn't hosted on GitHub
+}
+
+// Version is the pinned version parsed into its parts
+type Version struct {
+	Valid        bool       `json:"valid"`
+	Major        int        `json:"major"`
+	Minor        int        `json:"minor"`
+	Patch        int        `json:"patch"`
+	Prerelease   *string    `json:"prerelease"`
+	Pseudo       bool       `json:"pseudo"`
+	Base         *string    `json:"base"`     // Tagged version a pseudo-version is based on
+	Time         *time.Time `json:"time"`     // Commit time of a pseudo-version
+	Revision     *string    `json:"revision"` // Commit hash of a pseudo-version
+	Incompatible bool       `json:"incompatible"`
+	Flags        []string   `json:"flags"`
+}
+
+// URLs are the links to the module at the pinned version
+type URLs struct {
+	PkgGoDev string  `json:"pkg_go_dev"`
+	GitHub   *string `json:"github"`
+	Compare  *string `json:"compare"` // Compare view between the pinned and the latest version
+}
+
+// GitHub is the repository of a module hosted on GitHub
+type GitHub struct {
+	Repo       string      `json:"repo"`   // owner/name
+	Subdir     string      `json:"subdir"` // Directory of the module in the repository
+	Ref        string      `json:"ref"`    // Tag or commit of the pinned version
+	Starred    *bool       `json:"starred"`
+	Watched    *bool       `json:"watched"`
+	Ignored    *bool       `json:"ignored"`    // Whether the user chose to ignore the repository's notifications
+	Repository *Repository `json:"repository"` // Null if the metadata wasn't fetched
+}
+
+// Repository is the metadata of a GitHub repository
+type Repository struct {
+	Description  string        `json:"description"`
+	Stars        int           `json:"stars"`
+	Forks        int           `json:"forks"`
+	OpenIssues   int           `json:"open_issues"`
+	PushedAt     *time.Time    `json:"pushed_at"`
+	Archived     bool          `json:"archived"`
+	Disabled     bool          `json:"disabled"`
+	Language     string        `json:"language"`
+	License      string        `json:"license"`
+	Owner        string        `json:"owner"`
+	Sponsorable  bool          `json:"sponsorable"`
+	FundingLinks []FundingLink `json:"funding_links"`
+}
+
+// FundingLink is a place where the maintainers of a repository accept funding
+type FundingLink struct {
+	Platform string `json:"platform"`
+	Account  string `json:"account"`
+	URL      string `json:"url"`
+}
+
+// New creates the report of the module's dependencies and the errors that occurred while loading their metadata
+func New(module string, packages []*model.Package, errs []error) Report {
+	report := Report{
+		SchemaVersion: SchemaVersion,
+		Module:        module,
+		Packages:      make([]Package, 0, len(packages)),
+		Errors:        make([]string, 0, len(errs)),
+	}
+	for _, pkg := range packages {
+		report.Packages = append(report.Packages, newPackage(pkg))
+	}
+	for _, err := range errs {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	return report
+}
+
+// newPackage converts a package into its JSON form
+func newPackage(pkg *model.Package) Package {
+	version := pkg.ParsedVersion()
+	p := Package{
+		Path:    pkg.Path,
+		Version: pkg.Version,
+		VersionInfo: Version{
+			Valid:        version.Valid,
+			Major:        version.Major,
+			Minor:        version.Minor,
+			Patch:        version.Patch,
+			Prerelease:   optional(version.Prerelease),
+			Pseudo:       version.Pseudo,
+			Base:         optional(version.Base),
+			Time:         optionalTime(version.Time),
+			Revision:     optional(version.Revision),
+			Incompatible: version.Incompatible,
+			Flags:        nonNil(version.Flags()),
+		},
+		VersionTime:      optionalTime(pkg.VersionTime),
+		LatestVersion:    optional(pkg.LatestVersion),
+		Outdated:         pkg.Outdated(),
+		Host:             pkg.Host(),
+		Indirect:         pkg.Indirect,
+		Deprecated:       optional(pkg.Deprecated),
+		Retracted:        pkg.Retracted,
+		RetractRationale: optional(pkg.RetractRationale),
+		Warnings:         nonNil(pkg.Warnings()),
+		URLs: URLs{
+			PkgGoDev: pkg.PkgGoDevURL(),
+			GitHub:   optional(pkg.GitHubURL()),
+			Compare:  optional(pkg.CompareURL()),
+		},
+	}
+	if pkg.Size > 0 {
+		p.Size = &pkg.Size
+	}
+	if pkg.IsGitHub {
+		p.GitHub = newGitHub(pkg)
+	}
+	return p
+}
+
+// newGitHub converts the GitHub repository of a package into its JSON form.
+// The star and watch status are only known once the repository has been fetched.
+func newGitHub(pkg *model.Package) *GitHub {
+	github := &GitHub{
+		Repo:   pkg.GitHubRepoPath(),
+		Subdir: pkg.GitHubSubdir(),
+		Ref:    pkg.GitRef(),
+	}
+	repo := pkg.Repo
+	if repo == nil {
+		return github
+	}
+
+	github.Starred = &pkg.IsStarred
+	github.Watched = &pkg.IsWatched
+	github.Ignored = &pkg.IsIgnored
+	github.Repository = &Repository{
+		Description:  repo.Description,
+		Stars:        repo.Stars,
+		Forks:        repo.Forks,
+		OpenIssues:   repo.OpenIssues,
+		PushedAt:     optionalTime(repo.PushedAt),
+		Archived:     repo.Archived,
+		Disabled:     repo.Disabled,
+		Language:     repo.Language,
+		License:      repo.License,
+		Owner:        repo.Owner,
+		Sponsorable:  repo.OwnerSponsorable,
+		FundingLinks: make([]FundingLink, 0, len(repo.FundingLinks)),
+	}
+	for _, link := range repo.FundingLinks {
+		github.Repository.FundingLinks = append(github.Repository.FundingLinks, FundingLink(link))
+	}
+	return github
+}
+
+// Write writes the report as indented JSON
+func Write(w io.Writer, report Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// optional returns nil for an empty string, which is written as null
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// optionalTime returns nil for the zero time, which is written as null
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// nonNil returns an empty slice for nil, so that it's written as [] rather than null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,180 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// decode writes the report and decodes it into generic maps, as a consumer of the JSON would
+func decode(t *testing.T, report Report) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Write(&buf, report); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Failed to decode JSON: %v\n%s", err, buf.String())
+	}
+	return doc
+}
+
+func TestNewWithoutMetadata(t *testing.T) {
+	pkg := model.NewPackage("golang.org/x/mod", "v0.27.0")
+	doc := decode(t, New("example.com/app", []*model.Package{pkg}, nil))
+
+	if doc["schema_version"] != float64(SchemaVersion) {
+		t.Errorf("schema_version = %v, want %d", doc["schema_version"], SchemaVersion)
+	}
+	if doc["module"] != "example.com/app" {
+		t.Errorf("module = %v, want example.com/app", doc["module"])
+	}
+	if errs, ok := doc["errors"].([]any); !ok || len(errs) != 0 {
+		t.Errorf("errors = %v, want []", doc["errors"])
+	}
+
+	packages := doc["packages"].([]any)
+	if len(packages) != 1 {
+		t.Fatalf("Expected 1 package, got %d", len(packages))
+	}
+	p := packages[0].(map[string]any)
+
+	// Every key is present, with null for what isn't known
+	for _, key := range []string{"version_time", "latest_version", "size", "deprecated", "retract_rationale", "github"} {
+		value, ok := p[key]
+		if !ok {
+			t.Errorf("Expected key %q to be present", key)
+		} else if value != nil {
+			t.Errorf("%s = %v, want null", key, value)
+		}
+	}
+	if p["host"] != "golang.org" {
+		t.Errorf("host = %v, want golang.org", p["host"])
+	}
+	if p["outdated"] != false {
+		t.Errorf("outdated = %v, want false", p["outdated"])
+	}
+	if warnings, ok := p["warnings"].([]any); !ok || len(warnings) != 0 {
+		t.Errorf("warnings = %v, want []", p["warnings"])
+	}
+	urls := p["urls"].(map[string]any)
+	if urls["pkg_go_dev"] != "https://pkg.go.dev/golang.org/x/mod@v0.27.0" {
+		t.Errorf("urls.pkg_go_dev = %v", urls["pkg_go_dev"])
+	}
+	if urls["github"] != nil || urls["compare"] != nil {
+		t.Errorf("Expected null GitHub and compare URLs, got %v", urls)
+	}
+}
+
+func TestNewVersionInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		expected Version
+	}{
+		{
+			name:    "Release",
+			version: "v1.2.3",
+			expected: Version{
+				Valid: true, Major: 1, Minor: 2, Patch: 3, Flags: []string{},
+			},
+		},
+		{
+			name:    "Pre-release",
+			version: "v2.0.0-rc.1",
+			expected: Version{
+				Valid: true, Major: 2, Prerelease: ptr("rc.1"), Flags: []string{"pre-release"},
+			},
+		},
+		{
+			name:    "Pseudo-version",
+			version: "v0.0.0-20240101120000-abcdef123456",
+			expected: Version{
+				Valid:    true,
+				Pseudo:   true,
+				Time:     ptr(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+				Revision: ptr("abcdef123456"),
+				Flags:    []string{"pseudo-version"},
+			},
+		},
+		{
+			name:     "Invalid",
+			version:  "latest",
+			expected: Version{Flags: []string{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newPackage(model.NewPackage("golang.org/x/mod", tt.version)).VersionInfo
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(tt.expected)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("version_info = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestNewGitHub(t *testing.T) {
+	pkg := model.NewPackage("github.com/cli/go-gh/v2", "v2.11.0")
+
+	// Before the repository is fetched, only what's derived from the path is known
+	github := newPackage(pkg).GitHub
+	if github == nil {
+		t.Fatal("Expected GitHub data for a GitHub-hosted module")
+	}
+	if github.Repo != "cli/go-gh" || github.Ref != "v2.11.0" {
+		t.Errorf("Unexpected repo or ref: %+v", github)
+	}
+	if github.Starred != nil || github.Watched != nil || github.Ignored != nil || github.Repository != nil {
+		t.Errorf("Expected unknown star status and metadata, got %+v", github)
+	}
+
+	pkg.IsStarred = true
+	pkg.Size = 4096
+	pkg.LatestVersion = "v2.12.0"
+	pkg.Repo = &model.RepoMetadata{
+		Stars:        100,
+		PushedAt:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		License:      "MIT",
+		FundingLinks: []model.FundingLink{{Platform: "github", Account: "cli", URL: "https://github.com/sponsors/cli"}},
+	}
+	p := newPackage(pkg)
+	if p.Size == nil || *p.Size != 4096 {
+		t.Errorf("size = %v, want 4096", p.Size)
+	}
+	if !p.Outdated || p.URLs.Compare == nil {
+		t.Errorf("Expected an outdated package with a compare URL, got %+v", p)
+	}
+	if p.GitHub.Starred == nil || !*p.GitHub.Starred || p.GitHub.Watched == nil || *p.GitHub.Watched {
+		t.Errorf("Expected starred and not watched, got %v and %v", p.GitHub.Starred, p.GitHub.Watched)
+	}
+	repo := p.GitHub.Repository
+	if repo == nil || repo.Stars != 100 || repo.License != "MIT" || repo.PushedAt == nil {
+		t.Fatalf("Unexpected repository: %+v", repo)
+	}
+	if len(repo.FundingLinks) != 1 || repo.FundingLinks[0].URL != "https://github.com/sponsors/cli" {
+		t.Errorf("Unexpected funding links: %+v", repo.FundingLinks)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	report := New("example.com/app", nil, []error{errors.New("proxy unavailable")})
+	if len(report.Packages) != 0 || report.Packages == nil {
+		t.Errorf("Expected an empty package list, got %v", report.Packages)
+	}
+	if len(report.Errors) != 1 || report.Errors[0] != "proxy unavailable" {
+		t.Errorf("errors = %v, want [proxy unavailable]", report.Errors)
+	}
+}
+
+// ptr returns a pointer to the value
+func ptr[T any](value T) *T {
+	return &value
+}
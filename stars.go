@@ -0,0 +1,135 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/parser"
+	"github.com/tnagatomi/gh-lsmod/stars"
+	"github.com/tnagatomi/gh-lsmod/ui"
+)
+
+const starsUsage = `Usage:
+  gh lsmod stars sync [--dry-run] [--yes]   Unstar repositories starred by gh-lsmod that the project no longer depends on`
+
+// maxPreviewRepos is the number of repositories listed in the sync preview dialog
+const maxPreviewRepos = 10
+
+// runStars runs the stars subcommand
+func runStars(args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Println(starsUsage)
+		return nil
+	}
+	if args[0] != "sync" {
+		return fmt.Errorf("unknown stars command %q\n\n%s", args[0], starsUsage)
+	}
+
+	flags := flag.NewFlagSet("stars sync", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "show the stars that would be removed without removing them")
+	yes := flags.Bool("yes", false, "remove the stars without asking for confirmation")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	gomodParser, err := parser.NewParserForCurrentDirectory()
+	if err != nil {
+		return err
+	}
+	project, err := gomodParser.ModulePath()
+	if err != nil {
+		return err
+	}
+	// Stars can be added to indirect dependencies from the TUI with --all,
+	// so every module in go.mod counts as still in use
+	packages, err := gomodParser.ParseAll()
+	if err != nil {
+		return err
+	}
+
+	statePath, err := stars.DefaultStatePath()
+	if err != nil {
+		return err
+	}
+	state, err := stars.Load(statePath)
+	if err != nil {
+		return err
+	}
+
+	plan := state.PlanSync(project, packages)
+	if plan.Empty() {
+		fmt.Printf("All stars added by gh-lsmod for %s are still in use.\n", project)
+		return nil
+	}
+
+	if *dryRun {
+		for _, repo := range plan.Unstar {
+			fmt.Printf("Would unstar %s\n", repo)
+		}
+		for _, repo := range plan.Keep {
+			fmt.Printf("Would keep %s (starred for another project)\n", repo)
+		}
+		return nil
+	}
+
+	if len(plan.Unstar) > 0 && !*yes {
+		confirmed, err := ui.Confirm(
+			fmt.Sprintf("Unstar %d repositories %s no longer uses?", len(plan.Unstar), project),
+			previewRepos(plan.Unstar),
+		)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return nil
+		}
+	}
+
+	githubClient, err := github.NewClient()
+	if err != nil {
+		return err
+	}
+
+	count, err := state.ApplySync(githubClient, project, plan)
+	fmt.Printf("Unstarred %d repositories\n", count)
+	return err
+}
+
+// previewRepos lists the repositories for the preview dialog, abbreviating long lists
+func previewRepos(repos []string) string {
+	if len(repos) <= maxPreviewRepos {
+		return strings.Join(repos, "\n")
+	}
+	return strings.Join(repos[:maxPreviewRepos], "\n") + fmt.Sprintf("\n…and %d more", len(repos)-maxPreviewRepos)
+}
+
+// recordingClient wraps the client so that stars it adds are recorded for the project, to be synced later.
+// Stars are still managed, just not recorded, if the project or the state can't be read.
+func recordingClient(githubClient github.GitHubClient, gomodParser *parser.GoModParser) github.GitHubClient {
+	client, err := newRecordingClient(githubClient, gomodParser)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: stars won't be recorded for stars sync: %v\n", err)
+		return githubClient
+	}
+	return client
+}
+
+// newRecordingClient loads the star state and wraps the client to record stars for the project
+func newRecordingClient(githubClient github.GitHubClient, gomodParser *parser.GoModParser) (github.GitHubClient, error) {
+	project, err := gomodParser.ModulePath()
+	if err != nil {
+		return nil, err
+	}
+	statePath, err := stars.DefaultStatePath()
+	if err != nil {
+		return nil, err
+	}
+	state, err := stars.Load(statePath)
+	if err != nil {
+		return nil, err
+	}
+	return stars.NewRecordingClient(githubClient, state, project), nil
+}
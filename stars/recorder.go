@@ -0,0 +1,122 @@
+package stars
+
+import (
+	"sync"
+
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// RecordingClient is a GitHub client that records the stars it adds and removes in the project's state
+type RecordingClient struct {
+	github.GitHubClient
+	mu      sync.Mutex
+	state   *State
+	project string
+}
+
+// NewRecordingClient wraps the client so that stars are recorded for the project
+func NewRecordingClient(client github.GitHubClient, state *State, project string) *RecordingClient {
+	return &RecordingClient{
+		GitHubClient: client,
+		state:        state,
+		project:      project,
+	}
+}
+
+// StarRepository stars a repository and records it
+func (c *RecordingClient) StarRepository(pkg *model.Package) error {
+	wasStarred := pkg.IsStarred
+	if err := c.GitHubClient.StarRepository(pkg); err != nil {
+		return err
+	}
+	if wasStarred {
+		return nil
+	}
+	return c.record([]*model.Package{pkg})
+}
+
+// UnstarRepository unstars a repository and forgets it
+func (c *RecordingClient) UnstarRepository(pkg *model.Package) error {
+	if err := c.GitHubClient.UnstarRepository(pkg); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state.Forget(c.project, pkg.GitHubRepoPath())
+	return c.state.Save()
+}
+
+// StarRepositories stars the repositories and records the ones that were starred
+func (c *RecordingClient) StarRepositories(packages []*model.Package) (int, error) {
+	unstarred := unstarredPackages(packages)
+	count, err := c.GitHubClient.StarRepositories(packages)
+	if recordErr := c.record(unstarred); recordErr != nil && err == nil {
+		err = recordErr
+	}
+	return count, err
+}
+
+// UnstarRepositories unstars the repositories and forgets the ones that were unstarred
+func (c *RecordingClient) UnstarRepositories(packages []*model.Package) (int, error) {
+	var starred []*model.Package
+	for _, pkg := range packages {
+		if pkg.IsGitHub && pkg.IsStarred {
+			starred = append(starred, pkg)
+		}
+	}
+
+	count, err := c.GitHubClient.UnstarRepositories(packages)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, pkg := range starred {
+		if !pkg.IsStarred {
+			c.state.Forget(c.project, pkg.GitHubRepoPath())
+		}
+	}
+	if saveErr := c.state.Save(); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	return count, err
+}
+
+// AddToStarList adds repositories to a star list and records the ones that were starred for it
+func (c *RecordingClient) AddToStarList(list *github.StarList, packages []*model.Package) error {
+	unstarred := unstarredPackages(packages)
+	err := c.GitHubClient.AddToStarList(list, packages)
+	if recordErr := c.record(unstarred); recordErr != nil && err == nil {
+		err = recordErr
+	}
+	return err
+}
+
+// record records the packages that are now starred
+func (c *RecordingClient) record(packages []*model.Package) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	changed := false
+	for _, pkg := range packages {
+		if pkg.IsStarred {
+			c.state.Record(c.project, pkg.GitHubRepoPath())
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return c.state.Save()
+}
+
+// unstarredPackages returns the GitHub packages that aren't starred yet
+func unstarredPackages(packages []*model.Package) []*model.Package {
+	var unstarred []*model.Package
+	for _, pkg := range packages {
+		if pkg.IsGitHub && !pkg.IsStarred {
+			unstarred = append(unstarred, pkg)
+		}
+	}
+	return unstarred
+}
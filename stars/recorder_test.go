@@ -0,0 +1,85 @@
+package stars
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// fakeClient is a GitHub client whose star actions succeed except for the failing repositories
+type fakeClient struct {
+	github.GitHubClient
+	failing   map[string]bool
+	unstarred []string
+}
+
+func (f *fakeClient) StarRepository(pkg *model.Package) error {
+	if f.failing[pkg.GitHubRepoPath()] {
+		return errors.New("star failed")
+	}
+	pkg.IsStarred = true
+	return nil
+}
+
+func (f *fakeClient) UnstarRepository(pkg *model.Package) error {
+	if f.failing[pkg.GitHubRepoPath()] {
+		return errors.New("unstar failed")
+	}
+	f.unstarred = append(f.unstarred, pkg.GitHubRepoPath())
+	pkg.IsStarred = false
+	return nil
+}
+
+func (f *fakeClient) StarRepositories(packages []*model.Package) (int, error) {
+	count := 0
+	for _, pkg := range packages {
+		if pkg.IsGitHub && !pkg.IsStarred && f.StarRepository(pkg) == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func TestRecordingClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stars.json")
+	state, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() returned an error: %v", err)
+	}
+	client := NewRecordingClient(&fakeClient{failing: map[string]bool{"broken/repo": true}}, state, "example.com/app")
+
+	// Stars that already existed weren't added by gh-lsmod
+	alreadyStarred := model.NewPackage("github.com/owner/starred", "v1.0.0")
+	alreadyStarred.IsStarred = true
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	packages := []*model.Package{
+		alreadyStarred,
+		bubbles,
+		model.NewPackage("github.com/charmbracelet/lipgloss", "v1.0.0"),
+		model.NewPackage("github.com/broken/repo", "v1.0.0"),
+	}
+	if _, err := client.StarRepositories(packages); err != nil {
+		t.Fatalf("StarRepositories() returned an error: %v", err)
+	}
+
+	saved, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() returned an error: %v", err)
+	}
+	want := []string{"charmbracelet/bubbles", "charmbracelet/lipgloss"}
+	if got := saved.Stars("example.com/app"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Recorded stars = %v, want %v", got, want)
+	}
+
+	if err := client.UnstarRepository(bubbles); err != nil {
+		t.Fatalf("UnstarRepository() returned an error: %v", err)
+	}
+	want = []string{"charmbracelet/lipgloss"}
+	if got := state.Stars("example.com/app"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Recorded stars after unstarring = %v, want %v", got, want)
+	}
+}
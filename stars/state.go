@@ -0,0 +1,171 @@
+package stars
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// State records the repositories starred through gh-lsmod for each project,
+// so that stars for dependencies that are no longer used can be removed later
+type State struct {
+	path     string
+	Projects map[string]*Project `json:"projects"`
+}
+
+// Project is the state of a single project, identified by its module path
+type Project struct {
+	Stars []string `json:"stars"` // Repositories starred through gh-lsmod (owner/repo)
+}
+
+// DefaultStatePath returns the path of the state file in the user state directory
+// ($XDG_STATE_HOME, or ~/.local/state)
+func DefaultStatePath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "gh-lsmod", "stars.json"), nil
+}
+
+// Load reads the state file. A missing file is an empty state.
+func Load(path string) (*State, error) {
+	state := &State{path: path, Projects: make(map[string]*Project)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read star state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse star state %s: %w", path, err)
+	}
+	if state.Projects == nil {
+		state.Projects = make(map[string]*Project)
+	}
+	return state, nil
+}
+
+// Save writes the state file
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to save star state: %w", err)
+	}
+
+	// Write to a temporary file first so that an interrupted save never leaves a partial state
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to save star state: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save star state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save star state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save star state: %w", err)
+	}
+	return nil
+}
+
+// Record records that the repository was starred for the project
+func (s *State) Record(project, repo string) {
+	p := s.Projects[project]
+	if p == nil {
+		p = &Project{}
+		s.Projects[project] = p
+	}
+	if containsRepo(p.Stars, repo) {
+		return
+	}
+	p.Stars = append(p.Stars, repo)
+	sort.Strings(p.Stars)
+}
+
+// Forget removes the record of the repository from the project
+func (s *State) Forget(project, repo string) {
+	p := s.Projects[project]
+	if p == nil {
+		return
+	}
+
+	var stars []string
+	for _, star := range p.Stars {
+		if !strings.EqualFold(star, repo) {
+			stars = append(stars, star)
+		}
+	}
+	p.Stars = stars
+	if len(p.Stars) == 0 {
+		delete(s.Projects, project)
+	}
+}
+
+// Stars returns the repositories starred for the project
+func (s *State) Stars(project string) []string {
+	if p := s.Projects[project]; p != nil {
+		return p.Stars
+	}
+	return nil
+}
+
+// RecordedElsewhere reports whether the repository was starred for any other project
+func (s *State) RecordedElsewhere(project, repo string) bool {
+	for name, p := range s.Projects {
+		if name != project && containsRepo(p.Stars, repo) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unused returns the repositories starred for the project that none of the packages live in anymore
+func (s *State) Unused(project string, packages []*model.Package) []string {
+	used := make(map[string]bool)
+	for _, pkg := range packages {
+		if pkg.IsGitHub {
+			used[strings.ToLower(pkg.GitHubRepoPath())] = true
+		}
+	}
+
+	var unused []string
+	for _, repo := range s.Stars(project) {
+		if !used[strings.ToLower(repo)] {
+			unused = append(unused, repo)
+		}
+	}
+	return unused
+}
+
+// containsRepo reports whether the repository is in the list, ignoring case
+func containsRepo(repos []string, repo string) bool {
+	for _, r := range repos {
+		if strings.EqualFold(r, repo) {
+			return true
+		}
+	}
+	return false
+}
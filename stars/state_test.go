@@ -0,0 +1,84 @@
+package stars
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+func TestDefaultStatePath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/state")
+
+	path, err := DefaultStatePath()
+	if err != nil {
+		t.Fatalf("DefaultStatePath() returned an error: %v", err)
+	}
+	if want := filepath.Join("/tmp/state", "gh-lsmod", "stars.json"); path != want {
+		t.Errorf("DefaultStatePath() = %s, want %s", path, want)
+	}
+}
+
+func TestStateLoadAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gh-lsmod", "stars.json")
+
+	// A missing state file is an empty state
+	state, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() returned an error: %v", err)
+	}
+	if len(state.Projects) != 0 {
+		t.Errorf("Expected an empty state, got %v", state.Projects)
+	}
+
+	state.Record("example.com/app", "charmbracelet/bubbles")
+	state.Record("example.com/app", "charmbracelet/bubbles")
+	state.Record("example.com/app", "charmbracelet/lipgloss")
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() returned an error: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() returned an error: %v", err)
+	}
+	want := []string{"charmbracelet/bubbles", "charmbracelet/lipgloss"}
+	if got := loaded.Stars("example.com/app"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Stars() = %v, want %v", got, want)
+	}
+
+	loaded.Forget("example.com/app", "charmbracelet/bubbles")
+	loaded.Forget("example.com/app", "charmbracelet/lipgloss")
+	if _, ok := loaded.Projects["example.com/app"]; ok {
+		t.Error("Expected projects without stars to be removed")
+	}
+
+	// A corrupt state file is an error rather than silently losing the records
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Expected an error when loading a corrupt state file, got nil")
+	}
+}
+
+func TestStateUnused(t *testing.T) {
+	state := &State{Projects: make(map[string]*Project)}
+	state.Record("example.com/app", "charmbracelet/bubbles")
+	state.Record("example.com/app", "Dropped/Dependency")
+
+	packages := []*model.Package{
+		model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0"),
+		model.NewPackage("golang.org/x/mod", "v0.27.0"),
+	}
+
+	want := []string{"Dropped/Dependency"}
+	if got := state.Unused("example.com/app", packages); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unused() = %v, want %v", got, want)
+	}
+	if got := state.Unused("example.com/other", packages); got != nil {
+		t.Errorf("Unused() for an unknown project = %v, want nil", got)
+	}
+}
@@ -0,0 +1,68 @@
+package stars
+
+import (
+	"errors"
+
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// SyncPlan describes what syncing a project's stars with its dependencies does
+type SyncPlan struct {
+	Unstar []string // Repositories no longer used by the project, to be unstarred
+	Keep   []string // Repositories no longer used by the project but starred for another one, only forgotten
+}
+
+// Empty reports whether there is nothing to sync
+func (p SyncPlan) Empty() bool {
+	return len(p.Unstar) == 0 && len(p.Keep) == 0
+}
+
+// PlanSync compares the stars recorded for the project with the packages it currently depends on
+func (s *State) PlanSync(project string, packages []*model.Package) SyncPlan {
+	var plan SyncPlan
+	for _, repo := range s.Unused(project, packages) {
+		if s.RecordedElsewhere(project, repo) {
+			plan.Keep = append(plan.Keep, repo)
+		} else {
+			plan.Unstar = append(plan.Unstar, repo)
+		}
+	}
+	return plan
+}
+
+// ApplySync unstars the repositories of the plan and removes them from the project's state.
+// It returns the number of repositories unstarred, and PackageErrors for those that failed,
+// which stay recorded so that the next sync tries again.
+func (s *State) ApplySync(client github.GitHubClient, project string, plan SyncPlan) (int, error) {
+	var (
+		count int
+		errs  github.PackageErrors
+	)
+	for _, repo := range plan.Unstar {
+		pkg := model.NewPackage("github.com/"+repo, "")
+		err := client.UnstarRepository(pkg)
+		if errors.Is(err, github.ErrRepositoryNotFound) {
+			// Deleted repositories can't be starred anymore
+			s.Forget(project, repo)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, &github.PackageError{Package: pkg, Err: err})
+			continue
+		}
+		s.Forget(project, repo)
+		count++
+	}
+	for _, repo := range plan.Keep {
+		s.Forget(project, repo)
+	}
+
+	if err := s.Save(); err != nil {
+		return count, err
+	}
+	if len(errs) > 0 {
+		return count, errs
+	}
+	return count, nil
+}
@@ -0,0 +1,56 @@
+package stars
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+func TestSync(t *testing.T) {
+	state, err := Load(filepath.Join(t.TempDir(), "stars.json"))
+	if err != nil {
+		t.Fatalf("Load() returned an error: %v", err)
+	}
+	state.Record("example.com/app", "charmbracelet/bubbles")
+	state.Record("example.com/app", "dropped/dependency")
+	state.Record("example.com/app", "shared/dependency")
+	state.Record("example.com/app", "broken/repo")
+	state.Record("example.com/other", "shared/dependency")
+
+	packages := []*model.Package{model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")}
+	plan := state.PlanSync("example.com/app", packages)
+
+	want := SyncPlan{
+		Unstar: []string{"broken/repo", "dropped/dependency"},
+		Keep:   []string{"shared/dependency"},
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Fatalf("PlanSync() = %+v, want %+v", plan, want)
+	}
+
+	client := &fakeClient{failing: map[string]bool{"broken/repo": true}}
+	count, err := state.ApplySync(client, "example.com/app", plan)
+	if count != 1 {
+		t.Errorf("ApplySync() count = %d, want 1", count)
+	}
+	var pkgErrs github.PackageErrors
+	if !errors.As(err, &pkgErrs) || len(pkgErrs) != 1 {
+		t.Errorf("ApplySync() error = %v, want an error for the broken repository", err)
+	}
+	if !reflect.DeepEqual(client.unstarred, []string{"dropped/dependency"}) {
+		t.Errorf("Expected only the dropped dependency to be unstarred, got %v", client.unstarred)
+	}
+
+	// Failed repositories stay recorded so that the next sync tries again
+	wantStars := []string{"broken/repo", "charmbracelet/bubbles"}
+	if got := state.Stars("example.com/app"); !reflect.DeepEqual(got, wantStars) {
+		t.Errorf("Stars() after sync = %v, want %v", got, wantStars)
+	}
+	if got := state.Stars("example.com/other"); !reflect.DeepEqual(got, []string{"shared/dependency"}) {
+		t.Errorf("Expected other projects to keep their stars, got %v", got)
+	}
+}
@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// BulkAction is an action run on every selected package
+type BulkAction int
+
+const (
+	BulkStar BulkAction = iota
+	BulkUnstar
+	BulkOpen
+	BulkCopyPaths
+	BulkExport
+)
+
+// bulkActions lists the actions in the order they're shown in the menu
+var bulkActions = []BulkAction{BulkStar, BulkUnstar, BulkOpen, BulkCopyPaths, BulkExport}
+
+// String returns the label of the action
+func (a BulkAction) String() string {
+	switch a {
+	case BulkStar:
+		return "Star"
+	case BulkUnstar:
+		return "Unstar"
+	case BulkOpen:
+		return "Open in browser"
+	case BulkCopyPaths:
+		return "Copy module paths"
+	case BulkExport:
+		return "Export to " + ExportFileName
+	}
+	return ""
+}
+
+// bulkActionMsg is sent when an action is chosen in the bulk action menu
+type bulkActionMsg struct {
+	action   BulkAction
+	packages []*model.Package
+}
+
+// newBulkActionMenu creates the menu of actions to run on the packages
+func newBulkActionMenu(packages []*model.Package) *Dialog {
+	labels := make([]string, len(bulkActions))
+	for i, action := range bulkActions {
+		labels[i] = action.String()
+	}
+
+	return NewDialog("Actions", fmt.Sprintf("%d packages selected", len(packages))).
+		WithMenu(labels...).
+		OnResult(func(result DialogResult) tea.Msg {
+			if result.Button < 0 {
+				return nil
+			}
+			return bulkActionMsg{action: bulkActions[result.Button], packages: packages}
+		})
+}
+
+// runBulkAction runs the action on the packages and shows its result in the status bar.
+// Starring and unstarring wait for GitHub, so they return a command whose result is a githubActionMsg.
+func (a *App) runBulkAction(action BulkAction, packages []*model.Package) tea.Cmd {
+	if action != BulkStar && action != BulkUnstar {
+		return a.showResult(bulkActionResult(action, packages))
+	}
+	githubClient := a.githubClient
+	if githubClient == nil {
+		return a.statusBar.Warning("GitHub features are unavailable")
+	}
+
+	if action == BulkStar {
+		return runGitHubAction(statusStar, packages, func(packages []*model.Package) (string, error) {
+			count, err := githubClient.StarRepositories(packages)
+			return bulkResult(fmt.Sprintf("Starred %d packages", count), err), err
+		})
+	}
+	return runGitHubAction(statusStar, packages, func(packages []*model.Package) (string, error) {
+		count, err := githubClient.UnstarRepositories(packages)
+		return bulkResult(fmt.Sprintf("Unstarred %d packages", count), err), err
+	})
+}
+
+// showResult shows the result of an action in the status bar: the message if it succeeded,
+// the message as a warning if it partly failed, and the error if it failed as a whole
+func (a *App) showResult(message string, err error) tea.Cmd {
+	switch {
+	case err == nil:
+		return a.statusBar.Success(message)
+	case message != "":
+		return a.statusBar.Warning(message)
+	}
+	return a.statusBar.Error(err)
+}
+
+// bulkActionResult runs an action that doesn't need GitHub on the packages, and returns the message describing the result
+// with the error, if any. The message is empty when the action failed as a whole.
+func bulkActionResult(action BulkAction, packages []*model.Package) (string, error) {
+	switch action {
+	case BulkOpen:
+		// Open the repository of GitHub packages, and the documentation of the others
+		opened := 0
+		var errs []error
+		for _, pkg := range packages {
+			url := pkg.GitHubURL()
+			if url == "" {
+				url = pkg.PkgGoDevURL()
+			}
+			if err := openBrowser(url); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			opened++
+		}
+		err := errors.Join(errs...)
+		return bulkResult(fmt.Sprintf("Opened %d pages", opened), err), err
+
+	case BulkCopyPaths:
+		paths := make([]string, len(packages))
+		for i, pkg := range packages {
+			paths[i] = pkg.Path
+		}
+		if err := copyToClipboard(strings.Join(paths, "\n")); err != nil {
+			return "", fmt.Errorf("failed to copy: %w", err)
+		}
+		return fmt.Sprintf("Copied %d module paths", len(paths)), nil
+
+	case BulkExport:
+		if err := exportPackages(packages); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Exported %d packages to %s", len(packages), ExportFileName), nil
+	}
+	return "", nil
+}
+
+// bulkResult appends the number of failures to the result message
+func bulkResult(message string, err error) string {
+	if err == nil {
+		return message
+	}
+
+	var pkgErrs github.PackageErrors
+	if errors.As(err, &pkgErrs) {
+		return fmt.Sprintf("%s, %d failed", message, len(pkgErrs))
+	}
+	return message + ": " + err.Error()
+}
+
+// githubStatus is the status of a repository that a GitHub action changes
+type githubStatus int
+
+const (
+	statusStar githubStatus = iota
+	statusWatch
+)
+
+// githubActionMsg is sent when a star or watch action has finished in the background.
+// original and fetched hold copies of the packages, in the same order, from before and after the action.
+type githubActionMsg struct {
+	status   githubStatus
+	packages []*model.Package
+	original []*model.Package
+	fetched  []*model.Package
+	message  string // Result to show, empty if the action failed as a whole
+	err      error
+}
+
+// runGitHubAction returns a command that runs the action on copies of the packages,
+// so that the packages shown by the TUI are only changed in Update.
+// The action returns the message describing its result.
+func runGitHubAction(status githubStatus, packages []*model.Package, action func(packages []*model.Package) (string, error)) tea.Cmd {
+	original := clonePackages(packages)
+	fetched := clonePackages(packages)
+	return func() tea.Msg {
+		message, err := action(fetched)
+		return githubActionMsg{
+			status:   status,
+			packages: packages,
+			original: original,
+			fetched:  fetched,
+			message:  message,
+			err:      err,
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// bulkStarItem is a repository to be starred, with the packages that live in it
+type bulkStarItem struct {
+	repoPath string
+	packages []*model.Package
+	err      error
+}
+
+// bulkStarChosenMsg is sent when the repositories to star have been chosen
+type bulkStarChosenMsg struct {
+	items []*bulkStarItem
+}
+
+// bulkStarResultMsg is sent when a batch of repositories has been starred, or failed to be.
+// star carries the change to apply to the packages.
+type bulkStarResultMsg struct {
+	items []*bulkStarItem
+	star  githubActionMsg
+}
+
+// bulkStarItems returns the unstarred GitHub repositories of the packages.
+// Several modules can live in the same repository, so each repository is listed once.
+func bulkStarItems(packages []*model.Package) []*bulkStarItem {
+	var items []*bulkStarItem
+	byRepo := make(map[string]*bulkStarItem)
+	for _, pkg := range packages {
+		if !pkg.IsGitHub || pkg.IsStarred {
+			continue
+		}
+		repoPath := pkg.GitHubRepoPath()
+		item, ok := byRepo[repoPath]
+		if !ok {
+			item = &bulkStarItem{repoPath: repoPath}
+			byRepo[repoPath] = item
+			items = append(items, item)
+		}
+		item.packages = append(item.packages, pkg)
+	}
+	return items
+}
+
+// newBulkStarDialog creates the dialog listing the repositories to star, all checked
+func newBulkStarDialog(items []*bulkStarItem) *Dialog {
+	labels := make([]string, len(items))
+	checked := make([]bool, len(items))
+	for i, item := range items {
+		labels[i] = item.repoPath
+		if len(item.packages) > 1 {
+			labels[i] += fmt.Sprintf(" (%d modules)", len(item.packages))
+		}
+		checked[i] = true
+	}
+
+	return NewDialog(
+		fmt.Sprintf("Star %d unstarred repositories?", len(items)),
+		"Uncheck the repositories to leave unstarred.",
+	).
+		WithChecklist(labels, checked).
+		WithButtons("Star", "Cancel").
+		OnResult(func(result DialogResult) tea.Msg {
+			if !result.Confirmed() {
+				return nil
+			}
+			var chosen []*bulkStarItem
+			for i, item := range items {
+				if result.Checked[i] {
+					chosen = append(chosen, item)
+				}
+			}
+			if len(chosen) == 0 {
+				return nil
+			}
+			return bulkStarChosenMsg{items: chosen}
+		})
+}
+
+// newBulkStarSummary creates the dialog summarizing a run, offering to retry the repositories that failed
+func newBulkStarSummary(items []*bulkStarItem) *Dialog {
+	var failed []*bulkStarItem
+	var lines []string
+	for _, item := range items {
+		if item.err != nil {
+			failed = append(failed, item)
+			lines = append(lines, "✗ "+item.repoPath+": "+item.err.Error())
+		}
+	}
+	if len(failed) == 0 {
+		lines = append(lines, "✓ All repositories were starred")
+	}
+
+	dialog := NewDialog(
+		fmt.Sprintf("Starred %d of %d repositories", len(items)-len(failed), len(items)),
+		strings.Join(lines, "\n"),
+	)
+	if len(failed) == 0 {
+		return dialog.WithButtons("Close").OnResult(func(DialogResult) tea.Msg { return nil })
+	}
+	return dialog.
+		WithButtons("Retry failed", "Close").
+		OnResult(func(result DialogResult) tea.Msg {
+			if !result.Confirmed() {
+				return nil
+			}
+			return bulkStarChosenMsg{items: failed}
+		})
+}
+
+// BulkStarRun stars several repositories at once in batches, showing the progress below the list
+type BulkStarRun struct {
+	items    []*bulkStarItem
+	queue    []*bulkStarItem // Repositories that haven't been sent to GitHub yet
+	finished int
+	progress progress.Model
+	styles   BulkStarRunStyles
+}
+
+// BulkStarRunStyles contains the styles for the bulk star progress line
+type BulkStarRunStyles struct {
+	Status lipgloss.Style
+}
+
+// DefaultBulkStarRunStyles returns the default styles for the bulk star progress line
+func DefaultBulkStarRunStyles() BulkStarRunStyles {
+	return BulkStarRunStyles{
+		Status: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted).
+			MarginLeft(2),
+	}
+}
+
+// NewBulkStarRun creates a run starring the repositories
+func NewBulkStarRun(items []*bulkStarItem) *BulkStarRun {
+	for _, item := range items {
+		item.err = nil
+	}
+	return &BulkStarRun{
+		items:    items,
+		queue:    items,
+		progress: progress.New(progress.WithDefaultGradient(), progress.WithoutPercentage(), progress.WithWidth(30)),
+		styles:   DefaultBulkStarRunStyles(),
+	}
+}
+
+// Done reports whether every repository has been starred, or failed to be
+func (b *BulkStarRun) Done() bool {
+	return b.finished == len(b.items)
+}
+
+// Next returns the command starring the next batch of repositories, at most github.DefaultConcurrency at a time,
+// so that the progress moves as they finish. It returns nil once every batch has been sent.
+func (b *BulkStarRun) Next(githubClient github.GitHubClient) tea.Cmd {
+	if len(b.queue) == 0 {
+		return nil
+	}
+
+	batch := b.queue[:min(github.DefaultConcurrency, len(b.queue))]
+	b.queue = b.queue[len(batch):]
+
+	// One module per repository is enough, the others in it are updated along with it
+	packages := make([]*model.Package, len(batch))
+	for i, item := range batch {
+		packages[i] = item.packages[0]
+	}
+	cmd := runGitHubAction(statusStar, packages, func(packages []*model.Package) (string, error) {
+		_, err := githubClient.StarRepositories(packages)
+		return "", err
+	})
+	return func() tea.Msg {
+		return bulkStarResultMsg{items: batch, star: cmd().(githubActionMsg)}
+	}
+}
+
+// Update records the result of a batch and returns the command starring the next one
+func (b *BulkStarRun) Update(msg bulkStarResultMsg, githubClient github.GitHubClient) tea.Cmd {
+	// Failures are reported per package, or for the whole batch
+	errs := make(map[*model.Package]error)
+	var pkgErrs github.PackageErrors
+	if errors.As(msg.star.err, &pkgErrs) {
+		for _, pkgErr := range pkgErrs {
+			errs[pkgErr.Package] = pkgErr.Err
+		}
+	}
+	for i, item := range msg.items {
+		item.err = errs[msg.star.fetched[i]]
+		if msg.star.err != nil && pkgErrs == nil {
+			item.err = msg.star.err
+		}
+	}
+	b.finished += len(msg.items)
+	return b.Next(githubClient)
+}
+
+// View renders the progress line
+func (b *BulkStarRun) View() string {
+	percent := float64(b.finished) / float64(max(len(b.items), 1))
+	return b.styles.Status.Render(fmt.Sprintf("Starring repositories %s %d/%d", b.progress.ViewAs(percent), b.finished, len(b.items)))
+}
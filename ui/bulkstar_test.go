@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// runCmds runs the command and the commands it leads to until the stars have all been applied
+func runCmds(app *App, cmd tea.Cmd) {
+	for cmd != nil {
+		_, cmd = app.Update(cmd())
+	}
+}
+
+func TestBulkStarDialog(t *testing.T) {
+	starred := model.NewPackage("github.com/owner/starred", "v1.0.0")
+	starred.IsStarred = true
+	repo := model.NewPackage("github.com/owner/repo", "v1.0.0")
+	repoV2 := model.NewPackage("github.com/owner/repo/v2", "v2.0.0")
+	broken := model.NewPackage("github.com/owner/broken", "v1.0.0")
+	skipped := model.NewPackage("github.com/owner/skipped", "v1.0.0")
+	packages := []*model.Package{starred, repo, repoV2, broken, skipped, model.NewPackage("golang.org/x/mod", "v0.27.0")}
+
+	mockClient := NewMockGitHubClient()
+	mockClient.failingRepos[broken.Path] = errors.New("server error")
+	app := NewApp(packages, mockClient)
+	app.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	if app.modal == nil {
+		t.Fatal("Expected the bulk star dialog to be open")
+	}
+
+	// Every unstarred repository is listed once and checked
+	view := app.View()
+	for _, expected := range []string{"Star 3 unstarred repositories?", "[x] owner/repo (2 modules)", "[x] owner/broken", "[x] owner/skipped"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected the dialog to contain %q, got:\n%s", expected, view)
+		}
+	}
+
+	// Uncheck the last repository and start
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	app.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = app.Update(cmd())
+	if app.bulkStar == nil || app.modal != nil {
+		t.Fatal("Expected the stars to be applied with the dialog closed")
+	}
+	if !strings.Contains(app.View(), "Starring repositories") {
+		t.Errorf("Expected the progress to be shown below the list, got:\n%s", app.View())
+	}
+
+	// Starring all again is ignored while stars are being applied
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	if app.modal != nil {
+		t.Error("Expected no second bulk star while stars are being applied")
+	}
+
+	// The packages only change once the results are handled
+	if repo.IsStarred || repoV2.IsStarred {
+		t.Error("Expected the packages not to change before the results arrive")
+	}
+
+	runCmds(app, cmd)
+	if !repo.IsStarred || !repoV2.IsStarred {
+		t.Error("Expected both modules of the checked repository to be starred")
+	}
+	if skipped.IsStarred {
+		t.Error("Expected the unchecked repository not to be starred")
+	}
+	if mockClient.bulkStarCallCount != 1 || mockClient.starCallCount != 0 {
+		t.Errorf("Expected the stars to be applied in one batch, got %d batches and %d single stars", mockClient.bulkStarCallCount, mockClient.starCallCount)
+	}
+	if app.bulkStar != nil || app.modal == nil {
+		t.Fatal("Expected the summary to be shown once every star has been applied")
+	}
+	view = app.View()
+	for _, expected := range []string{"Starred 1 of 2 repositories", "✗ owner/broken: server error", "Retry failed"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected the summary to contain %q, got:\n%s", expected, view)
+		}
+	}
+
+	// Retry the failure once the server recovers
+	delete(mockClient.failingRepos, broken.Path)
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	runCmds(app, cmd)
+	if !broken.IsStarred {
+		t.Error("Expected the failed repository to be starred on retry")
+	}
+	if view := app.View(); !strings.Contains(view, "Starred 1 of 1 repositories") {
+		t.Errorf("Expected the retry summary, got:\n%s", view)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.modal != nil {
+		t.Error("Expected the summary to close")
+	}
+}
+
+func TestBulkStarSummaryWaitsForOpenDialog(t *testing.T) {
+	broken := model.NewPackage("github.com/owner/broken", "v1.0.0")
+	mockClient := NewMockGitHubClient()
+	mockClient.failingRepos[broken.Path] = errors.New("server error")
+	app := NewApp([]*model.Package{broken}, mockClient)
+
+	_, cmd := app.Update(bulkStarChosenMsg{items: bulkStarItems(app.packages)})
+
+	// A dialog opened while the stars are applied isn't replaced by the summary
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	copyMenu := app.modal
+	runCmds(app, cmd)
+	if copyMenu == nil || app.modal != copyMenu {
+		t.Fatal("Expected the open dialog to stay")
+	}
+
+	// The summary is shown once the dialog is answered
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if view := app.View(); app.modal == nil || !strings.Contains(view, "Starred 0 of 1 repositories") {
+		t.Errorf("Expected the summary after the dialog closed, got:\n%s", view)
+	}
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/cli/browser"
+)
+
+// copyToClipboard copies the text to the clipboard.
+// It's a variable so that tests don't touch the real clipboard.
+var copyToClipboard = func(text string) error {
+	return writeClipboard(text, clipboard.WriteAll, os.Stderr, os.Getenv)
+}
+
+// writeClipboard copies the text to the system clipboard, or asks the terminal to copy it with OSC 52.
+// The terminal is used over SSH, where the system clipboard is the one of the remote machine,
+// and when there's no system clipboard.
+func writeClipboard(text string, system func(string) error, terminal io.Writer, getenv func(string) string) error {
+	remote := getenv("SSH_TTY") != "" || getenv("SSH_CONNECTION") != ""
+	if !remote && system(text) == nil {
+		return nil
+	}
+
+	// tmux and screen only pass the sequence on to the terminal when it's wrapped
+	seq := osc52.New(text)
+	switch {
+	case getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+	_, err := seq.WriteTo(terminal)
+	return err
+}
+
+// openBrowser opens the URL in the browser.
+// It's a variable so that tests don't open a real browser.
+var openBrowser = browser.OpenURL
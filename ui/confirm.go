@@ -0,0 +1,40 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// confirmModel shows a dialog on its own and quits once it's answered
+type confirmModel struct {
+	dialog    *Dialog
+	confirmed bool
+}
+
+// Init initializes the confirmation
+func (m *confirmModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles the answer to the dialog
+func (m *confirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	cmd := m.dialog.Update(msg)
+	if m.dialog.Closed() {
+		m.confirmed = m.dialog.Result().Confirmed()
+		return m, tea.Quit
+	}
+	return m, cmd
+}
+
+// View renders the dialog
+func (m *confirmModel) View() string {
+	return m.dialog.View()
+}
+
+// Confirm shows a confirmation dialog and reports whether it was confirmed
+func Confirm(title, message string) (bool, error) {
+	model := &confirmModel{dialog: NewDialog(title, message)}
+	if _, err := tea.NewProgram(model).Run(); err != nil {
+		return false, err
+	}
+	return model.confirmed, nil
+}
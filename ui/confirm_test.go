@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestConfirmModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       tea.KeyMsg
+		confirmed bool
+	}{
+		{name: "Confirm", key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}, confirmed: true},
+		{name: "Cancel", key: tea.KeyMsg{Type: tea.KeyEsc}, confirmed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &confirmModel{dialog: NewDialog("Unstar?", "owner/repo")}
+			_, cmd := m.Update(tt.key)
+			if cmd == nil {
+				t.Fatal("Expected the dialog to quit once answered")
+			}
+			if m.confirmed != tt.confirmed {
+				t.Errorf("confirmed = %v, want %v", m.confirmed, tt.confirmed)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// CopyFormat is a way of copying packages to the clipboard
+type CopyFormat int
+
+const (
+	CopyPath CopyFormat = iota
+	CopyModuleVersion
+	CopyGoGet
+	CopyGoGetLatest
+	CopyRequire
+	CopyGitHubURL
+	CopyCompareURL
+	CopyPkgGoDevURL
+)
+
+// copyFormats lists the formats in the order they're shown in the menu
+var copyFormats = []CopyFormat{CopyPath, CopyModuleVersion, CopyGoGet, CopyGoGetLatest, CopyRequire, CopyGitHubURL, CopyCompareURL, CopyPkgGoDevURL}
+
+// String returns the label of the format
+func (f CopyFormat) String() string {
+	switch f {
+	case CopyPath:
+		return "Module path"
+	case CopyModuleVersion:
+		return "path@version"
+	case CopyGoGet:
+		return "go get at the pinned version"
+	case CopyGoGetLatest:
+		return "go get at the latest version"
+	case CopyRequire:
+		return "go.mod require line"
+	case CopyGitHubURL:
+		return "GitHub URL"
+	case CopyCompareURL:
+		return "Compare pinned...latest URL"
+	case CopyPkgGoDevURL:
+		return "pkg.go.dev URL"
+	}
+	return ""
+}
+
+// copyMsg is sent when a format is chosen in the copy menu
+type copyMsg struct {
+	format   CopyFormat
+	packages []*model.Package
+}
+
+// copyText returns the text copied for the packages in the format.
+// Lists have one package per line, and commands and require blocks cover every package.
+func copyText(format CopyFormat, packages []*model.Package) (string, error) {
+	var lines []string
+	for _, pkg := range packages {
+		switch format {
+		case CopyPath:
+			lines = append(lines, pkg.Path)
+		case CopyModuleVersion, CopyGoGet:
+			lines = append(lines, pkg.ModuleVersion())
+		case CopyGoGetLatest:
+			// Let go get resolve the latest version if it isn't known yet
+			version := pkg.LatestVersion
+			if version == "" {
+				version = "latest"
+			}
+			lines = append(lines, pkg.Path+"@"+version)
+		case CopyRequire:
+			lines = append(lines, pkg.RequireLine())
+		case CopyGitHubURL:
+			if pkg.IsGitHub {
+				lines = append(lines, pkg.GitHubURL())
+			}
+		case CopyCompareURL:
+			if url := pkg.CompareURL(); url != "" {
+				lines = append(lines, url)
+			}
+		case CopyPkgGoDevURL:
+			lines = append(lines, pkg.PkgGoDevURL())
+		}
+	}
+	if len(lines) == 0 {
+		switch format {
+		case CopyGitHubURL:
+			return "", errors.New("no GitHub repositories to copy")
+		case CopyCompareURL:
+			return "", errors.New("no newer versions on GitHub to compare with")
+		}
+		return "", errors.New("no packages to copy")
+	}
+
+	switch format {
+	case CopyGoGet, CopyGoGetLatest:
+		return "go get " + strings.Join(lines, " "), nil
+	case CopyRequire:
+		if len(lines) == 1 {
+			return "require " + lines[0], nil
+		}
+		return "require (\n\t" + strings.Join(lines, "\n\t") + "\n)", nil
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// newCopyMenu creates the menu of formats to copy the packages in.
+// The GitHub URLs are only offered when they apply to one of the packages.
+func newCopyMenu(packages []*model.Package) *Dialog {
+	var formats []CopyFormat
+	for _, format := range copyFormats {
+		if _, err := copyText(format, packages); err == nil {
+			formats = append(formats, format)
+		}
+	}
+	labels := make([]string, len(formats))
+	for i, format := range formats {
+		labels[i] = format.String()
+	}
+
+	message := packages[0].ModuleVersion()
+	if len(packages) > 1 {
+		message = fmt.Sprintf("%d selected packages", len(packages))
+	}
+	return NewDialog("Copy", message).
+		WithMenu(labels...).
+		OnResult(func(result DialogResult) tea.Msg {
+			if result.Button < 0 {
+				return nil
+			}
+			return copyMsg{format: formats[result.Button], packages: packages}
+		})
+}
+
+// openCopyMenu shows the copy menu for the packages with the key bindings of the copy menu
+func (a *App) openCopyMenu(packages []*model.Package) {
+	a.openDialog(newCopyMenu(packages), a.keys.CopyMenu)
+}
+
+// copyPackages copies the packages in the format, and reports what was copied
+func (a *App) copyPackages(format CopyFormat, packages []*model.Package) tea.Cmd {
+	text, err := copyText(format, packages)
+	if err == nil {
+		err = copyToClipboard(text)
+	}
+	if err != nil {
+		return a.statusBar.Error(fmt.Errorf("failed to copy: %w", err))
+	}
+
+	if strings.Contains(text, "\n") {
+		return a.statusBar.Success(fmt.Sprintf("Copied %d packages (%s)", len(packages), format))
+	}
+	return a.statusBar.Success("Copied " + text)
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+func TestCopyText(t *testing.T) {
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	bubbles.LatestVersion = "v0.21.0"
+	mod := model.NewPackage("golang.org/x/mod", "v0.27.0")
+	mod.Indirect = true
+
+	tests := []struct {
+		name     string
+		format   CopyFormat
+		packages []*model.Package
+		expected string
+		err      bool
+	}{
+		{name: "Module path", format: CopyPath, packages: []*model.Package{bubbles}, expected: "github.com/charmbracelet/bubbles"},
+		{name: "Module paths", format: CopyPath, packages: []*model.Package{bubbles, mod}, expected: "github.com/charmbracelet/bubbles\ngolang.org/x/mod"},
+		{name: "path@version", format: CopyModuleVersion, packages: []*model.Package{mod}, expected: "golang.org/x/mod@v0.27.0"},
+		{name: "go get", format: CopyGoGet, packages: []*model.Package{bubbles, mod}, expected: "go get github.com/charmbracelet/bubbles@v0.20.0 golang.org/x/mod@v0.27.0"},
+		{name: "go get latest", format: CopyGoGetLatest, packages: []*model.Package{bubbles, mod}, expected: "go get github.com/charmbracelet/bubbles@v0.21.0 golang.org/x/mod@latest"},
+		{name: "Require line", format: CopyRequire, packages: []*model.Package{mod}, expected: "require golang.org/x/mod v0.27.0 // indirect"},
+		{name: "Require block", format: CopyRequire, packages: []*model.Package{bubbles, mod}, expected: "require (\n\tgithub.com/charmbracelet/bubbles v0.20.0\n\tgolang.org/x/mod v0.27.0 // indirect\n)"},
+		{name: "GitHub URLs skip other hosts", format: CopyGitHubURL, packages: []*model.Package{bubbles, mod}, expected: "https://github.com/charmbracelet/bubbles/tree/v0.20.0"},
+		{name: "No GitHub URL", format: CopyGitHubURL, packages: []*model.Package{mod}, err: true},
+		{name: "Compare URL", format: CopyCompareURL, packages: []*model.Package{bubbles, mod}, expected: "https://github.com/charmbracelet/bubbles/compare/v0.20.0...v0.21.0"},
+		{name: "No compare URL", format: CopyCompareURL, packages: []*model.Package{mod}, err: true},
+		{name: "pkg.go.dev URL", format: CopyPkgGoDevURL, packages: []*model.Package{mod}, expected: "https://pkg.go.dev/golang.org/x/mod@v0.27.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := copyText(tt.format, tt.packages)
+			if tt.err {
+				if err == nil {
+					t.Errorf("Expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("copyText() returned an error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("copyText() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteClipboard(t *testing.T) {
+	failing := func(string) error { return errors.New("no clipboard utility") }
+
+	tests := []struct {
+		name     string
+		system   func(string) error
+		env      map[string]string
+		copied   bool   // Whether the system clipboard is used
+		sequence string // Prefix of the OSC 52 sequence written to the terminal
+	}{
+		{name: "System clipboard", system: func(string) error { return nil }, copied: true},
+		{name: "No system clipboard", system: failing, sequence: "\x1b]52;c;"},
+		{name: "Over SSH", system: func(string) error { return nil }, env: map[string]string{"SSH_TTY": "/dev/pts/0"}, sequence: "\x1b]52;c;"},
+		{name: "In tmux", system: failing, env: map[string]string{"TMUX": "/tmp/tmux"}, sequence: "\x1bPtmux;\x1b\x1b]52;c;"},
+		{name: "In screen", system: failing, env: map[string]string{"TERM": "screen-256color"}, sequence: "\x1bP\x1b]52;c;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copied := false
+			system := func(text string) error {
+				err := tt.system(text)
+				copied = err == nil
+				return err
+			}
+			var terminal bytes.Buffer
+			getenv := func(name string) string { return tt.env[name] }
+
+			if err := writeClipboard("go get golang.org/x/mod@v0.27.0", system, &terminal, getenv); err != nil {
+				t.Fatalf("writeClipboard() returned an error: %v", err)
+			}
+			if copied != tt.copied {
+				t.Errorf("Copied to the system clipboard = %v, want %v", copied, tt.copied)
+			}
+			if tt.sequence == "" && terminal.Len() > 0 {
+				t.Errorf("Expected nothing written to the terminal, got %q", terminal.String())
+			}
+			if tt.sequence != "" && !strings.HasPrefix(terminal.String(), tt.sequence) {
+				t.Errorf("Expected the terminal to get %q, got %q", tt.sequence, terminal.String())
+			}
+		})
+	}
+}
+
+func TestAppCopy(t *testing.T) {
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	mod := model.NewPackage("golang.org/x/mod", "v0.27.0")
+	app := NewApp([]*model.Package{bubbles, mod}, NewMockGitHubClient())
+
+	var copied string
+	original := copyToClipboard
+	copyToClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+	defer func() { copyToClipboard = original }()
+
+	// Copy the go get command of the package under the cursor
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if app.modal == nil {
+		t.Fatal("Expected the copy menu to open")
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected a command sending the chosen format")
+	}
+	app.Update(cmd())
+	if copied != "go get github.com/charmbracelet/bubbles@v0.20.0" {
+		t.Errorf("Copied %q, want the go get command", copied)
+	}
+
+	// The GitHub URL isn't offered when no package is on GitHub
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if view := app.View(); strings.Contains(view, "GitHub URL") || !strings.Contains(view, "pkg.go.dev URL") {
+		t.Errorf("Expected the menu without the GitHub URL, got:\n%s", view)
+	}
+}
@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/tnagatomi/gh-lsmod/model"
@@ -11,6 +14,8 @@ type PackageDetails struct {
 	pkg    *model.Package
 	width  int
 	height int
+	offset int      // First line shown when the details don't fit
+	fields []string // Names of the fields shown, in order
 	styles DetailsStyles
 }
 
@@ -19,32 +24,39 @@ type DetailsStyles struct {
 	Title       lipgloss.Style
 	Label       lipgloss.Style
 	Value       lipgloss.Style
+	Warning     lipgloss.Style
 	Border      lipgloss.Style
 	EmptyBorder lipgloss.Style
+	Scroll      lipgloss.Style
 }
 
 // DefaultDetailsStyles returns the default styles for the details view
 func DefaultDetailsStyles() DetailsStyles {
 	return DetailsStyles{
 		Title: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("99")).
+			Foreground(activeTheme.Accent).
 			Bold(true).
 			MarginLeft(2),
 		Label: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
+			Foreground(activeTheme.Muted).
 			Bold(true),
 		Value: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252")),
+			Foreground(activeTheme.Text),
+		Warning: lipgloss.NewStyle().
+			Foreground(activeTheme.Warning).
+			Bold(true),
 		Border: lipgloss.NewStyle().
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("240")).
+			BorderForeground(activeTheme.Muted).
 			Padding(1, 2),
 		EmptyBorder: lipgloss.NewStyle().
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("240")).
+			BorderForeground(activeTheme.Muted).
 			Padding(1, 2).
-			Foreground(lipgloss.Color("240")).
+			Foreground(activeTheme.Muted).
 			Italic(true),
+		Scroll: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted),
 	}
 }
 
@@ -53,13 +65,17 @@ func NewPackageDetails() *PackageDetails {
 	return &PackageDetails{
 		pkg:    nil,
 		width:  80,
-		height: 10,
+		height: DetailViewHeight,
+		fields: DefaultDetailsFields,
 		styles: DefaultDetailsStyles(),
 	}
 }
 
-// SetPackage sets the package to display
+// SetPackage sets the package to display, scrolled to the top
 func (d *PackageDetails) SetPackage(pkg *model.Package) {
+	if pkg != d.pkg {
+		d.offset = 0
+	}
 	d.pkg = pkg
 }
 
@@ -79,32 +95,186 @@ func (d *PackageDetails) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return d, nil
 }
 
-// View renders the details view
+// DefaultDetailsFields are the fields shown in the details view, in order
+var DefaultDetailsFields = []string{"name", "version", "version_info", "latest", "warnings", "size", "github", "compare", "description", "stats", "activity", "status", "pkg_go_dev"}
+
+// detailsFields renders each field of the details view by its name in the config file.
+// A field renders to an empty string when it doesn't apply to the package.
+var detailsFields = map[string]func(d *PackageDetails, pkg *model.Package) string{
+	"name": func(d *PackageDetails, pkg *model.Package) string {
+		return d.field("Name", pkg.Path)
+	},
+	"version": func(d *PackageDetails, pkg *model.Package) string {
+		return d.field("Version", pkg.Version)
+	},
+	"version_info": func(d *PackageDetails, pkg *model.Package) string {
+		// What a pseudo-version points at, and why +incompatible and pre-release versions need a review
+		version := pkg.ParsedVersion()
+		var lines []string
+		if version.Pseudo {
+			base := "no tagged version before it"
+			if version.Base != "" {
+				base = "after " + version.Base
+			}
+			lines = append(lines, d.styles.Warning.Render(fmt.Sprintf("⚑ Pseudo-version: commit %s from %s, %s",
+				version.Revision, version.Time.Format("2006-01-02 15:04 MST"), base)))
+		}
+		if version.Incompatible {
+			lines = append(lines, d.styles.Warning.Render(fmt.Sprintf("⚑ +incompatible: v%d without a go.mod opting into semantic import versioning", version.Major)))
+		}
+		if version.IsPrerelease() {
+			lines = append(lines, d.field("Pre-release", version.Prerelease))
+		}
+		return strings.Join(lines, "\n")
+	},
+	"latest": func(d *PackageDetails, pkg *model.Package) string {
+		// Only shown if it's newer than the pinned version
+		if !pkg.Outdated() {
+			return ""
+		}
+		return d.field("Latest", pkg.LatestVersion)
+	},
+	"warnings": func(d *PackageDetails, pkg *model.Package) string {
+		// Deprecation and retraction warnings
+		var lines []string
+		for _, warning := range pkg.Warnings() {
+			lines = append(lines, d.styles.Warning.Render("⚠ "+warning))
+		}
+		return strings.Join(lines, "\n")
+	},
+	"size": func(d *PackageDetails, pkg *model.Package) string {
+		return d.field("Size", pkg.FormattedSize())
+	},
+	"github": func(d *PackageDetails, pkg *model.Package) string {
+		if !pkg.IsGitHub {
+			return ""
+		}
+		return d.field("GitHub", pkg.GitHubURL())
+	},
+	"compare": func(d *PackageDetails, pkg *model.Package) string {
+		// Only shown if there's a newer version on GitHub
+		if url := pkg.CompareURL(); url != "" {
+			return d.field("Compare", url)
+		}
+		return ""
+	},
+	"description": func(d *PackageDetails, pkg *model.Package) string {
+		if pkg.Repo == nil || pkg.Repo.Description == "" {
+			return ""
+		}
+		return d.field("Description", pkg.Repo.Description)
+	},
+	"stats": func(d *PackageDetails, pkg *model.Package) string {
+		if pkg.Repo == nil {
+			return ""
+		}
+		return d.field("Stars", model.FormattedCount(pkg.Repo.Stars)) + "  " +
+			d.field("Forks", model.FormattedCount(pkg.Repo.Forks)) + "  " +
+			d.field("Open issues", model.FormattedCount(pkg.Repo.OpenIssues))
+	},
+	"activity": func(d *PackageDetails, pkg *model.Package) string {
+		repo := pkg.Repo
+		if repo == nil {
+			return ""
+		}
+		language := repo.Language
+		if language == "" {
+			language = "unknown"
+		}
+		lastPush := "unknown"
+		if !repo.PushedAt.IsZero() {
+			lastPush = repo.PushedAt.Format("2006-01-02")
+		}
+		activity := d.field("Language", language) + "  " + d.field("Last push", lastPush)
+		if repo.License != "" {
+			activity += "  " + d.field("License", repo.License)
+		}
+		return activity
+	},
+	"status": func(d *PackageDetails, pkg *model.Package) string {
+		if pkg.Repo == nil || pkg.Repo.Status() == "" {
+			return ""
+		}
+		return d.styles.Warning.Render("⚠ Repository is " + pkg.Repo.Status())
+	},
+	"pkg_go_dev": func(d *PackageDetails, pkg *model.Package) string {
+		return d.field("pkg.go.dev", pkg.PkgGoDevURL())
+	},
+}
+
+// SetFields sets the fields shown in the details view, in order
+func (d *PackageDetails) SetFields(fields []string) {
+	d.fields = fields
+}
+
+// View renders the details view, filling its size
 func (d *PackageDetails) View() string {
 	if d.pkg == nil {
 		return d.styles.EmptyBorder.Width(d.width - 4).Render("No package selected")
 	}
 
-	// Create the details content
-	var content string
+	lines := d.lines()
+	page := d.pageSize(len(lines))
+	offset := min(d.offset, max(len(lines)-page, 0))
+	visible := lines[offset:min(offset+page, len(lines))]
 
-	// Add the package name
-	content += d.styles.Label.Render("Name: ") + d.styles.Value.Render(d.pkg.Path) + "\n"
+	// Show the position when the details don't fit, or fill the height when they do
+	if len(lines) > page {
+		visible = append(visible, d.styles.Scroll.Render(fmt.Sprintf("↕ %d-%d of %d lines", offset+1, offset+len(visible), len(lines))))
+	} else {
+		visible = append(visible, make([]string, page-len(visible))...)
+	}
 
-	// Add the package version
-	content += d.styles.Label.Render("Version: ") + d.styles.Value.Render(d.pkg.Version) + "\n"
-	
-	// Add the package size
-	content += d.styles.Label.Render("Size: ") + d.styles.Value.Render(d.pkg.FormattedSize()) + "\n"
+	// Apply border to the content
+	return d.styles.Border.Width(d.width - 4).Render(strings.Join(visible, "\n"))
+}
 
-	// Add the GitHub URL if it's a GitHub repository
-	if d.pkg.IsGitHub {
-		content += d.styles.Label.Render("GitHub: ") + d.styles.Value.Render(d.pkg.GitHubURL()) + "\n"
+// lines renders the fields that apply to the package, wrapped to the width of the details view
+func (d *PackageDetails) lines() []string {
+	var fields []string
+	for _, field := range d.fields {
+		if line := detailsFields[field](d, d.pkg); line != "" {
+			fields = append(fields, line)
+		}
 	}
+	// The border and padding take 4 columns on each side
+	content := lipgloss.NewStyle().Width(max(d.width-8, 1)).Render(strings.Join(fields, "\n"))
+	return strings.Split(content, "\n")
+}
 
-	// Add the pkg.go.dev URL
-	content += d.styles.Label.Render("pkg.go.dev: ") + d.styles.Value.Render(d.pkg.PkgGoDevURL())
+// pageSize returns the number of lines shown at once, leaving one for the position if they don't all fit
+func (d *PackageDetails) pageSize(total int) int {
+	// The border and padding take 2 lines at the top and the bottom
+	height := max(d.height-4, 1)
+	if total > height {
+		return max(height-1, 1)
+	}
+	return height
+}
 
-	// Apply border to the content
-	return d.styles.Border.Width(d.width - 4).Render(content)
+// ScrollDown scrolls the details down by the number of lines
+func (d *PackageDetails) ScrollDown(lines int) {
+	if d.pkg == nil {
+		return
+	}
+	total := len(d.lines())
+	d.offset = max(min(d.offset+lines, total-d.pageSize(total)), 0)
+}
+
+// ScrollUp scrolls the details up by the number of lines
+func (d *PackageDetails) ScrollUp(lines int) {
+	d.offset = max(d.offset-lines, 0)
+}
+
+// PageSize returns the number of lines shown at once
+func (d *PackageDetails) PageSize() int {
+	if d.pkg == nil {
+		return 1
+	}
+	return d.pageSize(len(d.lines()))
+}
+
+// field renders a labeled value
+func (d *PackageDetails) field(label, value string) string {
+	return d.styles.Label.Render(label+": ") + d.styles.Value.Render(value)
 }
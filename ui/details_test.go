@@ -3,6 +3,7 @@ package ui
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/tnagatomi/gh-lsmod/model"
 )
@@ -73,7 +74,56 @@ func TestPackageDetailsView(t *testing.T) {
 				"pkg.go.dev: https://pkg.go.dev/golang.org/x/mod",
 			},
 		},
-}
+		{
+			name: "Deprecated package with retracted version",
+			pkg: func() *model.Package {
+				pkg := model.NewPackage("github.com/golang/protobuf", "v1.5.0")
+				pkg.LatestVersion = "v1.5.4"
+				pkg.Deprecated = "Use the google.golang.org/protobuf module instead."
+				pkg.Retracted = true
+				pkg.RetractRationale = "Broken build."
+				return pkg
+			}(),
+			contains: []string{
+				"Version: v1.5.0",
+				"Latest: v1.5.4",
+				"Compare: https://github.com/golang/protobuf/compare/v1.5.0...v1.5.4",
+				"⚠ Deprecated: Use the google.golang.org/protobuf module instead.",
+				"⚠ Retracted: v1.5.0 (Broken build.)",
+			},
+		},
+		{
+			name: "GitHub package with repository metadata",
+			pkg: func() *model.Package {
+				pkg := model.NewPackage("github.com/pkg/errors", "v0.9.1")
+				pkg.Repo = &model.RepoMetadata{
+					Stars:       8234,
+					Forks:       700,
+					OpenIssues:  42,
+					PushedAt:    time.Date(2021, 11, 2, 0, 0, 0, 0, time.UTC),
+					Archived:    true,
+					Language:    "Go",
+					Description: "Simple error handling primitives",
+				}
+				return pkg
+			}(),
+			contains: []string{
+				"Description: Simple error handling primitives",
+				"Stars: 8.2k  Forks: 700  Open issues: 42",
+				"Language: Go  Last push: 2021-11-02",
+				"⚠ Repository is archived",
+			},
+		},
+		{
+			name: "Pseudo-version and incompatible module",
+			pkg:  model.NewPackage("github.com/owner/repo", "v3.0.1-0.20240102030405-0123456789ab+incompatible"),
+			contains: []string{
+				"⚑ Pseudo-version: commit 0123456789ab from 2024-01-02 03:04 UTC, after",
+				"v3.0.0+incompatible",
+				"⚑ +incompatible: v3 without a go.mod",
+			},
+		},
+	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1,16 +1,77 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Dialog sizes
+const (
+	DialogWidth       = 60
+	DialogMinWidth    = 30
+	DialogMaxMessages = 10 // Lines of the message shown at once, unless the screen is smaller
 )
 
-// Dialog represents a confirmation dialog
+// DialogResult is how a dialog was answered
+type DialogResult struct {
+	Button  int      // Index of the pressed button, or -1 if the dialog was cancelled
+	Label   string   // Label of the pressed button
+	Inputs  []string // Values of the text inputs, in order
+	Checked []bool   // Whether each checklist item is checked, in order
+}
+
+// Confirmed reports whether the first button was pressed, which confirms by convention
+func (r DialogResult) Confirmed() bool {
+	return r.Button == 0
+}
+
+// DialogResultMsg is sent when a dialog without a result function is answered
+type DialogResultMsg struct {
+	ID string
+	DialogResult
+}
+
+// dialogInput is a labeled text input of a dialog
+type dialogInput struct {
+	label string
+	input textinput.Model
+}
+
+// dialogItem is an entry of the checklist of a dialog
+type dialogItem struct {
+	label   string
+	checked bool
+}
+
+// Dialog represents a modal dialog with a message, text inputs, a checklist and buttons.
+// Focus moves through the inputs, the checklist and the buttons in that order.
 type Dialog struct {
+	id      string
 	title   string
 	message string
+	buttons []string
+	menu    bool // Whether the buttons are stacked as a menu
+	inputs  []dialogInput
+	items   []dialogItem
+	item    int // Checklist item under the cursor
+	first   int // First checklist item shown when they don't fit
+	shown   int // Checklist items shown at once
+	focus   int // Focused input, then the checklist, then the focused button
+	offset  int // First line of the message shown when it doesn't fit
+	lines   int // Lines of the message shown at once
 	width   int
-	height  int
+	closed  bool
+	answer  DialogResult
+	result  func(DialogResult) tea.Msg
+	help    help.Model
 	styles  DialogStyles
 	keyMap  DialogKeyMap
 }
@@ -20,6 +81,11 @@ type DialogStyles struct {
 	Border       lipgloss.Style
 	Title        lipgloss.Style
 	Message      lipgloss.Style
+	Label        lipgloss.Style
+	LabelActive  lipgloss.Style
+	Item         lipgloss.Style
+	ItemActive   lipgloss.Style
+	Scroll       lipgloss.Style
 	Button       lipgloss.Style
 	ButtonActive lipgloss.Style
 }
@@ -29,80 +95,514 @@ func DefaultDialogStyles() DialogStyles {
 	return DialogStyles{
 		Border: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("240")),
+			BorderForeground(activeTheme.Muted).
+			Padding(0, 1),
 		Title: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("99")).
+			Foreground(activeTheme.Accent).
 			Bold(true),
 		Message: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252")),
+			Foreground(activeTheme.Text),
+		Label: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted).
+			Bold(true),
+		LabelActive: lipgloss.NewStyle().
+			Foreground(activeTheme.Accent).
+			Bold(true),
+		Item: lipgloss.NewStyle().
+			Foreground(activeTheme.Text),
+		ItemActive: lipgloss.NewStyle().
+			Foreground(activeTheme.Highlight).
+			Bold(true),
+		Scroll: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted),
 		Button: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252")).
-			Background(lipgloss.Color("240")).
+			Foreground(activeTheme.Text).
+			Background(activeTheme.Muted).
 			Padding(0, 3).
 			MarginRight(1),
 		ButtonActive: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("0")).
-			Background(lipgloss.Color("99")).
+			Foreground(activeTheme.Inverse).
+			Background(activeTheme.Accent).
 			Padding(0, 3).
 			MarginRight(1),
 	}
 }
 
-// NewDialog creates a new dialog
+// DialogKeyMap defines the key bindings for the dialog
+type DialogKeyMap struct {
+	Confirm   key.Binding
+	Cancel    key.Binding
+	Next      key.Binding
+	Previous  key.Binding
+	Left      key.Binding
+	Right     key.Binding
+	Press     key.Binding
+	Toggle    key.Binding
+	ToggleAll key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	PageUp    key.Binding
+	PageDown  key.Binding
+}
+
+// DefaultDialogKeyMap returns the default key bindings for the dialog
+func DefaultDialogKeyMap() DialogKeyMap {
+	return DialogKeyMap{
+		Confirm: key.NewBinding(
+			key.WithKeys("y", "Y"),
+			key.WithHelp("y", "confirm"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("n", "N", "esc", "q", "ctrl+c"),
+			key.WithHelp("n/esc/q", "cancel"),
+		),
+		Next: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next"),
+		),
+		Previous: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "previous"),
+		),
+		Left: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "previous button"),
+		),
+		Right: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "next button"),
+		),
+		Press: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "select"),
+		),
+		Toggle: key.NewBinding(
+			key.WithKeys(" ", "x"),
+			key.WithHelp("space", "check"),
+		),
+		ToggleAll: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "check all"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup"),
+			key.WithHelp("pgup", "scroll up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown"),
+			key.WithHelp("pgdn", "scroll down"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view.
+func (k DialogKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Next, k.Press, k.Cancel}
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k DialogKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Next, k.Previous, k.Left, k.Right},
+		{k.Press, k.Confirm, k.Cancel, k.Toggle, k.ToggleAll},
+		{k.Up, k.Down, k.PageUp, k.PageDown},
+	}
+}
+
+// NewDialog creates a new dialog asking to confirm with Yes or No
 func NewDialog(title, message string) *Dialog {
 	return &Dialog{
 		title:   title,
 		message: message,
-		width:   60,
-		height:  7,
+		buttons: []string{"Yes", "No"},
+		lines:   DialogMaxMessages,
+		width:   DialogWidth,
+		help:    help.New(),
 		styles:  DefaultDialogStyles(),
 		keyMap:  DefaultDialogKeyMap(),
 	}
 }
 
+// WithID sets the ID sent back in the DialogResultMsg
+func (d *Dialog) WithID(id string) *Dialog {
+	d.id = id
+	return d
+}
+
+// WithButtons replaces the buttons. The first one is focused and pressed by the confirm key.
+func (d *Dialog) WithButtons(labels ...string) *Dialog {
+	d.buttons = labels
+	d.resetFocus()
+	return d
+}
+
+// WithMenu replaces the buttons with a menu of choices stacked vertically
+func (d *Dialog) WithMenu(labels ...string) *Dialog {
+	d.menu = true
+	return d.WithButtons(labels...)
+}
+
+// WithInput adds a text input field
+func (d *Dialog) WithInput(label, placeholder, value string) *Dialog {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = placeholder
+	input.SetValue(value)
+	input.CursorEnd()
+	input.Cursor.SetMode(cursor.CursorStatic)
+	d.inputs = append(d.inputs, dialogInput{label: label, input: input})
+	d.resetFocus()
+	return d
+}
+
+// WithChecklist adds a checklist of items, checked as given
+func (d *Dialog) WithChecklist(items []string, checked []bool) *Dialog {
+	d.items = nil
+	for i, item := range items {
+		d.items = append(d.items, dialogItem{label: item, checked: i < len(checked) && checked[i]})
+	}
+	d.shown = len(d.items)
+	d.resetFocus()
+	return d
+}
+
+// OnResult sets the function turning the answer into the message sent to the application.
+// No message is sent when it returns nil.
+func (d *Dialog) OnResult(result func(DialogResult) tea.Msg) *Dialog {
+	d.result = result
+	return d
+}
+
+// SetKeyMap sets the key bindings for the dialog
+func (d *Dialog) SetKeyMap(keyMap DialogKeyMap) {
+	d.keyMap = keyMap
+}
+
+// SetSize fits the dialog into a screen of the size
+func (d *Dialog) SetSize(width, height int) {
+	d.width = max(min(DialogWidth, width-4), DialogMinWidth)
+	d.help.Width = d.contentWidth()
+
+	// The border, title, scroll position, buttons, help and the blank lines between them take 9 lines
+	fixed := 9 + len(d.inputs)*2
+	if d.menu {
+		fixed += len(d.buttons) - 1
+	}
+	if len(d.inputs)+len(d.items) > 0 {
+		fixed++
+	}
+
+	// The checklist scrolls when it doesn't fit, leaving a line for the message and one for its scroll position
+	d.shown = len(d.items)
+	if available := height - fixed - 1; d.shown > available {
+		d.shown = max(available-1, 1)
+	}
+	fixed += d.shown
+	if d.shown < len(d.items) {
+		fixed++
+	}
+	d.lines = max(min(DialogMaxMessages, height-fixed), 1)
+	d.scroll(0)
+	d.moveItem(0)
+}
+
+// Closed reports whether the dialog has been answered
+func (d *Dialog) Closed() bool {
+	return d.closed
+}
+
+// Result returns the answer once the dialog is closed
+func (d *Dialog) Result() DialogResult {
+	return d.answer
+}
+
+// checklistFocus returns the focus index of the checklist, or -1 if there's none
+func (d *Dialog) checklistFocus() int {
+	if len(d.items) == 0 {
+		return -1
+	}
+	return len(d.inputs)
+}
+
+// firstButton returns the focus index of the first button
+func (d *Dialog) firstButton() int {
+	if len(d.items) > 0 {
+		return len(d.inputs) + 1
+	}
+	return len(d.inputs)
+}
+
+// focusedInput returns the focused text input, or nil if the focus is elsewhere
+func (d *Dialog) focusedInput() *textinput.Model {
+	if d.focus < len(d.inputs) {
+		return &d.inputs[d.focus].input
+	}
+	return nil
+}
+
+// focusedButton returns the index of the focused button, or -1 if the focus is elsewhere
+func (d *Dialog) focusedButton() int {
+	if d.focus >= d.firstButton() {
+		return d.focus - d.firstButton()
+	}
+	return -1
+}
+
+// resetFocus focuses the first input, the checklist or the first button, whichever comes first
+func (d *Dialog) resetFocus() {
+	d.setFocus(0)
+}
+
+// setFocus moves the focus, wrapping around
+func (d *Dialog) setFocus(focus int) {
+	count := d.firstButton() + len(d.buttons)
+	if count == 0 {
+		return
+	}
+	d.focus = (focus%count + count) % count
+	for i := range d.inputs {
+		if i == d.focus {
+			d.inputs[i].input.Focus()
+		} else {
+			d.inputs[i].input.Blur()
+		}
+	}
+}
+
+// scroll scrolls the message by the number of lines, keeping it within bounds
+func (d *Dialog) scroll(lines int) {
+	total := len(d.messageLines())
+	d.offset = max(min(d.offset+lines, total-d.lines), 0)
+}
+
+// moveItem moves the checklist cursor by the number of items, scrolling to keep it shown
+func (d *Dialog) moveItem(items int) {
+	if len(d.items) == 0 {
+		return
+	}
+	d.item = max(min(d.item+items, len(d.items)-1), 0)
+	if d.item < d.first {
+		d.first = d.item
+	}
+	if d.item >= d.first+d.shown {
+		d.first = d.item - d.shown + 1
+	}
+	d.first = max(min(d.first, len(d.items)-d.shown), 0)
+}
+
+// close closes the dialog with the pressed button, or -1 if it was cancelled,
+// and returns a command sending the result
+func (d *Dialog) close(button int) tea.Cmd {
+	d.closed = true
+	d.answer = DialogResult{Button: button}
+	if button >= 0 && button < len(d.buttons) {
+		d.answer.Label = d.buttons[button]
+	}
+	for _, input := range d.inputs {
+		d.answer.Inputs = append(d.answer.Inputs, input.input.Value())
+	}
+	for _, item := range d.items {
+		d.answer.Checked = append(d.answer.Checked, item.checked)
+	}
+
+	result := d.answer
+	if d.result == nil {
+		id := d.id
+		return func() tea.Msg {
+			return DialogResultMsg{ID: id, DialogResult: result}
+		}
+	}
+	msg := d.result(result)
+	if msg == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// Update handles user input in the dialog
+func (d *Dialog) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || d.closed {
+		return nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, d.keyMap.Next):
+		d.setFocus(d.focus + 1)
+		return nil
+	case key.Matches(keyMsg, d.keyMap.Previous):
+		d.setFocus(d.focus - 1)
+		return nil
+	case key.Matches(keyMsg, d.keyMap.PageDown):
+		d.scroll(d.lines)
+		return nil
+	case key.Matches(keyMsg, d.keyMap.PageUp):
+		d.scroll(-d.lines)
+		return nil
+	}
+
+	// Text inputs take every other key, except for submitting and cancelling with non-printable keys
+	if input := d.focusedInput(); input != nil {
+		switch {
+		case key.Matches(keyMsg, d.keyMap.Press):
+			return d.close(0)
+		case keyMsg.Type != tea.KeyRunes && keyMsg.Type != tea.KeySpace && key.Matches(keyMsg, d.keyMap.Cancel):
+			return d.close(-1)
+		}
+		var cmd tea.Cmd
+		*input, cmd = input.Update(keyMsg)
+		return cmd
+	}
+
+	onChecklist := d.focus == d.checklistFocus()
+	switch {
+	case key.Matches(keyMsg, d.keyMap.Cancel):
+		return d.close(-1)
+	case key.Matches(keyMsg, d.keyMap.Confirm):
+		return d.close(0)
+	case key.Matches(keyMsg, d.keyMap.Press):
+		return d.close(max(d.focusedButton(), 0))
+	case d.menu && d.focusedButton() >= 0 && key.Matches(keyMsg, d.keyMap.Up):
+		d.setFocus(d.firstButton() + max(d.focusedButton()-1, 0))
+	case d.menu && d.focusedButton() >= 0 && key.Matches(keyMsg, d.keyMap.Down):
+		d.setFocus(d.firstButton() + min(d.focusedButton()+1, len(d.buttons)-1))
+	case onChecklist && key.Matches(keyMsg, d.keyMap.Toggle):
+		d.items[d.item].checked = !d.items[d.item].checked
+	case onChecklist && key.Matches(keyMsg, d.keyMap.ToggleAll):
+		// Uncheck everything if all are checked, check everything otherwise
+		all := true
+		for _, item := range d.items {
+			all = all && item.checked
+		}
+		for i := range d.items {
+			d.items[i].checked = !all
+		}
+	case onChecklist && key.Matches(keyMsg, d.keyMap.Up):
+		d.moveItem(-1)
+	case onChecklist && key.Matches(keyMsg, d.keyMap.Down):
+		d.moveItem(1)
+	case key.Matches(keyMsg, d.keyMap.Up):
+		d.scroll(-1)
+	case key.Matches(keyMsg, d.keyMap.Down):
+		d.scroll(1)
+	case d.focusedButton() >= 0 && key.Matches(keyMsg, d.keyMap.Left):
+		d.setFocus(d.firstButton() + max(d.focusedButton()-1, 0))
+	case d.focusedButton() >= 0 && key.Matches(keyMsg, d.keyMap.Right):
+		d.setFocus(d.firstButton() + min(d.focusedButton()+1, len(d.buttons)-1))
+	}
+	return nil
+}
+
+// contentWidth returns the width inside the border and padding
+func (d *Dialog) contentWidth() int {
+	return max(d.width-4, 1)
+}
+
+// messageLines returns the message wrapped to the width of the dialog
+func (d *Dialog) messageLines() []string {
+	if d.message == "" {
+		return nil
+	}
+	return strings.Split(d.styles.Message.Width(d.contentWidth()).Render(d.message), "\n")
+}
+
 // View renders the dialog
 func (d *Dialog) View() string {
-	// Create the dialog content
-	titleView := d.styles.Title.Render(d.title)
-	messageView := d.styles.Message.Render(d.message)
-	
-	// Create the buttons
-	yesButton := d.styles.ButtonActive.Render("Yes")
-	noButton := d.styles.Button.Render("No")
-	buttonsView := lipgloss.JoinHorizontal(lipgloss.Center, yesButton, noButton)
-	
-	// Join all content vertically
-	content := lipgloss.JoinVertical(
-		lipgloss.Center,
-		titleView,
-		lipgloss.NewStyle().Height(1).Render(""), // Empty line
-		messageView,
-		lipgloss.NewStyle().Height(1).Render(""), // Empty line
-		buttonsView,
-	)
-	
-	// Apply border to the content
-	dialogView := d.styles.Border.Width(d.width).Render(content)
-	
-	return dialogView
-}
-
-// KeyMap defines the key bindings for the dialog
-type DialogKeyMap struct {
-	Confirm key.Binding
-	Cancel  key.Binding
+	width := d.contentWidth()
+	sections := []string{d.styles.Title.Width(width).Render(d.title)}
+
+	// The message scrolls when it doesn't fit
+	if lines := d.messageLines(); len(lines) > 0 {
+		end := min(d.offset+d.lines, len(lines))
+		message := strings.Join(lines[d.offset:end], "\n")
+		if len(lines) > d.lines {
+			message += "\n" + d.styles.Scroll.Render(fmt.Sprintf("↕ %d-%d of %d lines", d.offset+1, end, len(lines)))
+		}
+		sections = append(sections, message)
+	}
+
+	var fields []string
+	for i, input := range d.inputs {
+		label := d.styles.Label
+		if i == d.focus {
+			label = d.styles.LabelActive
+		}
+		input.input.Width = max(width-4, 1)
+		fields = append(fields, label.Render(input.label), input.input.View())
+	}
+	end := min(d.first+d.shown, len(d.items))
+	for i, item := range d.items[d.first:end] {
+		check := "[ ] "
+		if item.checked {
+			check = "[x] "
+		}
+		if d.focus == d.checklistFocus() && d.first+i == d.item {
+			fields = append(fields, d.styles.ItemActive.Render("> "+check+item.label))
+		} else {
+			fields = append(fields, d.styles.Item.Render("  "+check+item.label))
+		}
+	}
+	if end-d.first < len(d.items) {
+		fields = append(fields, d.styles.Scroll.Render(fmt.Sprintf("↕ %d-%d of %d items", d.first+1, end, len(d.items))))
+	}
+	if len(fields) > 0 {
+		sections = append(sections, strings.Join(fields, "\n"))
+	}
+
+	var buttons []string
+	for i, label := range d.buttons {
+		switch {
+		case d.menu && i == d.focusedButton():
+			buttons = append(buttons, d.styles.ItemActive.Render("> "+label))
+		case d.menu:
+			buttons = append(buttons, d.styles.Item.Render("  "+label))
+		case i == d.focusedButton():
+			buttons = append(buttons, d.styles.ButtonActive.Render(label))
+		default:
+			buttons = append(buttons, d.styles.Button.Render(label))
+		}
+	}
+	if d.menu {
+		sections = append(sections, strings.Join(buttons, "\n"))
+	} else {
+		sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Center, buttons...))
+	}
+	sections = append(sections, d.help.View(d.keyMap))
+
+	return d.styles.Border.Width(d.width - 2).Render(strings.Join(sections, "\n\n"))
 }
 
-// DefaultDialogKeyMap returns the default key bindings for the dialog
-func DefaultDialogKeyMap() DialogKeyMap {
-	return DialogKeyMap{
-		Confirm: key.NewBinding(
-			key.WithKeys("y", "Y", "enter"),
-			key.WithHelp("y/enter", "confirm"),
-		),
-		Cancel: key.NewBinding(
-			key.WithKeys("n", "N", "esc", "q", "ctrl+c"),
-			key.WithHelp("n/esc/q", "cancel"),
-		),
+// placeOverlay renders the foreground centered over the background, which is padded to the height
+func placeOverlay(background, foreground string, width, height int) string {
+	lines := strings.Split(background, "\n")
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	overlay := strings.Split(foreground, "\n")
+
+	x := max((width-lipgloss.Width(foreground))/2, 0)
+	y := max((len(lines)-len(overlay))/2, 0)
+	for i, line := range overlay {
+		if y+i >= len(lines) {
+			lines = append(lines, "")
+		}
+		// Keep what's on either side of the overlay, resetting its style so it doesn't leak in
+		left := ansi.Truncate(lines[y+i], x, "")
+		if w := ansi.StringWidth(left); w < x {
+			left += strings.Repeat(" ", x-w)
+		}
+		right := ansi.TruncateLeft(lines[y+i], x+ansi.StringWidth(line), "")
+		lines[y+i] = left + ansi.ResetStyle + line + ansi.ResetStyle + right
 	}
+	return strings.Join(lines, "\n")
 }
@@ -1,8 +1,13 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 func TestDialogView(t *testing.T) {
@@ -30,3 +35,150 @@ func TestDialogView(t *testing.T) {
 		t.Errorf("Expected view to contain 'No' button, but it didn't.\nGot: %s", result)
 	}
 }
+
+func TestDialogButtons(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   []tea.KeyMsg
+		button int
+		label  string
+	}{
+		{name: "Enter presses the first button", keys: []tea.KeyMsg{{Type: tea.KeyEnter}}, button: 0, label: "Keep"},
+		{name: "Right moves the focus", keys: []tea.KeyMsg{{Type: tea.KeyRight}, {Type: tea.KeyEnter}}, button: 1, label: "Replace"},
+		{name: "Tab wraps around", keys: []tea.KeyMsg{{Type: tea.KeyTab}, {Type: tea.KeyTab}, {Type: tea.KeyTab}, {Type: tea.KeyEnter}}, button: 0, label: "Keep"},
+		{name: "Shift+tab moves back", keys: []tea.KeyMsg{{Type: tea.KeyShiftTab}, {Type: tea.KeyEnter}}, button: 2, label: "Skip"},
+		{name: "Confirm presses the first button", keys: []tea.KeyMsg{{Type: tea.KeyRight}, {Type: tea.KeyRunes, Runes: []rune("y")}}, button: 0, label: "Keep"},
+		{name: "Escape cancels", keys: []tea.KeyMsg{{Type: tea.KeyEsc}}, button: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialog := NewDialog("Conflict", "The file exists").WithID("conflict").WithButtons("Keep", "Replace", "Skip")
+			var cmd tea.Cmd
+			for _, key := range tt.keys {
+				cmd = dialog.Update(key)
+			}
+			if !dialog.Closed() {
+				t.Fatal("Expected the dialog to be closed")
+			}
+			if cmd == nil {
+				t.Fatal("Expected a command sending the result")
+			}
+			msg, ok := cmd().(DialogResultMsg)
+			if !ok {
+				t.Fatalf("Expected a DialogResultMsg, got %T", cmd())
+			}
+			if msg.ID != "conflict" || msg.Button != tt.button || msg.Label != tt.label {
+				t.Errorf("Result = %+v, want button %d %q", msg, tt.button, tt.label)
+			}
+		})
+	}
+}
+
+func TestDialogInputs(t *testing.T) {
+	type versionMsg struct{ version string }
+	dialog := NewDialog("Pin a version", "").
+		WithInput("Version", "v1.2.3", "v1.").
+		WithChecklist([]string{"Update go.sum", "Run go mod tidy"}, []bool{true}).
+		OnResult(func(result DialogResult) tea.Msg {
+			return versionMsg{version: result.Inputs[0]}
+		})
+
+	// Keys bound in the dialog are typed into the focused input
+	for _, r := range "yq" {
+		dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if dialog.Closed() {
+		t.Fatal("Expected typing into the input not to close the dialog")
+	}
+	dialog.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	dialog.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("4")})
+
+	// Move to the checklist, then toggle both items
+	dialog.Update(tea.KeyMsg{Type: tea.KeyTab})
+	dialog.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	dialog.Update(tea.KeyMsg{Type: tea.KeyDown})
+	dialog.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	view := dialog.View()
+	for _, expected := range []string{"Version", "v1.4", "[ ] Update go.sum", "> [x] Run go mod tidy"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected the view to contain %q, got:\n%s", expected, view)
+		}
+	}
+
+	cmd := dialog.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected a command sending the result")
+	}
+	if msg := cmd(); msg != (versionMsg{version: "v1.4"}) {
+		t.Errorf("Message = %+v, want the entered version", msg)
+	}
+	if checked := dialog.Result().Checked; len(checked) != 2 || checked[0] || !checked[1] {
+		t.Errorf("Checked = %v, want [false true]", checked)
+	}
+}
+
+func TestDialogScroll(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	dialog := NewDialog("Changes", strings.Join(lines, "\n"))
+	dialog.SetSize(80, 20)
+
+	if view := dialog.View(); !strings.Contains(view, "line 1 ") || strings.Contains(view, "line 30") {
+		t.Errorf("Expected only the start of the message, got:\n%s", view)
+	}
+	for range 30 {
+		dialog.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	view := dialog.View()
+	if !strings.Contains(view, "line 30") || !strings.Contains(view, "of 30 lines") {
+		t.Errorf("Expected the end of the message and the position, got:\n%s", view)
+	}
+	if lipgloss.Height(view) > 20 {
+		t.Errorf("Expected the dialog to fit in 20 lines, got %d", lipgloss.Height(view))
+	}
+}
+
+func TestDialogChecklistScroll(t *testing.T) {
+	var items []string
+	for i := 1; i <= 30; i++ {
+		items = append(items, fmt.Sprintf("repo %d", i))
+	}
+	dialog := NewDialog("Star repositories?", "").WithChecklist(items, nil)
+	dialog.SetSize(80, 20)
+
+	if view := dialog.View(); !strings.Contains(view, "repo 1 ") || strings.Contains(view, "repo 30") {
+		t.Errorf("Expected only the start of the checklist, got:\n%s", view)
+	}
+
+	// The cursor stays shown as it moves to the end
+	for range 29 {
+		dialog.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	view := dialog.View()
+	if !strings.Contains(view, "> [ ] repo 30") || !strings.Contains(view, "of 30 items") {
+		t.Errorf("Expected the end of the checklist and the position, got:\n%s", view)
+	}
+	if lipgloss.Height(view) > 20 {
+		t.Errorf("Expected the dialog to fit in 20 lines, got %d", lipgloss.Height(view))
+	}
+
+	// Checking all checks the items that aren't shown too
+	dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	dialog.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if checked := dialog.Result().Checked; len(checked) != 30 || !checked[0] || !checked[29] {
+		t.Errorf("Checked = %v, want every item", checked)
+	}
+}
+
+func TestPlaceOverlay(t *testing.T) {
+	background := strings.Join([]string{"aaaaaaaaaa", "bbbbbbbbbb", "cccccccccc"}, "\n")
+	got := placeOverlay(background, "XX", 10, 3)
+	want := strings.Join([]string{"aaaaaaaaaa", "bbbb" + ansi.ResetStyle + "XX" + ansi.ResetStyle + "bbbb", "cccccccccc"}, "\n")
+	if got != want {
+		t.Errorf("placeOverlay() = %q, want %q", got, want)
+	}
+}
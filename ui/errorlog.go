@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ErrorLog represents a scrollable view of the errors that occurred this session
+type ErrorLog struct {
+	entries  []StatusEntry
+	viewport viewport.Model
+	help     help.Model
+	width    int
+	height   int
+	styles   ErrorLogStyles
+	keyMap   ErrorLogKeyMap
+}
+
+// ErrorLogStyles contains the styles for the error log
+type ErrorLogStyles struct {
+	Title   lipgloss.Style
+	Time    lipgloss.Style
+	Error   lipgloss.Style
+	Message lipgloss.Style
+}
+
+// DefaultErrorLogStyles returns the default styles for the error log
+func DefaultErrorLogStyles() ErrorLogStyles {
+	return ErrorLogStyles{
+		Title: lipgloss.NewStyle().
+			Foreground(activeTheme.Accent).
+			Bold(true).
+			MarginLeft(2),
+		Time: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted),
+		Error: lipgloss.NewStyle().
+			Foreground(activeTheme.Text),
+		Message: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted).
+			Italic(true),
+	}
+}
+
+// ErrorLogKeyMap defines the key bindings for the error log
+type ErrorLogKeyMap struct {
+	Close key.Binding
+}
+
+// DefaultErrorLogKeyMap returns the default key bindings for the error log
+func DefaultErrorLogKeyMap() ErrorLogKeyMap {
+	return ErrorLogKeyMap{
+		Close: key.NewBinding(
+			key.WithKeys("esc", "q", "!"),
+			key.WithHelp("esc/q", "back"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view.
+func (k ErrorLogKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Close}
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k ErrorLogKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Close}}
+}
+
+// NewErrorLog creates a new error log showing the entries
+func NewErrorLog(entries []StatusEntry) *ErrorLog {
+	e := &ErrorLog{
+		entries:  entries,
+		viewport: viewport.New(80, 20),
+		help:     help.New(),
+		width:    80,
+		height:   24,
+		styles:   DefaultErrorLogStyles(),
+		keyMap:   DefaultErrorLogKeyMap(),
+	}
+	e.updateContent()
+	return e
+}
+
+// SetSize sets the size of the error log
+func (e *ErrorLog) SetSize(width, height int) {
+	e.width = width
+	e.height = height
+	e.help.Width = width
+
+	// Reserve space for the title and help message
+	e.viewport.Width = width
+	e.viewport.Height = height - 4
+	if e.viewport.Height < 1 {
+		e.viewport.Height = 1
+	}
+	e.updateContent()
+}
+
+// Update handles user input and scrolls the error log
+func (e *ErrorLog) Update(msg tea.Msg) (*ErrorLog, tea.Cmd) {
+	var cmd tea.Cmd
+	e.viewport, cmd = e.viewport.Update(msg)
+	return e, cmd
+}
+
+// updateContent renders the errors into the viewport
+func (e *ErrorLog) updateContent() {
+	if len(e.entries) == 0 {
+		e.viewport.SetContent(e.styles.Message.Render("No errors this session."))
+		return
+	}
+
+	// Wrap long errors, leaving room for the timestamp
+	errorStyle := e.styles.Error.Width(e.width - 12)
+	lines := make([]string, len(e.entries))
+	for i, entry := range e.entries {
+		lines[i] = lipgloss.JoinHorizontal(lipgloss.Top,
+			e.styles.Time.Render(entry.Time.Format("15:04:05")+"  "),
+			errorStyle.Render(entry.Message),
+		)
+	}
+	e.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// View renders the error log
+func (e *ErrorLog) View() string {
+	return e.styles.Title.Render("Errors") + "\n\n" + e.viewport.View() + "\n" + e.help.View(e.keyMap)
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// ExportFileName is the file the selected packages are exported to, in the current directory
+const ExportFileName = "gh-lsmod-packages.csv"
+
+// WritePackagesCSV writes the packages as CSV with a header row
+func WritePackagesCSV(w io.Writer, packages []*model.Package) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"path", "version", "latest", "github", "starred", "size", "deprecated"}); err != nil {
+		return err
+	}
+
+	for _, pkg := range packages {
+		record := []string{
+			pkg.Path,
+			pkg.Version,
+			pkg.LatestVersion,
+			pkg.GitHubURL(),
+			strconv.FormatBool(pkg.IsStarred),
+			strconv.FormatInt(pkg.Size, 10),
+			pkg.Deprecated,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportPackages writes the packages to the export file
+func exportPackages(packages []*model.Package) error {
+	file, err := os.Create(ExportFileName)
+	if err != nil {
+		return fmt.Errorf("failed to export packages: %w", err)
+	}
+
+	if err := WritePackagesCSV(file, packages); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to export packages: %w", err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+func TestWritePackagesCSV(t *testing.T) {
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	bubbles.LatestVersion = "v0.21.0"
+	bubbles.IsStarred = true
+	bubbles.Size = 1024
+	protobuf := model.NewPackage("github.com/golang/protobuf", "v1.5.0")
+	protobuf.Deprecated = "Use google.golang.org/protobuf, instead."
+
+	var b strings.Builder
+	if err := WritePackagesCSV(&b, []*model.Package{bubbles, protobuf}); err != nil {
+		t.Fatalf("WritePackagesCSV() returned an error: %v", err)
+	}
+
+	expected := `path,version,latest,github,starred,size,deprecated
+github.com/charmbracelet/bubbles,v0.20.0,v0.21.0,https://github.com/charmbracelet/bubbles/tree/v0.20.0,true,1024,
+github.com/golang/protobuf,v1.5.0,,https://github.com/golang/protobuf/tree/v1.5.0,false,0,"Use google.golang.org/protobuf, instead."
+`
+	if got := b.String(); got != expected {
+		t.Errorf("WritePackagesCSV() =\n%s\nwant\n%s", got, expected)
+	}
+}
@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/filter"
+)
+
+// FilterBar represents the input bar for the filter query
+type FilterBar struct {
+	input    textinput.Model
+	saved    *filter.Saved
+	previous string // Query before editing, restored when editing is cancelled
+	naming   bool   // Whether the name to save the query as is being entered
+	query    string // Query being saved while naming
+	err      error  // Error in the query being edited
+	help     help.Model
+	width    int
+	styles   FilterBarStyles
+	keyMap   FilterBarKeyMap
+}
+
+// FilterBarStyles contains the styles for the filter bar
+type FilterBarStyles struct {
+	Prompt  lipgloss.Style
+	Invalid lipgloss.Style
+	Error   lipgloss.Style
+	Saved   lipgloss.Style
+}
+
+// DefaultFilterBarStyles returns the default styles for the filter bar
+func DefaultFilterBarStyles() FilterBarStyles {
+	return FilterBarStyles{
+		Prompt: lipgloss.NewStyle().
+			Foreground(activeTheme.Accent).
+			Bold(true),
+		Invalid: lipgloss.NewStyle().
+			Foreground(activeTheme.Error).
+			Underline(true),
+		Error: lipgloss.NewStyle().
+			Foreground(activeTheme.Error),
+		Saved: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted),
+	}
+}
+
+// FilterBarKeyMap defines the key bindings for the filter bar
+type FilterBarKeyMap struct {
+	Apply  key.Binding
+	Cancel key.Binding
+	Save   key.Binding
+}
+
+// DefaultFilterBarKeyMap returns the default key bindings for the filter bar
+func DefaultFilterBarKeyMap() FilterBarKeyMap {
+	return FilterBarKeyMap{
+		Apply: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "apply"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+		Save: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("ctrl+s", "save as @name"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view.
+func (k FilterBarKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Apply, k.Cancel, k.Save}
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k FilterBarKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// NewFilterBar creates a new filter bar. Queries can refer to the saved filters as @name.
+func NewFilterBar(saved *filter.Saved) *FilterBar {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "host:github starred:no size:>5MB license:MIT outdated:yes indirect:no text"
+
+	return &FilterBar{
+		input:  input,
+		saved:  saved,
+		help:   help.New(),
+		width:  80,
+		styles: DefaultFilterBarStyles(),
+		keyMap: DefaultFilterBarKeyMap(),
+	}
+}
+
+// Start starts editing the query
+func (b *FilterBar) Start(query string) tea.Cmd {
+	b.previous = query
+	b.naming = false
+	b.input.SetValue(query)
+	b.input.CursorEnd()
+	b.validate()
+	return b.input.Focus()
+}
+
+// Stop stops editing
+func (b *FilterBar) Stop() {
+	b.naming = false
+	b.input.Blur()
+}
+
+// Value returns the query being edited
+func (b *FilterBar) Value() string {
+	return b.input.Value()
+}
+
+// Previous returns the query from before editing started
+func (b *FilterBar) Previous() string {
+	return b.previous
+}
+
+// Parse parses the query being edited
+func (b *FilterBar) Parse() (*filter.Query, error) {
+	return filter.Parse(b.input.Value(), b.saved.Filters)
+}
+
+// StartNaming starts entering the name to save the query as
+func (b *FilterBar) StartNaming() {
+	b.query = b.input.Value()
+	b.naming = true
+	b.input.SetValue("")
+	b.err = nil
+}
+
+// StopNaming goes back to editing the query
+func (b *FilterBar) StopNaming() {
+	b.naming = false
+	b.input.SetValue(b.query)
+	b.input.CursorEnd()
+	b.validate()
+}
+
+// SaveAs saves the query under the name entered, and goes back to editing it
+func (b *FilterBar) SaveAs() (string, error) {
+	name := strings.TrimPrefix(strings.TrimSpace(b.input.Value()), "@")
+	if err := b.saved.Set(name, b.query); err != nil {
+		return "", err
+	}
+	b.StopNaming()
+	return name, nil
+}
+
+// Naming reports whether the name to save the query as is being entered
+func (b *FilterBar) Naming() bool {
+	return b.naming
+}
+
+// Update handles user input in the text input
+func (b *FilterBar) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	b.input, cmd = b.input.Update(msg)
+	if !b.naming {
+		b.validate()
+	}
+	return cmd
+}
+
+// validate parses the query to point out syntax errors while it's typed
+func (b *FilterBar) validate() {
+	_, b.err = b.Parse()
+}
+
+// SetWidth sets the width of the filter bar
+func (b *FilterBar) SetWidth(width int) {
+	b.width = width
+	b.input.Width = width - 6
+	b.help.Width = width
+}
+
+// View renders the filter bar, the invalid part of the query, and the saved filters
+func (b *FilterBar) View() string {
+	if b.naming {
+		return "  " + b.styles.Prompt.Render("Save filter as @") + b.input.View() + "\n  " + b.help.View(b.keyMap)
+	}
+
+	lines := []string{"  " + b.styles.Prompt.Render("/ ") + b.input.View()}
+
+	// Underline the term the error is about, below the query
+	var syntaxErr *filter.SyntaxError
+	switch {
+	case errors.As(b.err, &syntaxErr):
+		query := b.input.Value()
+		end := min(syntaxErr.Offset+syntaxErr.Length, len(query))
+		lines = append(lines, "    "+query[:syntaxErr.Offset]+
+			b.styles.Invalid.Render(query[syntaxErr.Offset:end])+query[end:]+
+			"  "+b.styles.Error.Render(syntaxErr.Msg))
+	case b.err != nil:
+		lines = append(lines, "    "+b.styles.Error.Render(b.err.Error()))
+	}
+
+	hint := b.help.View(b.keyMap)
+	if names := b.saved.Names(); len(names) > 0 {
+		hint += b.styles.Saved.Render("  saved: @" + strings.Join(names, " @"))
+	}
+	lines = append(lines, "  "+hint)
+
+	return lipgloss.NewStyle().MaxWidth(b.width).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tnagatomi/gh-lsmod/filter"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// typeText sends the text to the app one key at a time
+func typeText(app *App, text string) {
+	for _, r := range text {
+		if r == ' ' {
+			app.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+			continue
+		}
+		app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestAppFilter(t *testing.T) {
+	withoutStatusDelay(t)
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	bubbles.IsStarred = true
+	mod := model.NewPackage("golang.org/x/mod", "v0.27.0")
+	lipgloss := model.NewPackage("github.com/charmbracelet/lipgloss", "v1.0.0")
+
+	saved, err := filter.LoadSaved(filepath.Join(t.TempDir(), "filters.yml"))
+	if err != nil {
+		t.Fatalf("LoadSaved() returned an error: %v", err)
+	}
+	app := NewAppWithOptions([]*model.Package{bubbles, mod, lipgloss}, NewMockGitHubClient(), Options{SavedFilters: saved})
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if app.state != StateFilter {
+		t.Fatalf("Expected state to be StateFilter, got %v", app.state)
+	}
+
+	// The list is filtered while typing; keys such as q are part of the query
+	typeText(app, "host:github starred:no")
+	if got := app.list.SelectedPackage(); got != lipgloss || len(app.list.list.Items()) != 1 {
+		t.Errorf("Expected only %s to match, got %d items", lipgloss.Path, len(app.list.list.Items()))
+	}
+
+	// Save the query as a named filter
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	typeText(app, "unstarred")
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if saved.Filters["unstarred"] != "host:github starred:no" {
+		t.Errorf("Expected the filter to be saved, got %v", saved.Filters)
+	}
+
+	// Invalid queries are highlighted and can't be applied
+	typeText(app, " colour:red")
+	view := app.View()
+	if !strings.Contains(view, "unknown key \"colour\"") {
+		t.Errorf("Expected the syntax error to be shown, got:\n%s", view)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.state != StateFilter {
+		t.Errorf("Expected an invalid query not to be applied, got state %v", app.state)
+	}
+
+	// Cancelling restores the previous filter, which was none
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.state != StateList || app.list.Filtered() {
+		t.Errorf("Expected the filter to be cancelled, got state %v and filter %q", app.state, app.list.Filter())
+	}
+
+	// Saved filters are used with @name
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	typeText(app, "@unstarred")
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.state != StateList || app.list.Filter() != "@unstarred" {
+		t.Errorf("Expected the saved filter to be applied, got state %v and filter %q", app.state, app.list.Filter())
+	}
+	if !strings.Contains(app.View(), "@unstarred (1 of 3)") {
+		t.Errorf("Expected the title to show the filter, got:\n%s", app.View())
+	}
+	if app.details.pkg != lipgloss {
+		t.Errorf("Expected the details to show %s, got %v", lipgloss.Path, app.details.pkg)
+	}
+
+	// Escape clears the applied filter
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.list.Filtered() || len(app.list.list.Items()) != 3 {
+		t.Errorf("Expected the filter to be cleared, got %d items", len(app.list.list.Items()))
+	}
+}
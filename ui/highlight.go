@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SyntaxStyles contains the styles for highlighted source code
+type SyntaxStyles struct {
+	Keyword    lipgloss.Style
+	String     lipgloss.Style
+	Number     lipgloss.Style
+	Comment    lipgloss.Style
+	Builtin    lipgloss.Style // Predeclared types, constants and functions
+	LineNumber lipgloss.Style
+}
+
+// DefaultSyntaxStyles returns the default styles for highlighted source code
+func DefaultSyntaxStyles() SyntaxStyles {
+	return SyntaxStyles{
+		Keyword: lipgloss.NewStyle().
+			Foreground(activeTheme.Highlight).
+			Bold(true),
+		String: lipgloss.NewStyle().
+			Foreground(activeTheme.String),
+		Number: lipgloss.NewStyle().
+			Foreground(activeTheme.Number),
+		Comment: lipgloss.NewStyle().
+			Foreground(activeTheme.Subtle).
+			Italic(true),
+		Builtin: lipgloss.NewStyle().
+			Foreground(activeTheme.Builtin),
+		LineNumber: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted),
+	}
+}
+
+// goBuiltins are the predeclared identifiers of Go
+var goBuiltins = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true, "complex64": true, "complex128": true,
+	"error": true, "float32": true, "float64": true, "int": true, "int8": true, "int16": true,
+	"int32": true, "int64": true, "rune": true, "string": true, "uint": true, "uint8": true,
+	"uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"true": true, "false": true, "iota": true, "nil": true,
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true, "copy": true,
+	"delete": true, "imag": true, "len": true, "make": true, "max": true, "min": true, "new": true,
+	"panic": true, "print": true, "println": true, "real": true, "recover": true,
+}
+
+// HighlightGo highlights Go source code with the go/scanner tokens and returns its lines.
+// Tabs are expanded to four spaces. Code that doesn't scan is still returned, less highlighted.
+func HighlightGo(source string, styles SyntaxStyles) []string {
+	src := []byte(expandTabs(source))
+
+	file := token.NewFileSet().AddFile("", -1, len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, scanner.ScanComments)
+
+	var b strings.Builder
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		var style *lipgloss.Style
+		text := lit
+		switch {
+		case tok == token.COMMENT:
+			style = &styles.Comment
+		case tok == token.STRING || tok == token.CHAR:
+			style = &styles.String
+		case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+			style = &styles.Number
+		case tok.IsKeyword():
+			style = &styles.Keyword
+			text = tok.String()
+		case tok == token.IDENT && goBuiltins[lit]:
+			style = &styles.Builtin
+		}
+		if style == nil {
+			continue
+		}
+
+		offset := file.Offset(pos)
+		end := offset + len(text)
+		if offset < last || end > len(src) || string(src[offset:end]) != text {
+			continue // The literal doesn't match the source, such as a comment with carriage returns
+		}
+		b.Write(src[last:offset])
+		b.WriteString(renderLines(*style, text))
+		last = end
+	}
+	b.Write(src[last:])
+
+	return strings.Split(b.String(), "\n")
+}
+
+// renderLines styles every line of the text separately, so that multi-line tokens aren't padded
+func renderLines(style lipgloss.Style, text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = style.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// expandTabs replaces tabs with four spaces and drops carriage returns
+func expandTabs(source string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(source, "\r\n", "\n"), "\t", "    ")
+}
+
+// WithLineNumbers prefixes every line with its number, right-aligned
+func WithLineNumbers(lines []string, style lipgloss.Style) []string {
+	width := len(fmt.Sprint(len(lines)))
+	numbered := make([]string, len(lines))
+	for i, line := range lines {
+		numbered[i] = style.Render(fmt.Sprintf("%*d │ ", width, i+1)) + line
+	}
+	return numbered
+}
@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Layout constants of the details pane
+const (
+	SideBySideMinWidth  = 120 // Terminals at least this wide show the details to the right
+	DefaultDetailsWidth = 40  // Percentage of the width taken by the details on the right
+	MinDetailsWidth     = 25
+	MaxDetailsWidth     = 70
+	DetailsWidthStep    = 5
+	MinDetailsHeight    = 6 // Below this, the details are hidden rather than squashed
+	DetailsHeightStep   = 2
+)
+
+// paneLayout is how the list or the tree and the details pane share the screen
+type paneLayout struct {
+	side          bool // Whether the details are to the right, rather than below
+	mainWidth     int  // Size of the list or the tree, including its help
+	mainHeight    int
+	detailsWidth  int // Size of the details, 0 when they're hidden
+	detailsHeight int
+}
+
+// layout arranges the list or the tree and the details in the given height, which excludes the status bar.
+// Wide terminals show the details to the right, others below, shrinking them on short terminals.
+func (a *App) layout(height int) paneLayout {
+	l := paneLayout{mainWidth: a.width, mainHeight: height}
+	switch {
+	case a.detailsHidden:
+	case a.width >= SideBySideMinWidth:
+		l.side = true
+		l.detailsWidth = a.width * a.detailsWidth / 100
+		l.detailsHeight = height
+		l.mainWidth = a.width - l.detailsWidth
+	default:
+		// The list keeps its minimum height
+		detailsHeight := min(a.detailsHeight, height-MinListHeight-HelpViewHeight)
+		if detailsHeight >= MinDetailsHeight {
+			l.detailsWidth = a.width
+			l.detailsHeight = detailsHeight
+			l.mainHeight = height - detailsHeight
+		}
+	}
+	return l
+}
+
+// joinPanes puts the details to the right of or below the main view, or leaves them out if they're hidden.
+// Anything between the panes, such as the status bar, is kept below the main view.
+func (a *App) joinPanes(l paneLayout, main, between string) string {
+	switch {
+	case l.detailsWidth == 0:
+		return main + "\n" + between
+	case l.side:
+		main = lipgloss.NewStyle().Width(l.mainWidth).Height(l.mainHeight).MaxHeight(l.mainHeight).Render(main)
+		return lipgloss.JoinHorizontal(lipgloss.Top, main, a.details.View()) + "\n" + between
+	}
+	return main + "\n" + between + "\n" + a.details.View()
+}
+
+// updateLayout handles the keys toggling, resizing, maximizing and scrolling the details pane.
+// It reports whether the key was one of them.
+func (a *App) updateLayout(msg tea.KeyMsg) bool {
+	k := a.list.keyMap
+	switch {
+	case key.Matches(msg, k.ToggleDetails):
+		a.detailsHidden = !a.detailsHidden
+	case key.Matches(msg, k.GrowDetails):
+		a.resizeDetails(1)
+	case key.Matches(msg, k.ShrinkDetails):
+		a.resizeDetails(-1)
+	case key.Matches(msg, k.ScrollDetailsDown):
+		a.details.ScrollDown(1)
+	case key.Matches(msg, k.ScrollDetailsUp):
+		a.details.ScrollUp(1)
+	case key.Matches(msg, k.MaximizeDetails):
+		a.detailsReturn = a.state
+		a.state = StateDetails
+	default:
+		return false
+	}
+	a.updateComponentSizes()
+	return true
+}
+
+// resizeDetails grows the details pane by a step, or shrinks it if the direction is negative.
+// The details are shown again if they were hidden.
+func (a *App) resizeDetails(direction int) {
+	if a.detailsHidden {
+		a.detailsHidden = false
+		return
+	}
+	if a.width >= SideBySideMinWidth {
+		a.detailsWidth = max(min(a.detailsWidth+direction*DetailsWidthStep, MaxDetailsWidth), MinDetailsWidth)
+		return
+	}
+	// Start from the height actually shown, which is smaller than the preferred one on short terminals
+	height := a.layout(a.height - a.statusHeight()).detailsHeight
+	if height == 0 {
+		height = a.detailsHeight
+	}
+	a.detailsHeight = max(height+direction*DetailsHeightStep, MinDetailsHeight)
+}
+
+// DetailsKeyMap defines the key bindings for the maximized details pane
+type DetailsKeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Close    key.Binding
+}
+
+// DefaultDetailsKeyMap returns the default key bindings for the maximized details pane
+func DefaultDetailsKeyMap() DetailsKeyMap {
+	return DetailsKeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k", "K"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j", "J"),
+			key.WithHelp("↓/j", "down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "b"),
+			key.WithHelp("pgup/b", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", "f", " "),
+			key.WithHelp("pgdn/f", "page down"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "q", "z"),
+			key.WithHelp("esc/q", "back"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view.
+func (k DetailsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.PageUp, k.PageDown, k.Close}
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k DetailsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// updateDetails handles user input in the maximized details pane
+func (a *App) updateDetails(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return a, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, a.keys.Details.Close):
+		a.state = a.detailsReturn
+		a.updateComponentSizes()
+	case key.Matches(keyMsg, a.keys.Details.Up):
+		a.details.ScrollUp(1)
+	case key.Matches(keyMsg, a.keys.Details.Down):
+		a.details.ScrollDown(1)
+	case key.Matches(keyMsg, a.keys.Details.PageUp):
+		a.details.ScrollUp(a.details.PageSize())
+	case key.Matches(keyMsg, a.keys.Details.PageDown):
+		a.details.ScrollDown(a.details.PageSize())
+	}
+	return a, nil
+}
+
+// detailsView renders the maximized details pane with its help
+func (a *App) detailsView() string {
+	return a.details.View() + "\n" + help.New().View(a.keys.Details)
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// lineContaining returns the first line of the view containing the text, or an empty string
+func lineContaining(view, text string) string {
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, text) {
+			return line
+		}
+	}
+	return ""
+}
+
+func TestAppLayout(t *testing.T) {
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	app := NewApp([]*model.Package{pkg}, NewMockGitHubClient())
+
+	// Wide terminals show the details to the right of the list
+	app.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	view := app.View()
+	if line := lineContaining(view, "Go Module Browser"); !strings.Contains(line, "┌") {
+		t.Errorf("Expected the details to be next to the list title, got:\n%s", view)
+	}
+	if lipgloss.Height(view) != 40 {
+		t.Errorf("Expected the view to fill the height, got %d lines", lipgloss.Height(view))
+	}
+	if app.details.width != 64 || app.details.height != 40-StatusBarHeight {
+		t.Errorf("Expected the details to take 40%% of the width, got %dx%d", app.details.width, app.details.height)
+	}
+
+	// Growing the details takes width from the list
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(">")})
+	if app.details.width != 72 {
+		t.Errorf("Expected the details to grow to 45%% of the width, got %d", app.details.width)
+	}
+
+	// Narrow terminals show the details below the list
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+	view = app.View()
+	if line := lineContaining(view, "Go Module Browser"); strings.Contains(line, "┌") {
+		t.Errorf("Expected the details to be below the list, got:\n%s", view)
+	}
+	if app.details.width != 80 || app.details.height != DetailViewHeight {
+		t.Errorf("Expected the details to be %dx%d, got %dx%d", 80, DetailViewHeight, app.details.width, app.details.height)
+	}
+
+	// Short terminals shrink the details to keep the list usable, or hide them
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 18})
+	if app.details.height != 18-StatusBarHeight-MinListHeight-HelpViewHeight {
+		t.Errorf("Expected the details to shrink, got a height of %d", app.details.height)
+	}
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 12})
+	if strings.Contains(app.View(), "Name: ") {
+		t.Errorf("Expected the details to be hidden, got:\n%s", app.View())
+	}
+
+	// The details can be toggled off
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if strings.Contains(app.View(), "Name: ") {
+		t.Errorf("Expected the details to be toggled off, got:\n%s", app.View())
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if !strings.Contains(app.View(), "Name: ") {
+		t.Errorf("Expected the details to be toggled on, got:\n%s", app.View())
+	}
+}
+
+func TestAppDetailsScroll(t *testing.T) {
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	pkg.Repo = &model.RepoMetadata{Description: "TUI components", Language: "Go", Archived: true}
+	pkg.Deprecated = "Use something else."
+	app := NewApp([]*model.Package{pkg}, NewMockGitHubClient())
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+
+	// The details don't fit in the pane, so they scroll
+	view := app.View()
+	if !strings.Contains(view, "↕ 1-8 of 10 lines") || strings.Contains(view, "pkg.go.dev: ") {
+		t.Fatalf("Expected the details to be cut with their position, got:\n%s", view)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("J")})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("J")})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("J")})
+	view = app.View()
+	if !strings.Contains(view, "↕ 3-10 of 10 lines") || !strings.Contains(view, "pkg.go.dev: ") {
+		t.Errorf("Expected the details to scroll to their end, got:\n%s", view)
+	}
+
+	// Maximized details fill the screen
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	if app.state != StateDetails {
+		t.Fatalf("Expected the details to be maximized, got state %v", app.state)
+	}
+	view = app.View()
+	for _, expected := range []string{"Name: github.com/charmbracelet/bubbles", "pkg.go.dev: ", "esc/q back"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected the maximized details to contain %q, got:\n%s", expected, view)
+		}
+	}
+	if strings.Contains(view, "Go Module Browser") {
+		t.Errorf("Expected the list to be hidden, got:\n%s", view)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.state != StateList || app.details.height != DetailViewHeight {
+		t.Errorf("Expected the list to be back with the details below, got state %v and height %d", app.state, app.details.height)
+	}
+}
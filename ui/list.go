@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -8,12 +9,16 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/filter"
 	"github.com/tnagatomi/gh-lsmod/model"
 )
 
 // PackageItem represents a package in the list
 type PackageItem struct {
-	pkg *model.Package
+	pkg      *model.Package
+	selected map[*model.Package]bool // Selection shared with the list
+	loader   *Loader                 // Shows a spinner while metadata is loading
+	fields   []string                // Fields of the description, the default ones if nil
 }
 
 // FilterValue returns the value to filter on
@@ -21,29 +26,131 @@ func (i PackageItem) FilterValue() string {
 	return i.pkg.Path
 }
 
-// Title returns the title of the item
+// Title returns the title of the item.
+// While packages are selected, every title starts with a selection marker.
 func (i PackageItem) Title() string {
-	return i.pkg.String()
+	if len(i.selected) == 0 {
+		return i.pkg.String()
+	}
+	if i.selected[i.pkg] {
+		return "◉ " + i.pkg.String()
+	}
+	return "○ " + i.pkg.String()
+}
+
+// DefaultDescriptionFields are the fields shown below each package in the list, in order
+var DefaultDescriptionFields = []string{"source", "indirect", "version_flags", "watching", "sponsor", "size", "warnings"}
+
+// descriptionFields renders each field of the item description by its name in the config file.
+// A field renders to an empty string when it doesn't apply to the package.
+var descriptionFields = map[string]func(pkg *model.Package) string{
+	"version": func(pkg *model.Package) string {
+		return pkg.Version
+	},
+	"latest": func(pkg *model.Package) string {
+		// Only shown if it's newer than the pinned version
+		if !pkg.Outdated() {
+			return ""
+		}
+		return "→ " + pkg.LatestVersion
+	},
+	"version_flags": func(pkg *model.Package) string {
+		// Pseudo-versions, +incompatible modules and pre-releases call for a review
+		var flags []string
+		for _, flag := range pkg.ParsedVersion().Flags() {
+			flags = append(flags, "⚑ "+flag)
+		}
+		return strings.Join(flags, " ")
+	},
+	"source": func(pkg *model.Package) string {
+		if pkg.IsGitHub {
+			return "[pkg.go] [GitHub]"
+		}
+		return "[pkg.go]"
+	},
+	"indirect": func(pkg *model.Package) string {
+		if !pkg.Indirect {
+			return ""
+		}
+		return "[indirect]"
+	},
+	"watching": func(pkg *model.Package) string {
+		// A badge when the user gets the repository's notifications, or chose to ignore them
+		switch {
+		case pkg.IsWatched:
+			return "[Watching]"
+		case pkg.IsIgnored:
+			return "[Ignoring]"
+		}
+		return ""
+	},
+	"sponsor": func(pkg *model.Package) string {
+		// A funding badge when the maintainers accept sponsorship
+		if pkg.Repo == nil || !pkg.Repo.Fundable() {
+			return ""
+		}
+		return "[♥ Sponsor]"
+	},
+	"size": func(pkg *model.Package) string {
+		return "(" + pkg.FormattedSize() + ")"
+	},
+	"stars": func(pkg *model.Package) string {
+		if pkg.Repo == nil {
+			return ""
+		}
+		return "★ " + model.FormattedCount(pkg.Repo.Stars)
+	},
+	"warnings": func(pkg *model.Package) string {
+		// Deprecation and retraction warnings
+		var warnings []string
+		if pkg.Deprecated != "" {
+			warnings = append(warnings, "⚠ deprecated")
+		}
+		if pkg.Retracted {
+			warnings = append(warnings, "⚠ retracted")
+		}
+		return strings.Join(warnings, " ")
+	},
 }
 
-// Description returns the description of the item
+// Description returns the description of the item.
+// While metadata is loading, a spinner follows the size, or ends the description if the size isn't shown.
 func (i PackageItem) Description() string {
-	desc := "[pkg.go]"
+	fields := i.fields
+	if fields == nil {
+		fields = DefaultDescriptionFields
+	}
+	loading := i.loader != nil && i.loader.IsLoading(i.pkg)
 
-	if i.pkg.IsGitHub {
-		desc += " [GitHub]"
+	var parts []string
+	for _, field := range fields {
+		if part := descriptionFields[field](i.pkg); part != "" {
+			parts = append(parts, part)
+		}
+		if field == "size" && loading {
+			parts = append(parts, i.loader.Spinner())
+			loading = false
+		}
 	}
-	
-	// Add size information
-	desc += " (" + i.pkg.FormattedSize() + ")"
-	
-	return desc
+	if loading {
+		parts = append(parts, i.loader.Spinner())
+	}
+
+	return strings.Join(parts, " ")
 }
 
 // PackageList represents the list of packages
 type PackageList struct {
 	list     list.Model
 	packages []*model.Package
+	visible  []*model.Package        // Packages matching the filter, in the order they're shown
+	selected map[*model.Package]bool // Selected packages, kept by package so it survives reordering
+	loader   *Loader
+	query    *filter.Query // Filter applied to the packages, nil when everything is shown
+	filter   string        // Text of the filter query
+	order    SortOrder     // Order the visible packages are sorted in
+	title    string        // Title shown before the filter
+	fields   []string      // Fields of the item descriptions
 	keyMap   PackageListKeyMap
 	help     help.Model
 	width    int
@@ -54,9 +161,40 @@ type PackageList struct {
 type PackageListKeyMap struct {
 	OpenGitHub   key.Binding
 	OpenPkgGoDev key.Binding
+	OpenCompare  key.Binding
 	ToggleStar   key.Binding
 	StarAll      key.Binding
+	ReleaseNotes key.Binding
+	Readme       key.Binding
+	Source       key.Binding
+	Sponsors     key.Binding
+	Select       key.Binding
+	SelectAll    key.Binding
+	Invert       key.Binding
+	ClearSelect  key.Binding
+	Actions      key.Binding
+	Help         key.Binding
+	StarLists    key.Binding
+	ToggleWatch  key.Binding
+	WatchAll     key.Binding
+	Tree         key.Binding
+	Filter       key.Binding
+	ClearFilter  key.Binding
+	Sort         key.Binding
+	SortThen     key.Binding
+	Reverse      key.Binding
+	ReverseThen  key.Binding
+	CopyURL      key.Binding
+	Copy         key.Binding
+	ErrorLog     key.Binding
 	Quit         key.Binding
+
+	ToggleDetails     key.Binding
+	MaximizeDetails   key.Binding
+	GrowDetails       key.Binding
+	ShrinkDetails     key.Binding
+	ScrollDetailsDown key.Binding
+	ScrollDetailsUp   key.Binding
 }
 
 // DefaultPackageListKeyMap returns the default key bindings for the package list
@@ -70,6 +208,10 @@ func DefaultPackageListKeyMap() PackageListKeyMap {
 			key.WithKeys("p"),
 			key.WithHelp("p", "open pkg.go.dev"),
 		),
+		OpenCompare: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "compare with latest"),
+		),
 		ToggleStar: key.NewBinding(
 			key.WithKeys("s"),
 			key.WithHelp("s", "star/unstar"),
@@ -78,6 +220,123 @@ func DefaultPackageListKeyMap() PackageListKeyMap {
 			key.WithKeys("S"),
 			key.WithHelp("S", "star all"),
 		),
+		ReleaseNotes: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "release notes"),
+		),
+		Readme: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "README"),
+		),
+		Source: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "browse source"),
+		),
+		Sponsors: key.NewBinding(
+			key.WithKeys("$"),
+			key.WithHelp("$", "sponsors"),
+		),
+		ToggleWatch: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "watch/unwatch"),
+		),
+		WatchAll: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "watch all"),
+		),
+		StarLists: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "star lists"),
+		),
+		Tree: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "dependency tree"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "select"),
+		),
+		SelectAll: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "select all"),
+		),
+		Invert: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "invert selection"),
+		),
+		ClearSelect: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear selection"),
+		),
+		Actions: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "actions on selection"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
+		ClearFilter: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear filter"),
+			key.WithDisabled(),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "sort by"),
+		),
+		SortThen: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "then by"),
+		),
+		Reverse: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "reverse sort"),
+		),
+		ReverseThen: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "reverse then by"),
+		),
+		CopyURL: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "copy URL"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "copy..."),
+		),
+		ErrorLog: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "errors"),
+		),
+		ToggleDetails: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "toggle details"),
+		),
+		MaximizeDetails: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "maximize details"),
+		),
+		GrowDetails: key.NewBinding(
+			key.WithKeys(">"),
+			key.WithHelp(">/<", "resize details"),
+		),
+		ShrinkDetails: key.NewBinding(
+			key.WithKeys("<"),
+			key.WithHelp("<", "shrink details"),
+		),
+		ScrollDetailsDown: key.NewBinding(
+			key.WithKeys("J"),
+			key.WithHelp("J/K", "scroll details"),
+		),
+		ScrollDetailsUp: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "scroll details up"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "more keys"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c"),
 			key.WithHelp("q", "quit"),
@@ -87,34 +346,47 @@ func DefaultPackageListKeyMap() PackageListKeyMap {
 
 // ShortHelp returns keybindings to be shown in the mini help view.
 func (k PackageListKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.OpenGitHub, k.OpenPkgGoDev, k.ToggleStar, k.StarAll, k.Quit}
+	return []key.Binding{k.OpenGitHub, k.OpenPkgGoDev, k.ToggleStar, k.Select, k.Actions, k.Filter, k.Help, k.Quit}
 }
 
 // FullHelp returns keybindings for the expanded help view.
 func (k PackageListKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.OpenGitHub, k.OpenPkgGoDev},
-		{k.ToggleStar, k.StarAll},
-		{k.Quit},
+		{k.OpenGitHub, k.OpenPkgGoDev, k.OpenCompare, k.CopyURL, k.Copy, k.ReleaseNotes, k.Readme, k.Source},
+		{k.ToggleStar, k.StarAll, k.StarLists},
+		{k.ToggleWatch, k.WatchAll, k.Sponsors},
+		{k.Select, k.SelectAll, k.Invert, k.ClearSelect, k.Actions},
+		{k.Filter, k.ClearFilter, k.Sort, k.SortThen, k.Reverse, k.ReverseThen},
+		{k.ToggleDetails, k.MaximizeDetails, k.GrowDetails, k.ScrollDetailsDown},
+		{k.Tree, k.ErrorLog, k.Help, k.Quit},
 	}
 }
 
 // NewPackageList creates a new package list
 func NewPackageList(packages []*model.Package) *PackageList {
 	// Create list items
+	selected := make(map[*model.Package]bool)
 	items := make([]list.Item, len(packages))
 	for i, pkg := range packages {
-		items[i] = PackageItem{pkg: pkg}
+		items[i] = PackageItem{pkg: pkg, selected: selected}
 	}
 
 	// Create list
-	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	// Filtering is done by the filter query rather than the list's own fuzzy filter
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.NormalTitle = delegate.Styles.NormalTitle.Foreground(activeTheme.Text)
+	delegate.Styles.NormalDesc = delegate.Styles.NormalDesc.Foreground(activeTheme.Muted)
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.Foreground(activeTheme.Highlight).BorderForeground(activeTheme.Highlight)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.Foreground(activeTheme.Highlight).BorderForeground(activeTheme.Highlight)
+	l := list.New(items, delegate, 0, 0)
 	l.Title = "Go Module Browser"
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(false)
 	l.SetShowHelp(false)
+	// Quitting is bound by the package list key map, so that it can be configured
+	l.DisableQuitKeybindings()
 	l.Styles.Title = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("99")).
+		Foreground(activeTheme.Accent).
 		Bold(true).
 		MarginLeft(2)
 
@@ -124,6 +396,9 @@ func NewPackageList(packages []*model.Package) *PackageList {
 	return &PackageList{
 		list:     l,
 		packages: packages,
+		visible:  packages,
+		selected: selected,
+		title:    l.Title,
 		keyMap:   keyMap,
 		help:     helpModel,
 	}
@@ -163,16 +438,171 @@ func (l *PackageList) View() string {
 	return listView + strings.Repeat("\n", height) + helpView
 }
 
-// SelectedPackage returns the currently selected package
+// SetLoader shows a spinner on the packages whose metadata the loader is still loading
+func (l *PackageList) SetLoader(loader *Loader) {
+	l.loader = loader
+	l.updateItems()
+}
+
+// SetDescriptionFields sets the fields shown below each package, in order
+func (l *PackageList) SetDescriptionFields(fields []string) {
+	l.fields = fields
+	l.updateItems()
+}
+
+// SetTitle sets the title shown above the list
+func (l *PackageList) SetTitle(title string) {
+	l.title = title
+	l.updateTitle()
+}
+
+// SetFilter shows only the packages matching the query. A nil or empty query shows every package.
+func (l *PackageList) SetFilter(text string, query *filter.Query) {
+	if query != nil && query.Empty() {
+		query = nil
+	}
+	l.filter = text
+	l.query = query
+	l.keyMap.ClearFilter.SetEnabled(query != nil)
+	l.Refresh()
+}
+
+// SetSortOrder sorts the visible packages
+func (l *PackageList) SetSortOrder(order SortOrder) {
+	l.order = order
+	l.Refresh()
+}
+
+// SortOrder returns the order the visible packages are sorted in
+func (l *PackageList) SortOrder() SortOrder {
+	return l.order
+}
+
+// Refresh applies the filter and the sort order again, after the packages' metadata has changed.
+// The cursor stays on the same package if it still matches.
+func (l *PackageList) Refresh() {
+	current := l.SelectedPackage()
+
+	// Filter and sort a copy, so that the packages stay in go.mod order
+	if l.query != nil {
+		l.visible = l.query.Filter(l.packages)
+	} else {
+		l.visible = append([]*model.Package(nil), l.packages...)
+	}
+	l.order.Sort(l.visible)
+	l.updateItems()
+	l.updateTitle()
+
+	l.list.Select(0)
+	for i, pkg := range l.visible {
+		if pkg == current {
+			l.list.Select(i)
+			break
+		}
+	}
+}
+
+// Filter returns the text of the filter query, or an empty string if the list isn't filtered
+func (l *PackageList) Filter() string {
+	if l.query == nil {
+		return ""
+	}
+	return l.filter
+}
+
+// Filtered reports whether only the packages matching a filter are shown
+func (l *PackageList) Filtered() bool {
+	return l.query != nil
+}
+
+// updateItems shows the visible packages in the list
+func (l *PackageList) updateItems() {
+	items := make([]list.Item, len(l.visible))
+	for i, pkg := range l.visible {
+		items[i] = PackageItem{pkg: pkg, selected: l.selected, loader: l.loader, fields: l.fields}
+	}
+	l.list.SetItems(items)
+}
+
+// updateTitle shows the sort order, the filter and the number of matching packages in the title
+func (l *PackageList) updateTitle() {
+	parts := []string{l.title}
+	if order := l.order.String(); order != "" {
+		parts = append(parts, order)
+	}
+	if l.query != nil {
+		parts = append(parts, fmt.Sprintf("%s (%d of %d)", l.filter, len(l.visible), len(l.packages)))
+	}
+	l.list.Title = strings.Join(parts, " · ")
+}
+
+// SelectedPackage returns the package under the cursor
 func (l *PackageList) SelectedPackage() *model.Package {
-	idx := l.list.Index()
-	if idx < 0 || idx >= len(l.packages) {
+	item, ok := l.list.SelectedItem().(PackageItem)
+	if !ok {
 		return nil
 	}
-	return l.packages[idx]
+	return item.pkg
 }
 
 // SetSize sets the size of the list
 func (l *PackageList) SetSize(width, height int) {
 	l.list.SetSize(width, height)
+	l.help.Width = width
+}
+
+// ToggleSelected selects the package, or deselects it if it's already selected
+func (l *PackageList) ToggleSelected(pkg *model.Package) {
+	if l.selected[pkg] {
+		delete(l.selected, pkg)
+	} else {
+		l.selected[pkg] = true
+	}
+}
+
+// SelectAll selects every package shown in the list
+func (l *PackageList) SelectAll() {
+	for _, pkg := range l.visible {
+		l.selected[pkg] = true
+	}
+}
+
+// InvertSelection selects the packages shown in the list that aren't selected and deselects the others
+func (l *PackageList) InvertSelection() {
+	for _, pkg := range l.visible {
+		l.ToggleSelected(pkg)
+	}
+}
+
+// ClearSelection deselects every package
+func (l *PackageList) ClearSelection() {
+	for pkg := range l.selected {
+		delete(l.selected, pkg)
+	}
+}
+
+// IsSelected reports whether the package is selected
+func (l *PackageList) IsSelected(pkg *model.Package) bool {
+	return l.selected[pkg]
+}
+
+// Selection returns the selected packages in list order
+func (l *PackageList) Selection() []*model.Package {
+	var selection []*model.Package
+	for _, pkg := range l.packages {
+		if l.selected[pkg] {
+			selection = append(selection, pkg)
+		}
+	}
+	return selection
+}
+
+// ToggleHelp switches between the short and the full help
+func (l *PackageList) ToggleHelp() {
+	l.help.ShowAll = !l.help.ShowAll
+}
+
+// HelpHeight returns the number of lines taken by the help
+func (l *PackageList) HelpHeight() int {
+	return lipgloss.Height(l.help.View(l.keyMap))
 }
@@ -3,6 +3,7 @@ package ui
 import (
 	"testing"
 
+	"github.com/tnagatomi/gh-lsmod/filter"
 	"github.com/tnagatomi/gh-lsmod/model"
 )
 
@@ -93,6 +94,44 @@ func TestPackageItemDescription(t *testing.T) {
 			pkg:  model.NewPackage("golang.org/x/mod", "v1.0.0"),
 			expected: "[pkg.go] (unknown)",
 		},
+		{
+			name: "Deprecated package with retracted version",
+			pkg: func() *model.Package {
+				pkg := model.NewPackage("github.com/golang/protobuf", "v1.0.0")
+				pkg.Deprecated = "Use the google.golang.org/protobuf module instead."
+				pkg.Retracted = true
+				return pkg
+			}(),
+			expected: "[pkg.go] [GitHub] (unknown) ⚠ deprecated ⚠ retracted",
+		},
+		{
+			name: "GitHub package accepting sponsorship",
+			pkg: func() *model.Package {
+				pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v1.0.0")
+				pkg.Repo = &model.RepoMetadata{Owner: "charmbracelet", OwnerSponsorable: true}
+				return pkg
+			}(),
+			expected: "[pkg.go] [GitHub] [♥ Sponsor] (unknown)",
+		},
+		{
+			name: "Watched GitHub package",
+			pkg: func() *model.Package {
+				pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v1.0.0")
+				pkg.IsWatched = true
+				return pkg
+			}(),
+			expected: "[pkg.go] [GitHub] [Watching] (unknown)",
+		},
+		{
+			name:     "Pseudo-version",
+			pkg:      model.NewPackage("golang.org/x/mod", "v0.0.0-20240102030405-0123456789ab"),
+			expected: "[pkg.go] ⚑ pseudo-version (unknown)",
+		},
+		{
+			name:     "Incompatible pre-release",
+			pkg:      model.NewPackage("github.com/owner/repo", "v3.0.0-beta.1+incompatible"),
+			expected: "[pkg.go] [GitHub] ⚑ +incompatible ⚑ pre-release (unknown)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -140,3 +179,93 @@ func TestSelectedPackage(t *testing.T) {
 		t.Errorf("SelectedPackage() with out of bounds index = %v, want nil", got)
 	}
 }
+
+func TestSelection(t *testing.T) {
+	pkg1 := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	pkg2 := model.NewPackage("golang.org/x/mod", "v0.8.0")
+	pkg3 := model.NewPackage("github.com/charmbracelet/lipgloss", "v1.0.0")
+	list := NewPackageList([]*model.Package{pkg1, pkg2, pkg3})
+
+	item := PackageItem{pkg: pkg1, selected: list.selected}
+	if got := item.Title(); got != "☆ github.com/charmbracelet/bubbles" {
+		t.Errorf("Title() without a selection = %q", got)
+	}
+
+	list.ToggleSelected(pkg3)
+	list.ToggleSelected(pkg1)
+	if got := list.Selection(); len(got) != 2 || got[0] != pkg1 || got[1] != pkg3 {
+		t.Errorf("Selection() = %v, want packages in list order", got)
+	}
+	if got := item.Title(); got != "◉ ☆ github.com/charmbracelet/bubbles" {
+		t.Errorf("Title() of a selected package = %q", got)
+	}
+	if got := (PackageItem{pkg: pkg2, selected: list.selected}).Title(); got != "○   golang.org/x/mod" {
+		t.Errorf("Title() of an unselected package = %q", got)
+	}
+
+	list.InvertSelection()
+	if got := list.Selection(); len(got) != 1 || got[0] != pkg2 {
+		t.Errorf("Selection() after inverting = %v, want only %s", got, pkg2.Path)
+	}
+
+	list.SelectAll()
+	if got := list.Selection(); len(got) != 3 {
+		t.Errorf("Selection() after selecting all = %v, want 3 packages", got)
+	}
+
+	list.ClearSelection()
+	if got := list.Selection(); len(got) != 0 {
+		t.Errorf("Selection() after clearing = %v, want none", got)
+	}
+}
+
+func TestFilterKeepsSelectedPackage(t *testing.T) {
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	mod := model.NewPackage("golang.org/x/mod", "v0.8.0")
+	lipgloss := model.NewPackage("github.com/charmbracelet/lipgloss", "v1.0.0")
+	lipgloss.IsStarred = true
+	list := NewPackageList([]*model.Package{bubbles, mod, lipgloss})
+
+	// The cursor stays on the same package when the packages before it are hidden
+	list.list.Select(2)
+	query, err := filter.Parse("host:github", nil)
+	if err != nil {
+		t.Fatalf("Parse() returned an error: %v", err)
+	}
+	list.SetFilter("host:github", query)
+	if got := list.SelectedPackage(); got != lipgloss {
+		t.Errorf("SelectedPackage() = %v, want %s", got, lipgloss.Path)
+	}
+
+	// Indexes map to the visible packages, not to all packages
+	list.list.Select(1)
+	if got := list.SelectedPackage(); got != lipgloss {
+		t.Errorf("SelectedPackage() at index 1 = %v, want %s", got, lipgloss.Path)
+	}
+	if want := "Go Module Browser · host:github (2 of 3)"; list.list.Title != want {
+		t.Errorf("Title = %q, want %q", list.list.Title, want)
+	}
+
+	// Only visible packages are selected
+	list.SelectAll()
+	if got := list.Selection(); len(got) != 2 || got[0] != bubbles || got[1] != lipgloss {
+		t.Errorf("Selection() = %v, want the visible packages", got)
+	}
+
+	// Packages that no longer match are hidden when the filter is applied again
+	query, err = filter.Parse("starred:no", nil)
+	if err != nil {
+		t.Fatalf("Parse() returned an error: %v", err)
+	}
+	list.SetFilter("starred:no", query)
+	bubbles.IsStarred = true
+	list.Refresh()
+	if got := list.SelectedPackage(); got != mod {
+		t.Errorf("SelectedPackage() after refiltering = %v, want %s", got, mod.Path)
+	}
+
+	list.SetFilter("", nil)
+	if list.Filtered() || len(list.list.Items()) != 3 || list.list.Title != "Go Module Browser" {
+		t.Errorf("Expected every package after clearing the filter, got %d items titled %q", len(list.list.Items()), list.list.Title)
+	}
+}
@@ -0,0 +1,318 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/goproxy"
+	"github.com/tnagatomi/gh-lsmod/metadata"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// loadTask is a kind of metadata loaded in the background
+type loadTask int
+
+const (
+	loadSize loadTask = iota
+	loadModuleStatus
+	loadGitHub
+)
+
+// String returns the name of the task shown in the status line
+func (t loadTask) String() string {
+	switch t {
+	case loadSize:
+		return "sizes"
+	case loadModuleStatus:
+		return "module status"
+	case loadGitHub:
+		return "GitHub"
+	}
+	return ""
+}
+
+// sizeLoadedMsg is sent when the size of a package has been calculated
+type sizeLoadedMsg struct {
+	pkg  *model.Package
+	size int64
+	err  error
+}
+
+// moduleStatusLoadedMsg is sent when the module status of a package has been looked up.
+// status is a copy of the package with the module status filled in.
+type moduleStatusLoadedMsg struct {
+	pkg    *model.Package
+	status *model.Package
+	err    error
+}
+
+// repositoriesLoadedMsg is sent when the GitHub metadata of the packages has been fetched.
+// original and fetched hold copies of the packages, in the same order, from before and after fetching.
+type repositoriesLoadedMsg struct {
+	packages []*model.Package
+	original []*model.Package
+	fetched  []*model.Package
+	err      error
+}
+
+// Loader tracks the metadata loaded in the background after the TUI starts
+type Loader struct {
+	pending map[*model.Package]int
+	total   map[loadTask]int
+	done    map[loadTask]int
+	results <-chan tea.Msg // Results of the sizes and module status being loaded
+	queued  int            // Number of results not received yet
+	failed  int            // Number of modules whose status failed to load, reported once all have loaded
+	spinner spinner.Model
+	styles  LoaderStyles
+}
+
+// LoaderStyles contains the styles for the loading status line
+type LoaderStyles struct {
+	Status lipgloss.Style
+}
+
+// DefaultLoaderStyles returns the default styles for the loading status line
+func DefaultLoaderStyles() LoaderStyles {
+	return LoaderStyles{
+		Status: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted).
+			MarginLeft(2),
+	}
+}
+
+// NewLoader creates a new loader
+func NewLoader() *Loader {
+	return &Loader{
+		pending: make(map[*model.Package]int),
+		total:   make(map[loadTask]int),
+		done:    make(map[loadTask]int),
+		spinner: spinner.New(spinner.WithSpinner(spinner.MiniDot)),
+		styles:  DefaultLoaderStyles(),
+	}
+}
+
+// Start returns the commands loading the sizes, module status and GitHub metadata of the packages.
+// Sizes and module status are loaded metadata.Concurrency at a time, while the GitHub metadata is fetched in batches.
+// A nil module status checker or GitHub client skips that metadata.
+func (l *Loader) Start(packages []*model.Package, checker metadata.ModuleStatusChecker, githubClient github.GitHubClient) tea.Cmd {
+	var cmds, jobs []tea.Cmd
+
+	for _, pkg := range packages {
+		l.add(loadSize, pkg)
+		jobs = append(jobs, loadPackageSize(pkg))
+	}
+
+	if checker != nil {
+		for _, pkg := range packages {
+			l.add(loadModuleStatus, pkg)
+			jobs = append(jobs, loadModuleStatusOf(checker, pkg))
+		}
+	}
+
+	if len(jobs) > 0 {
+		cmds = append(cmds, l.run(jobs))
+	}
+
+	if githubClient != nil {
+		var githubPackages []*model.Package
+		for _, pkg := range packages {
+			if pkg.IsGitHub {
+				l.add(loadGitHub, pkg)
+				githubPackages = append(githubPackages, pkg)
+			}
+		}
+		if len(githubPackages) > 0 {
+			cmds = append(cmds, loadRepositories(githubClient, githubPackages))
+		}
+	}
+
+	if len(cmds) == 0 {
+		return nil
+	}
+	return tea.Batch(append(cmds, l.spinner.Tick)...)
+}
+
+// add records that the metadata of the package is being loaded
+func (l *Loader) add(task loadTask, pkg *model.Package) {
+	l.pending[pkg]++
+	l.total[task]++
+}
+
+// finish records that the metadata of the package has been loaded
+func (l *Loader) finish(task loadTask, pkg *model.Package) {
+	if l.pending[pkg] > 0 {
+		l.pending[pkg]--
+	}
+	if l.pending[pkg] == 0 {
+		delete(l.pending, pkg)
+	}
+	l.done[task]++
+}
+
+// run runs the jobs in the background, at most metadata.Concurrency at a time, and returns the command
+// receiving the first result. The results are buffered, so that the jobs don't wait for them to be handled.
+func (l *Loader) run(jobs []tea.Cmd) tea.Cmd {
+	results := make(chan tea.Msg, len(jobs))
+	l.results = results
+	l.queued = len(jobs)
+
+	go metadata.ForEach(len(jobs), metadata.Concurrency, func(i int) {
+		results <- jobs[i]()
+	})
+
+	return l.waitForResult()
+}
+
+// waitForResult returns a command that waits for the next result, or nil once every result has been received
+func (l *Loader) waitForResult() tea.Cmd {
+	if l.queued == 0 {
+		return nil
+	}
+	l.queued--
+
+	results := l.results
+	return func() tea.Msg {
+		return <-results
+	}
+}
+
+// loadPackageSize returns a command that calculates the size of the package
+func loadPackageSize(pkg *model.Package) tea.Cmd {
+	return func() tea.Msg {
+		pkgSize, err := metadata.CalculateSize(pkg)
+		return sizeLoadedMsg{pkg: pkg, size: pkgSize, err: err}
+	}
+}
+
+// loadModuleStatusOf returns a command that looks up the module status of the package
+func loadModuleStatusOf(checker metadata.ModuleStatusChecker, pkg *model.Package) tea.Cmd {
+	// Work on a copy so that the package shown by the TUI is only changed in Update
+	status := *pkg
+	return func() tea.Msg {
+		err := checker.UpdateModuleStatus(&status)
+		return moduleStatusLoadedMsg{pkg: pkg, status: &status, err: err}
+	}
+}
+
+// loadRepositories returns a command that fetches the GitHub metadata of the packages
+func loadRepositories(githubClient github.GitHubClient, packages []*model.Package) tea.Cmd {
+	// Work on copies so that the packages shown by the TUI are only changed in Update
+	original := clonePackages(packages)
+	fetched := clonePackages(packages)
+	return func() tea.Msg {
+		err := githubClient.FetchRepositories(fetched)
+		return repositoriesLoadedMsg{packages: packages, original: original, fetched: fetched, err: err}
+	}
+}
+
+// clonePackages returns copies of the packages, for commands that change them outside of Update
+func clonePackages(packages []*model.Package) []*model.Package {
+	clones := make([]*model.Package, len(packages))
+	for i, pkg := range packages {
+		clone := *pkg
+		clones[i] = &clone
+	}
+	return clones
+}
+
+// Update applies loaded metadata to the packages and advances the spinner.
+// It returns the error that occurred while loading the metadata, if any.
+func (l *Loader) Update(msg tea.Msg) (tea.Cmd, error) {
+	switch msg := msg.(type) {
+	case sizeLoadedMsg:
+		// Modules that haven't been downloaded have no size, which isn't worth reporting
+		if msg.err == nil {
+			msg.pkg.Size = msg.size
+		}
+		l.finish(loadSize, msg.pkg)
+		return l.waitForResult(), nil
+
+	case moduleStatusLoadedMsg:
+		// The publication time is looked up separately, so it may be there even if the rest failed
+		msg.pkg.VersionTime = msg.status.VersionTime
+		if msg.err == nil {
+			msg.pkg.LatestVersion = msg.status.LatestVersion
+			msg.pkg.Deprecated = msg.status.Deprecated
+			msg.pkg.Retracted = msg.status.Retracted
+			msg.pkg.RetractRationale = msg.status.RetractRationale
+		}
+		// Private and unknown modules have no status to look up, which isn't worth reporting
+		if msg.err != nil && !errors.Is(msg.err, goproxy.ErrNoProxy) && !errors.Is(msg.err, goproxy.ErrNotFound) {
+			l.failed++
+		}
+		l.finish(loadModuleStatus, msg.pkg)
+		return l.waitForResult(), l.moduleStatusErr()
+
+	case repositoriesLoadedMsg:
+		// Repositories that failed are shown without metadata; the error covers all of them.
+		// A star or watch changed while fetching is newer than the fetched status, so it's kept.
+		for i, pkg := range msg.packages {
+			original, fetched := msg.original[i], msg.fetched[i]
+			pkg.Repo = fetched.Repo
+			if pkg.IsStarred == original.IsStarred {
+				pkg.IsStarred = fetched.IsStarred
+			}
+			if pkg.IsWatched == original.IsWatched && pkg.IsIgnored == original.IsIgnored {
+				pkg.IsWatched = fetched.IsWatched
+				pkg.IsIgnored = fetched.IsIgnored
+			}
+			l.finish(loadGitHub, pkg)
+		}
+		return nil, msg.err
+
+	case spinner.TickMsg:
+		if !l.Loading() {
+			return nil, nil
+		}
+		var cmd tea.Cmd
+		l.spinner, cmd = l.spinner.Update(msg)
+		return cmd, nil
+	}
+	return nil, nil
+}
+
+// moduleStatusErr returns the failures of the module status lookups once they have all finished,
+// so that they're reported once instead of for each module
+func (l *Loader) moduleStatusErr() error {
+	if l.failed == 0 || l.done[loadModuleStatus] < l.total[loadModuleStatus] {
+		return nil
+	}
+	failed := l.failed
+	l.failed = 0
+	return fmt.Errorf("failed to look up the module status of %d modules", failed)
+}
+
+// Loading reports whether any metadata is still being loaded
+func (l *Loader) Loading() bool {
+	return len(l.pending) > 0
+}
+
+// IsLoading reports whether metadata of the package is still being loaded
+func (l *Loader) IsLoading(pkg *model.Package) bool {
+	return l.pending[pkg] > 0
+}
+
+// Spinner returns the current frame of the loading spinner
+func (l *Loader) Spinner() string {
+	return l.spinner.View()
+}
+
+// View renders the status line with the loading progress, which is empty once everything has loaded
+func (l *Loader) View() string {
+	var parts []string
+	for _, task := range []loadTask{loadSize, loadModuleStatus, loadGitHub} {
+		if l.total[task] > 0 && l.done[task] < l.total[task] {
+			parts = append(parts, fmt.Sprintf("%s %d/%d", task, l.done[task], l.total[task]))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return l.styles.Status.Render(l.spinner.View() + " Loading " + strings.Join(parts, " · "))
+}
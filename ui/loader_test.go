@@ -0,0 +1,247 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tnagatomi/gh-lsmod/goproxy"
+	"github.com/tnagatomi/gh-lsmod/metadata"
+	"github.com/tnagatomi/gh-lsmod/model"
+	"github.com/tnagatomi/gh-lsmod/size"
+)
+
+// mockModuleStatusChecker reports the same latest version for every module
+type mockModuleStatusChecker struct {
+	failing map[string]error
+}
+
+// UpdateModuleStatus mocks looking up the module status
+func (m *mockModuleStatusChecker) UpdateModuleStatus(pkg *model.Package) error {
+	if err := m.failing[pkg.Path]; err != nil {
+		return err
+	}
+	pkg.LatestVersion = "v9.9.9"
+	pkg.Deprecated = "use something else"
+	return nil
+}
+
+// loadMessages runs the commands of a batch and returns their messages, skipping spinner ticks and status timers
+func loadMessages(t *testing.T, cmd tea.Cmd) []tea.Msg {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("Expected commands loading the metadata, got nil")
+	}
+
+	var msgs []tea.Msg
+	var collect func(cmd tea.Cmd)
+	collect = func(cmd tea.Cmd) {
+		switch msg := cmd().(type) {
+		case tea.BatchMsg:
+			for _, c := range msg {
+				if c != nil {
+					collect(c)
+				}
+			}
+		case spinner.TickMsg, statusExpiredMsg:
+		default:
+			msgs = append(msgs, msg)
+		}
+	}
+	collect(cmd)
+	return msgs
+}
+
+// applyMessages passes the messages to the app, along with the messages of the commands it returns,
+// until nothing is left to load
+func applyMessages(t *testing.T, app *App, msgs []tea.Msg) {
+	t.Helper()
+	for len(msgs) > 0 {
+		msg := msgs[0]
+		msgs = msgs[1:]
+		if _, cmd := app.Update(msg); cmd != nil {
+			msgs = append(msgs, loadMessages(t, cmd)...)
+		}
+	}
+}
+
+func TestAppInitLoadsMetadata(t *testing.T) {
+	withoutStatusDelay(t)
+	metadata.CalculateSize = func(pkg *model.Package) (int64, error) {
+		return 2048, nil
+	}
+	defer func() { metadata.CalculateSize = size.CalculatePackageSize }()
+
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	mod := model.NewPackage("golang.org/x/mod", "v0.27.0")
+	private := model.NewPackage("example.com/private/repo", "v1.0.0")
+	unknown := model.NewPackage("example.com/unknown", "v1.0.0")
+
+	mockClient := NewMockGitHubClient()
+	mockClient.starredRepos[bubbles.Path] = true
+	checker := &mockModuleStatusChecker{failing: map[string]error{
+		mod.Path:     errors.New("proxy unavailable"),
+		private.Path: goproxy.ErrNoProxy,
+		unknown.Path: fmt.Errorf("%w: %s", goproxy.ErrNotFound, "example.com/unknown"),
+	}}
+	app := NewAppWithOptions([]*model.Package{bubbles, mod, private, unknown}, mockClient, Options{ModuleStatus: checker})
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	// The list is shown right away, with a spinner on the packages being loaded
+	msgs := loadMessages(t, app.Init())
+	if !app.loader.IsLoading(bubbles) || !app.loader.IsLoading(mod) {
+		t.Fatal("Expected both packages to be loading")
+	}
+	if !strings.Contains(app.View(), "Loading sizes 0/4") {
+		t.Errorf("Expected the status line to show the loading progress, got:\n%s", app.View())
+	}
+
+	// Packages aren't changed until the messages are handled
+	if bubbles.Size != 0 || bubbles.IsStarred || bubbles.LatestVersion != "" {
+		t.Fatalf("Expected the package to be unchanged before Update, got %+v", bubbles)
+	}
+
+	applyMessages(t, app, msgs)
+
+	if app.loader.Loading() {
+		t.Error("Expected loading to have finished")
+	}
+	if bubbles.Size != 2048 || mod.Size != 2048 {
+		t.Errorf("Expected sizes to be loaded, got %d and %d", bubbles.Size, mod.Size)
+	}
+	if !bubbles.IsStarred {
+		t.Error("Expected the star status to be loaded")
+	}
+	if bubbles.LatestVersion != "v9.9.9" || bubbles.Deprecated != "use something else" {
+		t.Errorf("Expected the module status to be loaded, got %q and %q", bubbles.LatestVersion, bubbles.Deprecated)
+	}
+	if mod.LatestVersion != "" {
+		t.Errorf("Expected the failed module status to be left unchanged, got %q", mod.LatestVersion)
+	}
+
+	// The failure is shown in the status bar instead of the progress
+	view := app.View()
+	if strings.Contains(view, "Loading") {
+		t.Errorf("Expected the progress to be hidden, got:\n%s", view)
+	}
+	if !strings.Contains(view, "failed to look up the module status of 1 modules") {
+		t.Errorf("Expected the status line to show the failure, got:\n%s", view)
+	}
+
+	// Private and unknown modules aren't failures, and the others are reported once
+	if errs := app.statusBar.Errors(); len(errs) != 1 {
+		t.Errorf("Expected the module status failures to be logged once, got %v", errs)
+	}
+}
+
+func TestAppWithoutGitHubClient(t *testing.T) {
+	withoutStatusDelay(t)
+	metadata.CalculateSize = func(pkg *model.Package) (int64, error) {
+		return 0, errors.New("not downloaded")
+	}
+	defer func() { metadata.CalculateSize = size.CalculatePackageSize }()
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	app := NewAppWithOptions([]*model.Package{pkg}, nil, Options{
+		StartupErrors: []error{errors.New("GitHub features are unavailable: no token")},
+	})
+
+	// Only the size is loaded, and missing sizes aren't reported
+	msgs := loadMessages(t, app.Init())
+	if len(msgs) != 1 {
+		t.Fatalf("Expected only the size to be loaded, got %d messages", len(msgs))
+	}
+	app.Update(msgs[0])
+
+	// GitHub actions are ignored
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if app.state != StateList {
+		t.Errorf("Expected state to be StateList, got %v", app.state)
+	}
+
+	view := app.View()
+	if !strings.Contains(view, "GitHub features are unavailable: no token") {
+		t.Errorf("Expected the startup error to be shown, got:\n%s", view)
+	}
+
+	// Missing sizes aren't errors
+	if errs := app.statusBar.Errors(); len(errs) != 1 {
+		t.Errorf("Expected only the startup error to be logged, got %v", errs)
+	}
+}
+
+func TestLoaderBoundsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	metadata.CalculateSize = func(pkg *model.Package) (int64, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return 1024, nil
+	}
+	defer func() { metadata.CalculateSize = size.CalculatePackageSize }()
+
+	var packages []*model.Package
+	for i := 0; i < 5*metadata.Concurrency; i++ {
+		packages = append(packages, model.NewPackage(fmt.Sprintf("example.com/module%d", i), "v1.0.0"))
+	}
+	app := NewAppWithOptions(packages, nil, Options{})
+
+	// Results are received one at a time, each handled result asking for the next one
+	msgs := loadMessages(t, app.Init())
+	if len(msgs) != 1 {
+		t.Fatalf("Expected a single result at first, got %d", len(msgs))
+	}
+	applyMessages(t, app, msgs)
+
+	if app.loader.Loading() {
+		t.Error("Expected loading to have finished")
+	}
+	for _, pkg := range packages {
+		if pkg.Size != 1024 {
+			t.Fatalf("Expected the size of %s to be loaded, got %d", pkg.Path, pkg.Size)
+		}
+	}
+	if peak.Load() > metadata.Concurrency {
+		t.Errorf("Expected at most %d sizes to be calculated at a time, got %d", metadata.Concurrency, peak.Load())
+	}
+}
+
+func TestLoaderKeepsLocalChanges(t *testing.T) {
+	withoutStatusDelay(t)
+	metadata.CalculateSize = func(pkg *model.Package) (int64, error) {
+		return 0, errors.New("not downloaded")
+	}
+	defer func() { metadata.CalculateSize = size.CalculatePackageSize }()
+
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	lipgloss := model.NewPackage("github.com/charmbracelet/lipgloss", "v1.0.0")
+	mockClient := NewMockGitHubClient()
+	mockClient.starredRepos[lipgloss.Path] = true
+	app := NewApp([]*model.Package{bubbles, lipgloss}, mockClient)
+
+	// The repositories are fetched before the user stars bubbles, but the result arrives after that
+	msgs := loadMessages(t, app.Init())
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	finishGitHubAction(t, app, cmd)
+	applyMessages(t, app, msgs)
+
+	if !bubbles.IsStarred {
+		t.Error("Expected the star made while loading to be kept")
+	}
+	if !lipgloss.IsStarred {
+		t.Error("Expected the fetched star status to be applied to the unchanged package")
+	}
+}
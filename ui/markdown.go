@@ -0,0 +1,323 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MarkdownStyles contains the styles for Markdown rendered in the terminal
+type MarkdownStyles struct {
+	Title     lipgloss.Style // Level 1 headings
+	Heading   lipgloss.Style // Other headings
+	Code      lipgloss.Style
+	CodeBlock lipgloss.Style
+	Link      lipgloss.Style
+	URL       lipgloss.Style
+	Bold      lipgloss.Style
+	Italic    lipgloss.Style
+	Quote     lipgloss.Style
+	Bullet    lipgloss.Style
+	Rule      lipgloss.Style
+}
+
+// DefaultMarkdownStyles returns the default styles for rendered Markdown
+func DefaultMarkdownStyles() MarkdownStyles {
+	return MarkdownStyles{
+		Title: lipgloss.NewStyle().
+			Foreground(activeTheme.Highlight).
+			Bold(true).
+			Underline(true),
+		Heading: lipgloss.NewStyle().
+			Foreground(activeTheme.Accent).
+			Bold(true),
+		Code: lipgloss.NewStyle().
+			Foreground(activeTheme.Code),
+		CodeBlock: lipgloss.NewStyle().
+			Foreground(activeTheme.Text).
+			Background(activeTheme.Surface),
+		Link: lipgloss.NewStyle().
+			Foreground(activeTheme.Link).
+			Underline(true),
+		URL: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted),
+		Bold: lipgloss.NewStyle().
+			Bold(true),
+		Italic: lipgloss.NewStyle().
+			Italic(true),
+		Quote: lipgloss.NewStyle().
+			Foreground(activeTheme.Subtle).
+			Italic(true),
+		Bullet: lipgloss.NewStyle().
+			Foreground(activeTheme.Highlight),
+		Rule: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted),
+	}
+}
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	fencePattern    = regexp.MustCompile("^\\s*(```|~~~)")
+	rulePattern     = regexp.MustCompile(`^\s*([-*_])(\s*([-*_]))+\s*$`)
+	setextPattern   = regexp.MustCompile(`^\s*(=+|-+)\s*$`)
+	listPattern     = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	quotePattern    = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	tableSeparator  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	imagePattern    = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]*)[^)]*\)`)
+	linkPattern     = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]*)[^)]*\)`)
+	autolinkPattern = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+	boldPattern     = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	italicPattern   = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+	breakPattern    = regexp.MustCompile(`(?i)<br\s*/?>`)
+	tagPattern      = regexp.MustCompile(`</?[A-Za-z][^>]*>|<!--.*?-->`)
+)
+
+// markdownRenderer renders Markdown block by block
+type markdownRenderer struct {
+	width     int
+	styles    MarkdownStyles
+	blocks    []string
+	paragraph []string // Lines of the paragraph being read
+}
+
+// RenderMarkdown renders Markdown for the terminal, wrapped to the width.
+// Headings, code, lists, quotes, rules and links are styled; HTML tags are dropped.
+func RenderMarkdown(source string, width int, styles MarkdownStyles) string {
+	r := &markdownRenderer{width: max(width, 10), styles: styles}
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		switch {
+		case fencePattern.MatchString(line):
+			// Code blocks are kept as they are, up to the closing fence
+			fence := strings.TrimSpace(line)[:3]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			r.addBlock(r.codeBlock(code))
+
+		case strings.TrimSpace(line) == "":
+			r.flushParagraph()
+
+		case len(r.paragraph) > 0 && setextPattern.MatchString(line):
+			// A line of = or - under a paragraph turns it into a heading
+			text := strings.Join(r.paragraph, " ")
+			r.paragraph = nil
+			r.addBlock(r.heading(strings.HasPrefix(strings.TrimSpace(line), "="), text))
+
+		case rulePattern.MatchString(line):
+			r.addBlock(r.styles.Rule.Render(strings.Repeat("─", r.width)))
+
+		case headingPattern.MatchString(line):
+			match := headingPattern.FindStringSubmatch(line)
+			r.addBlock(r.heading(len(match[1]) == 1, match[2]))
+
+		case quotePattern.MatchString(line):
+			var quote []string
+			for ; i < len(lines) && quotePattern.MatchString(lines[i]); i++ {
+				quote = append(quote, quotePattern.FindStringSubmatch(lines[i])[1])
+			}
+			i--
+			r.addBlock(r.quote(strings.Join(quote, " ")))
+
+		case listPattern.MatchString(line):
+			// Lines indented under an item continue it
+			match := listPattern.FindStringSubmatch(line)
+			text := match[3]
+			for i+1 < len(lines) && strings.HasPrefix(lines[i+1], "  ") &&
+				strings.TrimSpace(lines[i+1]) != "" && !listPattern.MatchString(lines[i+1]) {
+				i++
+				text += " " + strings.TrimSpace(lines[i])
+			}
+			r.flushParagraph()
+			r.blocks = append(r.blocks, r.listItem(len(strings.ReplaceAll(match[1], "\t", "  "))/2, match[2], text))
+
+		case strings.HasPrefix(strings.TrimSpace(line), "|"):
+			// Tables are shown as they're written, without the separator row
+			if !tableSeparator.MatchString(line) {
+				r.flushParagraph()
+				r.blocks = append(r.blocks, lipgloss.NewStyle().MaxWidth(r.width).Render(r.inline(strings.TrimSpace(line))))
+			}
+
+		default:
+			r.paragraph = append(r.paragraph, strings.TrimSpace(line))
+		}
+	}
+	r.flushParagraph()
+
+	return strings.Join(r.blocks, "\n")
+}
+
+// addBlock adds a block separated from the previous one by a blank line
+func (r *markdownRenderer) addBlock(block string) {
+	r.flushParagraph()
+	if len(r.blocks) > 0 && r.blocks[len(r.blocks)-1] != "" {
+		r.blocks = append(r.blocks, "")
+	}
+	r.blocks = append(r.blocks, block, "")
+}
+
+// flushParagraph renders the paragraph being read, if any.
+// Paragraphs made only of HTML tags are dropped.
+func (r *markdownRenderer) flushParagraph() {
+	if len(r.paragraph) == 0 {
+		return
+	}
+	text := strings.TrimSpace(r.inline(strings.Join(r.paragraph, " ")))
+	r.paragraph = nil
+	if text != "" {
+		r.addBlock(r.wrap(text, r.width))
+	}
+}
+
+// heading renders a heading, level 1 headings standing out more
+func (r *markdownRenderer) heading(title bool, text string) string {
+	style := r.styles.Heading
+	if title {
+		style = r.styles.Title
+	}
+	return r.wrap(style.Render(r.plainInline(text)), r.width)
+}
+
+// codeBlock renders the lines of a code block, truncated to the width
+func (r *markdownRenderer) codeBlock(code []string) string {
+	if len(code) == 0 {
+		code = []string{""}
+	}
+	longest := 0
+	for i, line := range code {
+		code[i] = strings.ReplaceAll(line, "\t", "    ")
+		longest = max(longest, lipgloss.Width(code[i]))
+	}
+	// Lines are padded to the longest one so that the background forms a block
+	style := r.styles.CodeBlock.MaxWidth(r.width)
+	for i, line := range code {
+		code[i] = style.Render(" " + line + strings.Repeat(" ", longest-lipgloss.Width(line)+1))
+	}
+	return strings.Join(code, "\n")
+}
+
+// quote renders a block quote with a bar in front of every line
+func (r *markdownRenderer) quote(text string) string {
+	lines := strings.Split(r.wrap(r.styles.Quote.Render(r.plainInline(text)), r.width-2), "\n")
+	for i, line := range lines {
+		lines[i] = r.styles.Rule.Render("│ ") + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// listItem renders a list item with a hanging indent
+func (r *markdownRenderer) listItem(level int, marker, text string) string {
+	bullet := "• "
+	if marker[0] >= '0' && marker[0] <= '9' {
+		bullet = marker + " "
+	}
+	indent := strings.Repeat("  ", level) + strings.Repeat(" ", lipgloss.Width(bullet))
+
+	// Task list items are shown with a checkbox
+	switch {
+	case strings.HasPrefix(text, "[ ] "):
+		text = "☐ " + text[4:]
+	case strings.HasPrefix(text, "[x] "), strings.HasPrefix(text, "[X] "):
+		text = "☑ " + text[4:]
+	}
+
+	lines := strings.Split(r.wrap(r.inline(text), r.width-len(indent)), "\n")
+	for i, line := range lines {
+		if i == 0 {
+			lines[i] = strings.Repeat("  ", level) + r.styles.Bullet.Render(bullet) + line
+		} else {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrap wraps styled text to the width
+func (r *markdownRenderer) wrap(text string, width int) string {
+	return lipgloss.NewStyle().Width(max(width, 1)).Render(text)
+}
+
+// inline renders inline code, links, images, emphasis and HTML in the text
+func (r *markdownRenderer) inline(text string) string {
+	// Code spans are taken as they are, so they're split off first
+	parts := strings.Split(text, "`")
+	for i, part := range parts {
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = r.styles.Code.Render(part)
+			continue
+		}
+		if i%2 == 1 {
+			part = "`" + part // Unbalanced backtick
+		}
+		parts[i] = r.inlineText(part)
+	}
+	return strings.Join(parts, "")
+}
+
+// plainInline renders inline markup without styling code and links, for text that's styled as a whole
+func (r *markdownRenderer) plainInline(text string) string {
+	text = strings.ReplaceAll(text, "`", "")
+	text = imagePattern.ReplaceAllString(text, "$1")
+	text = linkPattern.ReplaceAllString(text, "$1")
+	text = boldPattern.ReplaceAllString(text, "$1$2")
+	text = breakPattern.ReplaceAllString(text, " ")
+	return tagPattern.ReplaceAllString(text, "")
+}
+
+// inlineText renders the inline markup of text outside code spans
+func (r *markdownRenderer) inlineText(text string) string {
+	text = autolinkPattern.ReplaceAllString(text, "[$1]($1)")
+	text = breakPattern.ReplaceAllString(text, " ")
+	text = tagPattern.ReplaceAllString(text, "")
+
+	// Images can't be shown, so their description takes their place
+	text = imagePattern.ReplaceAllStringFunc(text, func(s string) string {
+		alt := imagePattern.FindStringSubmatch(s)[1]
+		if alt == "" {
+			return ""
+		}
+		return r.styles.URL.Render("[" + alt + "]")
+	})
+
+	// Absolute URLs are shown after the link text, since they can't be clicked
+	text = linkPattern.ReplaceAllStringFunc(text, func(s string) string {
+		match := linkPattern.FindStringSubmatch(s)
+		label, url := match[1], match[2]
+		if label == "" {
+			label = url
+		}
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") || label == url {
+			return r.styles.Link.Render(label)
+		}
+		return r.styles.Link.Render(label) + " " + r.styles.URL.Render("("+url+")")
+	})
+
+	text = boldPattern.ReplaceAllStringFunc(text, func(s string) string {
+		match := boldPattern.FindStringSubmatch(s)
+		return r.styles.Bold.Render(match[1] + match[2])
+	})
+	return italicPattern.ReplaceAllStringFunc(text, func(s string) string {
+		return r.styles.Italic.Render(italicPattern.FindStringSubmatch(s)[1])
+	})
+}
+
+// RenderPlainText wraps text that isn't Markdown, such as reStructuredText, to the width
+func RenderPlainText(source string, width int) string {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	style := lipgloss.NewStyle().Width(max(width, 1))
+	for i, line := range lines {
+		lines[i] = style.Render(strings.ReplaceAll(line, "\t", "    "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isMarkdownFile reports whether the file name has a Markdown extension
+func isMarkdownFile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, ".md") || strings.HasSuffix(lower, ".markdown")
+}
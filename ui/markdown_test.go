@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// plainLines renders the Markdown and returns its lines without styling or trailing spaces
+func plainLines(source string, width int) []string {
+	lines := strings.Split(ansi.Strip(RenderMarkdown(source, width, DefaultMarkdownStyles())), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		width    int
+		expected []string
+	}{
+		{
+			name:     "Headings and paragraphs",
+			source:   "# Title\n\nSome text\non two lines.\n\nSubtitle\n--------\n\n## Usage ##",
+			width:    40,
+			expected: []string{"Title", "", "Some text on two lines.", "", "Subtitle", "", "Usage", ""},
+		},
+		{
+			name:     "Paragraphs are wrapped",
+			source:   "one two three four five six",
+			width:    14,
+			expected: []string{"one two three", "four five six", ""},
+		},
+		{
+			name:     "Code blocks keep their formatting",
+			source:   "```go\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```",
+			width:    40,
+			expected: []string{" func main() {", "     fmt.Println(\"hi\")", " }", ""},
+		},
+		{
+			name:     "Lists with continuation lines and nesting",
+			source:   "- first\n  continued\n- second\n  - nested\n1. one\n- [x] done",
+			width:    40,
+			expected: []string{"• first continued", "• second", "  • nested", "1. one", "• ☑ done"},
+		},
+		{
+			name:     "Links, images and inline markup",
+			source:   "See [the docs](https://example.com/docs), [local](docs/x.md) and `code` **now** ![logo](logo.png) <https://go.dev>",
+			width:    100,
+			expected: []string{"See the docs (https://example.com/docs), local and code now [logo] https://go.dev", ""},
+		},
+		{
+			name:     "HTML is dropped",
+			source:   "<p align=\"center\">\n<img src=\"logo.png\">\n</p>\n\nText<br>after",
+			width:    40,
+			expected: []string{"Text after", ""},
+		},
+		{
+			name:     "Quotes and rules",
+			source:   "> quoted\n> text\n\n---",
+			width:    10,
+			expected: []string{"│ quoted", "│ text", "", "──────────", ""},
+		},
+		{
+			name:     "Tables without the separator row",
+			source:   "| a | b |\n|---|:-:|\n| 1 | 2 |",
+			width:    40,
+			expected: []string{"| a | b |", "| 1 | 2 |"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := plainLines(tt.source, tt.width)
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("RenderMarkdown() =\n%q\nwant\n%q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// PagerGutterWidth is the width of the column in front of every line marking search matches
+const PagerGutterWidth = 2
+
+// Pager represents a scrollable view of rendered lines that can be searched like less
+type Pager struct {
+	lines     []string // Rendered lines
+	plain     []string // Lines without styling, searched in
+	viewport  viewport.Model
+	input     textinput.Model
+	searching bool   // Whether the search term is being entered
+	term      string // Last term searched for
+	matches   []int  // Lines matching the term
+	match     int    // Index of the current match
+	styles    PagerStyles
+	keyMap    PagerKeyMap
+}
+
+// PagerStyles contains the styles for the pager
+type PagerStyles struct {
+	Match   lipgloss.Style // Current match
+	Marker  lipgloss.Style // Gutter of matching lines
+	Prompt  lipgloss.Style
+	Message lipgloss.Style
+}
+
+// DefaultPagerStyles returns the default styles for the pager
+func DefaultPagerStyles() PagerStyles {
+	return PagerStyles{
+		Match: lipgloss.NewStyle().
+			Foreground(activeTheme.Inverse).
+			Background(activeTheme.Warning),
+		Marker: lipgloss.NewStyle().
+			Foreground(activeTheme.Warning),
+		Prompt: lipgloss.NewStyle().
+			Foreground(activeTheme.Accent).
+			Bold(true),
+		Message: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted),
+	}
+}
+
+// PagerKeyMap defines the key bindings for the pager
+type PagerKeyMap struct {
+	Search   key.Binding
+	Next     key.Binding
+	Previous key.Binding
+	Top      key.Binding
+	Bottom   key.Binding
+	Apply    key.Binding
+	Cancel   key.Binding
+}
+
+// DefaultPagerKeyMap returns the default key bindings for the pager
+func DefaultPagerKeyMap() PagerKeyMap {
+	return PagerKeyMap{
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search"),
+		),
+		Next: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n/N", "next/previous match"),
+		),
+		Previous: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "previous match"),
+		),
+		Top: key.NewBinding(
+			key.WithKeys("g", "home"),
+			key.WithHelp("g/G", "top/bottom"),
+		),
+		Bottom: key.NewBinding(
+			key.WithKeys("G", "end"),
+			key.WithHelp("G", "bottom"),
+		),
+		Apply: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "search"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view.
+// Next and Previous, and Top and Bottom, share a help entry.
+func (k PagerKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Search, k.Next, k.Top}
+}
+
+// NewPager creates a new pager
+func NewPager() *Pager {
+	input := textinput.New()
+	input.Prompt = ""
+
+	return &Pager{
+		viewport: viewport.New(80, 20),
+		input:    input,
+		styles:   DefaultPagerStyles(),
+		keyMap:   DefaultPagerKeyMap(),
+	}
+}
+
+// SetContent sets the rendered content, searching it again for the last term
+func (p *Pager) SetContent(content string) {
+	p.lines = strings.Split(content, "\n")
+	p.plain = make([]string, len(p.lines))
+	for i, line := range p.lines {
+		p.plain[i] = strings.ToLower(ansi.Strip(line))
+	}
+	p.findMatches()
+	p.match = 0
+	p.updateContent()
+}
+
+// SetSize sets the size of the pager, including the gutter
+func (p *Pager) SetSize(width, height int) {
+	p.viewport.Width = width
+	p.viewport.Height = max(height, 1)
+	p.input.Width = width - 10
+	p.updateContent()
+}
+
+// ContentWidth returns the width available for the content, next to the gutter
+func (p *Pager) ContentWidth() int {
+	return p.viewport.Width - PagerGutterWidth
+}
+
+// Searching reports whether the search term is being entered
+func (p *Pager) Searching() bool {
+	return p.searching
+}
+
+// Search searches for the term, case-insensitively, and scrolls to the first match from the top of the view
+func (p *Pager) Search(term string) {
+	p.SearchFrom(term, p.viewport.YOffset)
+}
+
+// SearchFrom searches for the term, case-insensitively, and scrolls to the first match from the line
+func (p *Pager) SearchFrom(term string, from int) {
+	p.term = term
+	p.findMatches()
+	p.match = 0
+	for i, line := range p.matches {
+		if line >= from {
+			p.match = i
+			break
+		}
+	}
+	p.updateContent()
+	p.scrollToMatch()
+}
+
+// findMatches finds the lines containing the search term
+func (p *Pager) findMatches() {
+	p.matches = nil
+	if p.term == "" {
+		return
+	}
+	term := strings.ToLower(p.term)
+	for i, line := range p.plain {
+		if strings.Contains(line, term) {
+			p.matches = append(p.matches, i)
+		}
+	}
+}
+
+// NextMatch scrolls to the next match, wrapping around at the end
+func (p *Pager) NextMatch() {
+	if len(p.matches) == 0 {
+		return
+	}
+	p.match = (p.match + 1) % len(p.matches)
+	p.updateContent()
+	p.scrollToMatch()
+}
+
+// PreviousMatch scrolls to the previous match, wrapping around at the start
+func (p *Pager) PreviousMatch() {
+	if len(p.matches) == 0 {
+		return
+	}
+	p.match = (p.match + len(p.matches) - 1) % len(p.matches)
+	p.updateContent()
+	p.scrollToMatch()
+}
+
+// CurrentLine returns the line of the current match, or the line at the top of the view
+func (p *Pager) CurrentLine() int {
+	if len(p.matches) > 0 {
+		return p.matches[p.match]
+	}
+	return p.viewport.YOffset
+}
+
+// GotoLine scrolls so that the line is a third of the way down the view
+func (p *Pager) GotoLine(line int) {
+	p.viewport.SetYOffset(line - p.viewport.Height/3)
+}
+
+// scrollToMatch scrolls to the current match
+func (p *Pager) scrollToMatch() {
+	if len(p.matches) > 0 {
+		p.GotoLine(p.matches[p.match])
+	}
+}
+
+// updateContent puts the lines into the viewport, marking the matches in the gutter
+func (p *Pager) updateContent() {
+	matching := make(map[int]bool, len(p.matches))
+	for _, line := range p.matches {
+		matching[line] = true
+	}
+
+	lines := make([]string, len(p.lines))
+	for i, line := range p.lines {
+		switch {
+		case len(p.matches) > 0 && i == p.matches[p.match]:
+			// The current match loses its styling to stand out
+			lines[i] = p.styles.Marker.Render("▶ ") + p.styles.Match.Render(ansi.Strip(line))
+		case matching[i]:
+			lines[i] = p.styles.Marker.Render("▌ ") + line
+		default:
+			lines[i] = strings.Repeat(" ", PagerGutterWidth) + line
+		}
+	}
+	p.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// Update handles the search input and scrolling
+func (p *Pager) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if p.searching {
+		switch {
+		case ok && key.Matches(keyMsg, p.keyMap.Apply):
+			p.searching = false
+			p.input.Blur()
+			p.Search(p.input.Value())
+			return nil
+		case ok && key.Matches(keyMsg, p.keyMap.Cancel):
+			p.searching = false
+			p.input.Blur()
+			return nil
+		}
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		return cmd
+	}
+
+	if ok {
+		switch {
+		case key.Matches(keyMsg, p.keyMap.Search):
+			p.searching = true
+			p.input.SetValue("")
+			return p.input.Focus()
+		case key.Matches(keyMsg, p.keyMap.Next):
+			p.NextMatch()
+			return nil
+		case key.Matches(keyMsg, p.keyMap.Previous):
+			p.PreviousMatch()
+			return nil
+		case key.Matches(keyMsg, p.keyMap.Top):
+			p.viewport.GotoTop()
+			return nil
+		case key.Matches(keyMsg, p.keyMap.Bottom):
+			p.viewport.GotoBottom()
+			return nil
+		}
+	}
+
+	var cmd tea.Cmd
+	p.viewport, cmd = p.viewport.Update(msg)
+	return cmd
+}
+
+// View renders the lines in view
+func (p *Pager) View() string {
+	return p.viewport.View()
+}
+
+// SearchView renders the search input while the term is entered, or the matches of the last search
+func (p *Pager) SearchView() string {
+	switch {
+	case p.searching:
+		return p.styles.Prompt.Render("/") + p.input.View()
+	case p.term == "":
+		return ""
+	case len(p.matches) == 0:
+		return p.styles.Message.Render(fmt.Sprintf("No matches for %q", p.term))
+	}
+	return p.styles.Message.Render(fmt.Sprintf("Match %d of %d for %q", p.match+1, len(p.matches), p.term))
+}
@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/modcache"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// readReadme reads the README of a module version, from the module cache by default
+var readReadme = modcache.ReadReadme
+
+// readmeMsg is sent when the README of a package has been loaded
+type readmeMsg struct {
+	pkg     *model.Package
+	name    string
+	source  string // Where the README was read from, "the module cache" or "GitHub"
+	content string
+	err     error
+}
+
+// loadReadme returns a command that reads the README of a package from the module cache,
+// or fetches it from GitHub if the module isn't cached
+func loadReadme(githubClient github.GitHubClient, pkg *model.Package) tea.Cmd {
+	return func() tea.Msg {
+		name, data, err := readReadme(pkg.Path, pkg.Version)
+		if err == nil {
+			return readmeMsg{pkg: pkg, name: name, source: "the module cache", content: string(data)}
+		}
+		if githubClient == nil || !pkg.IsGitHub {
+			return readmeMsg{pkg: pkg, err: fmt.Errorf("failed to read the README from the module cache: %w", err)}
+		}
+
+		readme, err := githubClient.FetchReadme(pkg)
+		if err != nil {
+			return readmeMsg{pkg: pkg, err: err}
+		}
+		return readmeMsg{pkg: pkg, name: readme.Name, source: "GitHub", content: readme.Content}
+	}
+}
+
+// ReadmeView represents a pager showing the README of a package
+type ReadmeView struct {
+	pkg     *model.Package
+	name    string
+	source  string
+	content string
+	err     error
+	loading bool
+	pager   *Pager
+	help    help.Model
+	width   int
+	height  int
+	styles  ReadmeStyles
+	keyMap  ReadmeKeyMap
+}
+
+// ReadmeStyles contains the styles for the README view
+type ReadmeStyles struct {
+	Title    lipgloss.Style
+	Source   lipgloss.Style
+	Message  lipgloss.Style
+	Markdown MarkdownStyles
+}
+
+// DefaultReadmeStyles returns the default styles for the README view
+func DefaultReadmeStyles() ReadmeStyles {
+	return ReadmeStyles{
+		Title: lipgloss.NewStyle().
+			Foreground(activeTheme.Accent).
+			Bold(true).
+			MarginLeft(2),
+		Source: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted),
+		Message: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted).
+			Italic(true),
+		Markdown: DefaultMarkdownStyles(),
+	}
+}
+
+// ReadmeKeyMap defines the key bindings for the README view
+type ReadmeKeyMap struct {
+	Pager PagerKeyMap
+	Close key.Binding
+}
+
+// DefaultReadmeKeyMap returns the default key bindings for the README view
+func DefaultReadmeKeyMap() ReadmeKeyMap {
+	return ReadmeKeyMap{
+		Pager: DefaultPagerKeyMap(),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc/q", "back"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view.
+func (k ReadmeKeyMap) ShortHelp() []key.Binding {
+	return append(k.Pager.ShortHelp(), k.Close)
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k ReadmeKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// NewReadmeView creates a new README view for the package
+func NewReadmeView(pkg *model.Package) *ReadmeView {
+	r := &ReadmeView{
+		pkg:     pkg,
+		loading: true,
+		pager:   NewPager(),
+		help:    help.New(),
+		width:   80,
+		height:  24,
+		styles:  DefaultReadmeStyles(),
+		keyMap:  DefaultReadmeKeyMap(),
+	}
+	r.pager.keyMap = r.keyMap.Pager
+	r.updateContent()
+	return r
+}
+
+// SetPagerKeyMap sets the key bindings for scrolling and searching the README
+func (r *ReadmeView) SetPagerKeyMap(keyMap PagerKeyMap) {
+	r.keyMap.Pager = keyMap
+	r.pager.keyMap = keyMap
+}
+
+// SetReadme sets the loaded README, or the error that occurred while loading it
+func (r *ReadmeView) SetReadme(name, source, content string, err error) {
+	r.name = name
+	r.source = source
+	r.content = content
+	r.err = err
+	r.loading = false
+	r.updateContent()
+}
+
+// SetSize sets the size of the README view
+func (r *ReadmeView) SetSize(width, height int) {
+	r.width = width
+	r.height = height
+	r.help.Width = width
+
+	// Reserve space for the title and help message
+	r.pager.SetSize(width, height-4)
+	r.updateContent()
+}
+
+// Searching reports whether a search term is being entered
+func (r *ReadmeView) Searching() bool {
+	return r.pager.Searching()
+}
+
+// Update handles user input in the pager
+func (r *ReadmeView) Update(msg tea.Msg) tea.Cmd {
+	return r.pager.Update(msg)
+}
+
+// updateContent renders the README into the pager.
+// Markdown is rendered; other formats such as reStructuredText are shown as they are.
+func (r *ReadmeView) updateContent() {
+	width := r.pager.ContentWidth()
+	messageStyle := r.styles.Message.Width(width)
+
+	switch {
+	case r.loading:
+		r.pager.SetContent(messageStyle.Render("Loading README..."))
+	case errors.Is(r.err, github.ErrNoReadme):
+		r.pager.SetContent(messageStyle.Render("No README"))
+	case r.err != nil:
+		r.pager.SetContent(messageStyle.Render("Failed to load the README: " + r.err.Error()))
+	case isMarkdownFile(r.name):
+		r.pager.SetContent(RenderMarkdown(r.content, width, r.styles.Markdown))
+	default:
+		r.pager.SetContent(RenderPlainText(r.content, width))
+	}
+}
+
+// View renders the README view
+func (r *ReadmeView) View() string {
+	title := r.styles.Title.Render(r.pkg.Path + " " + r.pkg.Version)
+	if r.name != "" {
+		title += " " + r.styles.Source.Render(r.name+" from "+r.source)
+	}
+
+	footer := r.help.View(r.keyMap)
+	if search := r.pager.SearchView(); search != "" {
+		footer = "  " + search
+		if !r.pager.Searching() {
+			footer += "  " + r.help.View(r.keyMap)
+		}
+	}
+
+	return title + "\n\n" + r.pager.View() + "\n\n" + lipgloss.NewStyle().MaxWidth(r.width).Render(footer)
+}
@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/modcache"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// withCachedReadmes serves the READMEs from the map, keyed by path@version, as if they were in the module cache
+func withCachedReadmes(t *testing.T, readmes map[string]string) {
+	t.Helper()
+	readReadme = func(path, version string) (string, []byte, error) {
+		content, ok := readmes[path+"@"+version]
+		if !ok {
+			return "", nil, fmt.Errorf("no README for %s@%s: %w", path, version, os.ErrNotExist)
+		}
+		return "README.md", []byte(content), nil
+	}
+	t.Cleanup(func() {
+		readReadme = modcache.ReadReadme
+	})
+}
+
+func TestAppReadme(t *testing.T) {
+	var readme strings.Builder
+	readme.WriteString("# Bubbles\n\n")
+	for i := 1; i <= 40; i++ {
+		fmt.Fprintf(&readme, "Paragraph %d about components.\n\n", i)
+	}
+	readme.WriteString("## Spinner\n\nA spinner component.\n")
+	withCachedReadmes(t, map[string]string{"github.com/charmbracelet/bubbles@v0.20.0": readme.String()})
+
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	lipgloss := model.NewPackage("github.com/charmbracelet/lipgloss", "v1.0.0")
+	mockClient := NewMockGitHubClient()
+	mockClient.readme = &github.Readme{Name: "README.md", Content: "# Lip Gloss\n\nStyle definitions."}
+	app := NewApp([]*model.Package{bubbles, lipgloss}, mockClient)
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	// The README of a cached module is read from the module cache
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if app.state != StateReadme || cmd == nil {
+		t.Fatalf("Expected the README to be loading, got state %v", app.state)
+	}
+	app.Update(cmd())
+	view := app.View()
+	for _, expected := range []string{"README.md from the module cache", "Bubbles", "Paragraph 1 about"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected the README view to contain %q, got:\n%s", expected, view)
+		}
+	}
+	if strings.Contains(view, "Spinner") {
+		t.Error("Expected the end of the README to be out of view")
+	}
+
+	// Searching scrolls to the match
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	typeText(app, "spinner")
+	if view := app.View(); !strings.Contains(view, "/spinner") {
+		t.Errorf("Expected the search input, got:\n%s", view)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	view = app.View()
+	for _, expected := range []string{"Spinner", "A spinner component.", `Match 1 of 2 for "spinner"`} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected the view to contain %q after searching, got:\n%s", expected, view)
+		}
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if view := app.View(); !strings.Contains(view, `Match 2 of 2 for "spinner"`) {
+		t.Errorf("Expected the next match, got:\n%s", view)
+	}
+
+	// Escape cancels a search being entered before it closes the README
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.state != StateReadme {
+		t.Fatalf("Expected the README to stay open, got state %v", app.state)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.state != StateList {
+		t.Fatalf("Expected to be back to the list, got state %v", app.state)
+	}
+
+	// Modules that aren't cached fall back to GitHub
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	app.Update(cmd())
+	view = app.View()
+	for _, expected := range []string{"README.md from GitHub", "Lip Gloss", "Style definitions."} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected the README view to contain %q, got:\n%s", expected, view)
+		}
+	}
+}
+
+func TestLoadReadmeWithoutGitHub(t *testing.T) {
+	withCachedReadmes(t, nil)
+
+	pkg := model.NewPackage("golang.org/x/mod", "v0.27.0")
+	msg := loadReadme(NewMockGitHubClient(), pkg)().(readmeMsg)
+	if msg.err == nil || !strings.Contains(msg.err.Error(), "module cache") {
+		t.Errorf("Expected an error about the module cache for a module not hosted on GitHub, got %v", msg.err)
+	}
+
+	msg = loadReadme(nil, model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0"))().(readmeMsg)
+	if msg.err == nil {
+		t.Error("Expected an error without a GitHub client")
+	}
+}
+
+func TestAppReadmeMissing(t *testing.T) {
+	withCachedReadmes(t, nil)
+
+	app := NewApp([]*model.Package{model.NewPackage("github.com/owner/bare", "v1.0.0")}, NewMockGitHubClient())
+	app.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	app.Update(cmd())
+
+	view := app.View()
+	if !strings.Contains(view, "No README") {
+		t.Errorf("Expected the README view to say there's no README, got:\n%s", view)
+	}
+	if strings.Contains(view, "Failed to load") {
+		t.Errorf("Expected a missing README not to be shown as a failure, got:\n%s", view)
+	}
+}
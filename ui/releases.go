@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// releasesMsg is sent when the releases of a package have been fetched
+type releasesMsg struct {
+	pkg      *model.Package
+	releases []github.Release
+	err      error
+}
+
+// fetchReleases returns a command that fetches the releases of a package
+func fetchReleases(githubClient github.GitHubClient, pkg *model.Package) tea.Cmd {
+	return func() tea.Msg {
+		releases, err := githubClient.ListReleases(pkg)
+		return releasesMsg{pkg: pkg, releases: releases, err: err}
+	}
+}
+
+// ReleaseNotes represents a scrollable pane with the release notes between
+// the pinned and the latest version of a package
+type ReleaseNotes struct {
+	pkg      *model.Package
+	releases []github.Release
+	err      error
+	loading  bool
+	viewport viewport.Model
+	help     help.Model
+	width    int
+	height   int
+	styles   ReleaseNotesStyles
+	keyMap   ReleaseNotesKeyMap
+}
+
+// ReleaseNotesStyles contains the styles for the release notes pane
+type ReleaseNotesStyles struct {
+	Title   lipgloss.Style
+	Tag     lipgloss.Style
+	Date    lipgloss.Style
+	Body    lipgloss.Style
+	Message lipgloss.Style
+}
+
+// DefaultReleaseNotesStyles returns the default styles for the release notes pane
+func DefaultReleaseNotesStyles() ReleaseNotesStyles {
+	return ReleaseNotesStyles{
+		Title: lipgloss.NewStyle().
+			Foreground(activeTheme.Accent).
+			Bold(true).
+			MarginLeft(2),
+		Tag: lipgloss.NewStyle().
+			Foreground(activeTheme.Highlight).
+			Bold(true),
+		Date: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted),
+		Body: lipgloss.NewStyle().
+			Foreground(activeTheme.Text),
+		Message: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted).
+			Italic(true),
+	}
+}
+
+// ReleaseNotesKeyMap defines the key bindings for the release notes pane
+type ReleaseNotesKeyMap struct {
+	OpenCompare key.Binding
+	Close       key.Binding
+}
+
+// DefaultReleaseNotesKeyMap returns the default key bindings for the release notes pane
+func DefaultReleaseNotesKeyMap() ReleaseNotesKeyMap {
+	return ReleaseNotesKeyMap{
+		OpenCompare: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "open compare view"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc/q", "back"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view.
+func (k ReleaseNotesKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.OpenCompare, k.Close}
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k ReleaseNotesKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.OpenCompare, k.Close}}
+}
+
+// NewReleaseNotes creates a new release notes pane for the package
+func NewReleaseNotes(pkg *model.Package) *ReleaseNotes {
+	r := &ReleaseNotes{
+		pkg:      pkg,
+		loading:  true,
+		viewport: viewport.New(80, 20),
+		help:     help.New(),
+		width:    80,
+		height:   24,
+		styles:   DefaultReleaseNotesStyles(),
+		keyMap:   DefaultReleaseNotesKeyMap(),
+	}
+	r.updateContent()
+	return r
+}
+
+// SetReleases sets the fetched releases, or the error that occurred while fetching them
+func (r *ReleaseNotes) SetReleases(releases []github.Release, err error) {
+	r.releases = releases
+	r.err = err
+	r.loading = false
+	r.updateContent()
+}
+
+// SetSize sets the size of the release notes pane
+func (r *ReleaseNotes) SetSize(width, height int) {
+	r.width = width
+	r.height = height
+	r.help.Width = width
+
+	// Reserve space for the title and help message
+	r.viewport.Width = width
+	r.viewport.Height = height - 4
+	if r.viewport.Height < 1 {
+		r.viewport.Height = 1
+	}
+	r.updateContent()
+}
+
+// Update handles user input and scrolls the release notes
+func (r *ReleaseNotes) Update(msg tea.Msg) (*ReleaseNotes, tea.Cmd) {
+	var cmd tea.Cmd
+	r.viewport, cmd = r.viewport.Update(msg)
+	return r, cmd
+}
+
+// updateContent renders the release notes into the viewport
+func (r *ReleaseNotes) updateContent() {
+	r.viewport.SetContent(r.content())
+}
+
+// content returns the rendered release notes
+func (r *ReleaseNotes) content() string {
+	bodyStyle := r.styles.Body.Width(r.width - 2)
+	messageStyle := r.styles.Message.Width(r.width - 2)
+
+	switch {
+	case r.loading:
+		return messageStyle.Render("Loading releases...")
+	case r.err != nil:
+		return messageStyle.Render("Failed to load releases: " + r.err.Error())
+	case len(r.releases) == 0:
+		message := "No GitHub releases found after " + r.pkg.Version + "."
+		if url := r.pkg.CompareURL(); url != "" {
+			message += "\n\nCompare " + r.pkg.Version + "..." + r.pkg.LatestVersion + ":\n" + url
+		}
+		return messageStyle.Render(message)
+	}
+
+	var sections []string
+	for _, release := range r.releases {
+		header := r.styles.Tag.Render(release.TagName)
+		if release.Name != "" && release.Name != release.TagName {
+			header += " " + release.Name
+		}
+		if release.Prerelease {
+			header += " (pre-release)"
+		}
+		if !release.PublishedAt.IsZero() {
+			header += " " + r.styles.Date.Render(release.PublishedAt.Format("2006-01-02"))
+		}
+
+		body := strings.TrimSpace(strings.ReplaceAll(release.Body, "\r\n", "\n"))
+		if body == "" {
+			body = "(no release notes)"
+		}
+
+		sections = append(sections, header+"\n\n"+bodyStyle.Render(body))
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+// View renders the release notes pane
+func (r *ReleaseNotes) View() string {
+	title := "Release notes: " + r.pkg.Path + " " + r.pkg.Version
+	if r.pkg.Outdated() {
+		title += " → " + r.pkg.LatestVersion
+	}
+
+	return r.styles.Title.Render(title) + "\n\n" + r.viewport.View() + "\n\n" + r.help.View(r.keyMap)
+}
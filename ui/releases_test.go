@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+func TestReleaseNotesView(t *testing.T) {
+	tests := []struct {
+		name     string
+		releases []github.Release
+		err      error
+		loading  bool
+		contains []string
+	}{
+		{
+			name:     "Loading",
+			loading:  true,
+			contains: []string{"Release notes: github.com/charmbracelet/bubbles v0.20.0 → v0.21.0", "Loading releases..."},
+		},
+		{
+			name: "Releases",
+			releases: []github.Release{
+				{TagName: "v0.21.0", Name: "Spring release", Body: "Viewport improvements", PublishedAt: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+				{TagName: "v0.20.1", Body: "", Prerelease: true},
+			},
+			contains: []string{"v0.21.0 Spring release 2025-04-01", "Viewport improvements", "v0.20.1 (pre-release)", "(no release notes)"},
+		},
+		{
+			name:     "No releases falls back to compare view",
+			releases: nil,
+			contains: []string{"No GitHub releases found after v0.20.0.", "https://github.com/charmbracelet/bubbles/compare/v0.20.0...v0.21.0"},
+		},
+		{
+			name:     "Error",
+			err:      errors.New("HTTP 500"),
+			contains: []string{"Failed to load releases: HTTP 500"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+			pkg.LatestVersion = "v0.21.0"
+
+			notes := NewReleaseNotes(pkg)
+			notes.SetSize(120, 40)
+			if !tt.loading {
+				notes.SetReleases(tt.releases, tt.err)
+			}
+
+			result := notes.View()
+			for _, expected := range tt.contains {
+				if !strings.Contains(result, expected) {
+					t.Errorf("Expected view to contain %q, but it didn't.\nGot: %s", expected, result)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/tnagatomi/gh-lsmod/config"
+)
+
+// KeyMaps contains the key bindings of the screens that can be configured
+type KeyMaps struct {
+	List         PackageListKeyMap
+	Dialog       DialogKeyMap
+	Pager        PagerKeyMap // Used by the README view and the file viewer of the source browser
+	Tree         TreeKeyMap
+	Source       SourceKeyMap
+	Details      DetailsKeyMap // Used by the maximized details pane
+	ReleaseNotes ReleaseNotesKeyMap
+	Sponsors     SponsorsKeyMap
+	StarLists    DialogKeyMap
+	BulkStar     DialogKeyMap
+	Actions      DialogKeyMap
+	ErrorLog     ErrorLogKeyMap
+	FilterBar    FilterBarKeyMap
+	CopyMenu     DialogKeyMap
+}
+
+// dialogScreens are the screens shown as dialogs, which follow the dialog bindings except for the actions configured for them
+var dialogScreens = []string{"actions", "bulk_star", "copy_menu", "star_lists"}
+
+// DefaultKeyMaps returns the default key bindings of every screen
+func DefaultKeyMaps() KeyMaps {
+	return KeyMaps{
+		List:         DefaultPackageListKeyMap(),
+		Dialog:       DefaultDialogKeyMap(),
+		Pager:        DefaultPagerKeyMap(),
+		Tree:         DefaultTreeKeyMap(),
+		Source:       DefaultSourceKeyMap(),
+		Details:      DefaultDetailsKeyMap(),
+		ReleaseNotes: DefaultReleaseNotesKeyMap(),
+		Sponsors:     DefaultSponsorsKeyMap(),
+		StarLists:    DefaultDialogKeyMap(),
+		BulkStar:     DefaultDialogKeyMap(),
+		Actions:      DefaultDialogKeyMap(),
+		ErrorLog:     DefaultErrorLogKeyMap(),
+		FilterBar:    DefaultFilterBarKeyMap(),
+		CopyMenu:     DefaultDialogKeyMap(),
+	}
+}
+
+// bindings returns the bindings of each screen by the names of the screen and the action in the config file
+func (k *KeyMaps) bindings() map[string]map[string]*key.Binding {
+	return map[string]map[string]*key.Binding{
+		"list": {
+			"open_github":         &k.List.OpenGitHub,
+			"open_pkg_go_dev":     &k.List.OpenPkgGoDev,
+			"open_compare":        &k.List.OpenCompare,
+			"toggle_star":         &k.List.ToggleStar,
+			"star_all":            &k.List.StarAll,
+			"release_notes":       &k.List.ReleaseNotes,
+			"readme":              &k.List.Readme,
+			"source":              &k.List.Source,
+			"sponsors":            &k.List.Sponsors,
+			"select":              &k.List.Select,
+			"select_all":          &k.List.SelectAll,
+			"invert":              &k.List.Invert,
+			"clear_select":        &k.List.ClearSelect,
+			"actions":             &k.List.Actions,
+			"help":                &k.List.Help,
+			"star_lists":          &k.List.StarLists,
+			"toggle_watch":        &k.List.ToggleWatch,
+			"watch_all":           &k.List.WatchAll,
+			"tree":                &k.List.Tree,
+			"filter":              &k.List.Filter,
+			"clear_filter":        &k.List.ClearFilter,
+			"sort":                &k.List.Sort,
+			"sort_then":           &k.List.SortThen,
+			"reverse":             &k.List.Reverse,
+			"reverse_then":        &k.List.ReverseThen,
+			"copy_url":            &k.List.CopyURL,
+			"copy":                &k.List.Copy,
+			"error_log":           &k.List.ErrorLog,
+			"quit":                &k.List.Quit,
+			"toggle_details":      &k.List.ToggleDetails,
+			"maximize_details":    &k.List.MaximizeDetails,
+			"grow_details":        &k.List.GrowDetails,
+			"shrink_details":      &k.List.ShrinkDetails,
+			"scroll_details_down": &k.List.ScrollDetailsDown,
+			"scroll_details_up":   &k.List.ScrollDetailsUp,
+		},
+		"details": {
+			"up":        &k.Details.Up,
+			"down":      &k.Details.Down,
+			"page_up":   &k.Details.PageUp,
+			"page_down": &k.Details.PageDown,
+			"close":     &k.Details.Close,
+		},
+		"dialog": dialogBindings(&k.Dialog),
+		"pager": {
+			"search":   &k.Pager.Search,
+			"next":     &k.Pager.Next,
+			"previous": &k.Pager.Previous,
+			"top":      &k.Pager.Top,
+			"bottom":   &k.Pager.Bottom,
+			"apply":    &k.Pager.Apply,
+			"cancel":   &k.Pager.Cancel,
+		},
+		"tree": {
+			"up":         &k.Tree.Up,
+			"down":       &k.Tree.Down,
+			"expand":     &k.Tree.Expand,
+			"collapse":   &k.Tree.Collapse,
+			"toggle":     &k.Tree.Toggle,
+			"expand_all": &k.Tree.ExpandAll,
+			"parent":     &k.Tree.Parent,
+			"close":      &k.Tree.Close,
+		},
+		"source": {
+			"up":     &k.Source.Up,
+			"down":   &k.Source.Down,
+			"open":   &k.Source.Open,
+			"back":   &k.Source.Back,
+			"search": &k.Source.Search,
+			"editor": &k.Source.Editor,
+			"github": &k.Source.GitHub,
+			"close":  &k.Source.Close,
+		},
+		"release_notes": {
+			"open_compare": &k.ReleaseNotes.OpenCompare,
+			"close":        &k.ReleaseNotes.Close,
+		},
+		"sponsors": {
+			"up":    &k.Sponsors.Up,
+			"down":  &k.Sponsors.Down,
+			"open":  &k.Sponsors.Open,
+			"close": &k.Sponsors.Close,
+		},
+		"star_lists": dialogBindings(&k.StarLists),
+		"bulk_star":  dialogBindings(&k.BulkStar),
+		"actions":    dialogBindings(&k.Actions),
+		"error_log": {
+			"close": &k.ErrorLog.Close,
+		},
+		"filter_bar": {
+			"apply":  &k.FilterBar.Apply,
+			"cancel": &k.FilterBar.Cancel,
+			"save":   &k.FilterBar.Save,
+		},
+		"copy_menu": dialogBindings(&k.CopyMenu),
+	}
+}
+
+// dialogBindings returns the bindings of a dialog by the names of the actions in the config file
+func dialogBindings(k *DialogKeyMap) map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"confirm":    &k.Confirm,
+		"cancel":     &k.Cancel,
+		"next":       &k.Next,
+		"previous":   &k.Previous,
+		"left":       &k.Left,
+		"right":      &k.Right,
+		"press":      &k.Press,
+		"toggle":     &k.Toggle,
+		"toggle_all": &k.ToggleAll,
+		"up":         &k.Up,
+		"down":       &k.Down,
+		"page_up":    &k.PageUp,
+		"page_down":  &k.PageDown,
+	}
+}
+
+// NewKeyMaps returns the default key bindings with the ones of the config replacing them.
+// A key bound to two actions of a screen is an error, unless the actions share it by default.
+func NewKeyMaps(cfg config.Keys) (KeyMaps, error) {
+	keyMaps := DefaultKeyMaps()
+	bindings := keyMaps.bindings()
+	defaults := DefaultKeyMaps()
+	defaultBindings := defaults.bindings()
+
+	var errs []error
+	for _, screen := range sortedNames(cfg) {
+		actions, ok := bindings[screen]
+		if !ok {
+			errs = append(errs, &config.FieldError{
+				Field:   "keys." + screen,
+				Message: fmt.Sprintf("unknown screen, expected one of %s", strings.Join(sortedNames(bindings), ", ")),
+			})
+			continue
+		}
+		for _, action := range sortedNames(cfg[screen]) {
+			binding, ok := actions[action]
+			if !ok {
+				errs = append(errs, &config.FieldError{
+					Field:   "keys." + screen + "." + action,
+					Message: fmt.Sprintf("unknown action, expected one of %s", strings.Join(sortedNames(actions), ", ")),
+				})
+				continue
+			}
+			keys := cfg[screen][action]
+			binding.SetKeys(keys...)
+			binding.SetHelp(strings.Join(keys, "/"), binding.Help().Desc)
+		}
+
+		// Report the keys of the configured actions that other actions of the screen are bound to
+		for _, action := range sortedNames(cfg[screen]) {
+			if actions[action] == nil {
+				continue
+			}
+			for _, k := range cfg[screen][action] {
+				for _, other := range sortedNames(actions) {
+					if other == action || !slices.Contains(actions[other].Keys(), k) {
+						continue
+					}
+					if sharesKey(defaultBindings[screen][action], defaultBindings[screen][other]) {
+						continue
+					}
+					errs = append(errs, &config.FieldError{
+						Field:   "keys." + screen + "." + action,
+						Message: fmt.Sprintf("%q is already bound to %s", k, other),
+					})
+				}
+			}
+		}
+	}
+
+	// The source browser shows files with the pager
+	keyMaps.Source.Pager = keyMaps.Pager
+
+	// The menus and pickers are dialogs, so they take the dialog bindings of the actions not configured for them
+	for _, screen := range dialogScreens {
+		for action, binding := range bindings[screen] {
+			if _, ok := cfg[screen][action]; !ok {
+				*binding = *bindings["dialog"][action]
+			}
+		}
+	}
+	return keyMaps, errors.Join(errs...)
+}
+
+// sharesKey reports whether the bindings have a key in common
+func sharesKey(a, b *key.Binding) bool {
+	for _, k := range a.Keys() {
+		if slices.Contains(b.Keys(), k) {
+			return true
+		}
+	}
+	return false
+}
+
+// Settings are the customizations of the TUI from the config file
+type Settings struct {
+	Keys        KeyMaps
+	Theme       Theme
+	Description []string // Fields shown below each package in the list
+	Details     []string // Fields shown in the details view
+}
+
+// DefaultSettings returns the settings used without a config file
+func DefaultSettings() *Settings {
+	return &Settings{
+		Keys:        DefaultKeyMaps(),
+		Theme:       DarkTheme(),
+		Description: DefaultDescriptionFields,
+		Details:     DefaultDetailsFields,
+	}
+}
+
+// NewSettings returns the settings of the config, reporting every unknown key action, color and field
+func NewSettings(cfg *config.Config) (*Settings, error) {
+	settings := DefaultSettings()
+	var errs []error
+
+	keys, err := NewKeyMaps(cfg.Keys)
+	settings.Keys = keys
+	errs = append(errs, err)
+
+	theme, err := NewTheme(cfg.Theme)
+	settings.Theme = theme
+	errs = append(errs, err)
+
+	if cfg.List.Description != nil {
+		settings.Description = cfg.List.Description
+		errs = append(errs, checkFields("list.description", cfg.List.Description, sortedNames(descriptionFields)))
+	}
+	if cfg.Details.Fields != nil {
+		settings.Details = cfg.Details.Fields
+		errs = append(errs, checkFields("details.fields", cfg.Details.Fields, sortedNames(detailsFields)))
+	}
+
+	return settings, errors.Join(errs...)
+}
+
+// checkFields reports the unknown and repeated fields
+func checkFields(name string, fields, known []string) error {
+	var errs []error
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		switch {
+		case !slices.Contains(known, field):
+			errs = append(errs, &config.FieldError{
+				Field:   name,
+				Message: fmt.Sprintf("unknown field %q, expected one of %s", field, strings.Join(known, ", ")),
+			})
+		case seen[field]:
+			errs = append(errs, &config.FieldError{Field: name, Message: fmt.Sprintf("field %q is listed twice", field)})
+		}
+		seen[field] = true
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,217 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/config"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+func TestNewKeyMaps(t *testing.T) {
+	keyMaps, err := NewKeyMaps(config.Keys{
+		"list":  {"open_github": {"G"}, "open_pkg_go_dev": {"g"}},
+		"pager": {"next": {"ctrl+s"}},
+	})
+	if err != nil {
+		t.Fatalf("NewKeyMaps() returned an error: %v", err)
+	}
+	if keys := keyMaps.List.OpenGitHub.Keys(); len(keys) != 1 || keys[0] != "G" {
+		t.Errorf("OpenGitHub keys = %v, want [G]", keys)
+	}
+	if help := keyMaps.List.OpenPkgGoDev.Help(); help.Key != "g" || help.Desc != "open pkg.go.dev" {
+		t.Errorf("OpenPkgGoDev help = %+v, want the new key with the same description", help)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlS}, keyMaps.Source.Pager.Next) {
+		t.Error("Expected the pager keys to apply to the source browser")
+	}
+	if keys := keyMaps.List.Quit.Keys(); len(keys) != 2 {
+		t.Errorf("Expected the other keys to keep their defaults, got Quit keys %v", keys)
+	}
+
+	tests := []struct {
+		name     string
+		keys     config.Keys
+		expected []string
+	}{
+		{
+			name:     "Unknown screen",
+			keys:     config.Keys{"lsit": {"quit": {"x"}}},
+			expected: []string{"keys.lsit: unknown screen, expected one of actions, bulk_star, copy_menu, details, dialog, error_log, filter_bar, list, pager, release_notes, source, sponsors, star_lists, tree"},
+		},
+		{
+			name:     "Unknown action",
+			keys:     config.Keys{"dialog": {"confrim": {"y"}}},
+			expected: []string{"keys.dialog.confrim: unknown action, expected one of cancel, confirm"},
+		},
+		{
+			name:     "Key bound to another action",
+			keys:     config.Keys{"list": {"sort": {"g"}}},
+			expected: []string{`keys.list.sort: "g" is already bound to open_github`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyMaps(tt.keys)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("Expected the error to contain %q, got:\n%v", expected, err)
+				}
+			}
+		})
+	}
+
+	// The copy menu follows the dialog bindings unless it's configured itself
+	keyMaps, err = NewKeyMaps(config.Keys{
+		"dialog":    {"cancel": {"n"}, "confirm": {"y"}},
+		"copy_menu": {"confirm": {"c"}},
+	})
+	if err != nil {
+		t.Fatalf("NewKeyMaps() returned an error: %v", err)
+	}
+	if keys := keyMaps.CopyMenu.Cancel.Keys(); len(keys) != 1 || keys[0] != "n" {
+		t.Errorf("CopyMenu.Cancel keys = %v, want the dialog's [n]", keys)
+	}
+	if keys := keyMaps.CopyMenu.Confirm.Keys(); len(keys) != 1 || keys[0] != "c" {
+		t.Errorf("CopyMenu.Confirm keys = %v, want [c]", keys)
+	}
+
+	// Actions sharing a key by default can keep sharing it
+	if _, err := NewKeyMaps(config.Keys{"list": {"clear_filter": {"esc"}}}); err != nil {
+		t.Errorf("NewKeyMaps() returned an error for a key shared by default: %v", err)
+	}
+}
+
+func TestNewSettings(t *testing.T) {
+	settings, err := NewSettings(&config.Config{
+		Theme: config.Theme{Name: config.ThemeLight, Colors: map[string]string{"accent": "#ff8800"}},
+		List:  config.List{Description: []string{"version", "stars"}},
+	})
+	if err != nil {
+		t.Fatalf("NewSettings() returned an error: %v", err)
+	}
+	if settings.Theme.Accent != lipgloss.Color("#ff8800") || settings.Theme.Text != LightTheme().Text {
+		t.Errorf("Theme = %+v, want the light theme with the accent overridden", settings.Theme)
+	}
+	if strings.Join(settings.Description, ",") != "version,stars" {
+		t.Errorf("Description = %v, want [version stars]", settings.Description)
+	}
+	if strings.Join(settings.Details, ",") != strings.Join(DefaultDetailsFields, ",") {
+		t.Errorf("Details = %v, want the default fields", settings.Details)
+	}
+
+	_, err = NewSettings(&config.Config{
+		Keys:    config.Keys{"list": {"qiut": {"x"}}},
+		Theme:   config.Theme{Colors: map[string]string{"acent": "99"}},
+		List:    config.List{Description: []string{"size", "size"}},
+		Details: config.Details{Fields: []string{"license"}},
+	})
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	for _, expected := range []string{
+		"keys.list.qiut: unknown action",
+		"theme.colors.acent: unknown color, expected one of accent, builtin",
+		`list.description: field "size" is listed twice`,
+		`details.fields: unknown field "license", expected one of activity, compare, description`,
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected the error to contain %q, got:\n%v", expected, err)
+		}
+	}
+}
+
+func TestAppSettings(t *testing.T) {
+	t.Cleanup(func() {
+		SetTheme(DarkTheme())
+	})
+	settings, err := NewSettings(&config.Config{
+		Keys:    config.Keys{"list": {"quit": {"Q"}, "help": {"h"}}},
+		List:    config.List{Description: []string{"version", "size"}},
+		Details: config.Details{Fields: []string{"name", "size"}},
+	})
+	if err != nil {
+		t.Fatalf("NewSettings() returned an error: %v", err)
+	}
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	pkg.Size = 1024
+	app := NewAppWithOptions([]*model.Package{pkg}, NewMockGitHubClient(), Options{Settings: settings})
+	app.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+
+	view := app.View()
+	for _, expected := range []string{"v0.20.0 (1.00 KB)", "Name: github.com/charmbracelet/bubbles", "Size: 1.00 KB", "Q quit", "h more keys"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected the view to contain %q, got:\n%s", expected, view)
+		}
+	}
+	for _, unexpected := range []string{"[GitHub]", "pkg.go.dev: "} {
+		if strings.Contains(view, unexpected) {
+			t.Errorf("Expected the view not to contain %q, got:\n%s", unexpected, view)
+		}
+	}
+
+	// The default key no longer quits
+	if _, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd != nil {
+		if _, ok := cmd().(tea.QuitMsg); ok {
+			t.Error("Expected q not to quit")
+		}
+	}
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Q")})
+	if cmd == nil {
+		t.Fatal("Expected Q to quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Expected Q to quit")
+	}
+}
+
+func TestAppSettingsScreens(t *testing.T) {
+	settings, err := NewSettings(&config.Config{
+		Keys: config.Keys{"actions": {"press": {"r"}}, "error_log": {"close": {"b"}}},
+	})
+	if err != nil {
+		t.Fatalf("NewSettings() returned an error: %v", err)
+	}
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	app := NewAppWithOptions([]*model.Package{pkg}, NewMockGitHubClient(), Options{Settings: settings})
+	app.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+
+	// The action menu runs with the new key only, and keeps the other dialog keys
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if app.modal == nil {
+		t.Fatal("Expected the action menu to be open")
+	}
+	if view := app.View(); !strings.Contains(view, "r select") {
+		t.Errorf("Expected the help to show the new key, got:\n%s", view)
+	}
+	if _, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || app.modal == nil {
+		t.Error("Expected enter not to run the action")
+	}
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	_, cmd = app.Update(cmd())
+	finishGitHubAction(t, app, cmd)
+	if !pkg.IsStarred {
+		t.Error("Expected r to star the package")
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	// The error log closes with the new key only
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("!")})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if app.state != StateErrorLog {
+		t.Fatalf("Expected q not to close the error log, got state %v", app.state)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	if app.state != StateList {
+		t.Errorf("Expected b to close the error log, got state %v", app.state)
+	}
+}
@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// SortKey is a property the package list can be sorted by
+type SortKey int
+
+const (
+	SortNone SortKey = iota // Order of the go.mod file
+	SortName
+	SortSize
+	SortVersionDate
+	SortVersion
+	SortStars
+	SortLastPush
+	SortStarred
+)
+
+// sortKeys lists the sort keys in the order they're cycled through
+var sortKeys = []SortKey{SortNone, SortName, SortSize, SortVersionDate, SortVersion, SortStars, SortLastPush, SortStarred}
+
+// String returns the name of the sort key shown in the title
+func (k SortKey) String() string {
+	switch k {
+	case SortName:
+		return "name"
+	case SortSize:
+		return "size"
+	case SortVersionDate:
+		return "version date"
+	case SortVersion:
+		return "version"
+	case SortStars:
+		return "stars"
+	case SortLastPush:
+		return "last push"
+	case SortStarred:
+		return "starred"
+	}
+	return "go.mod order"
+}
+
+// defaultDescending reports whether the key sorts descending by default:
+// biggest, most starred and starred first, but oldest and least mature versions and stalest repositories first
+func (k SortKey) defaultDescending() bool {
+	switch k {
+	case SortSize, SortStars, SortStarred:
+		return true
+	}
+	return false
+}
+
+// compare compares the packages by the key. Packages whose value is unknown are reported as such,
+// so that they're sorted last in either direction.
+func (k SortKey) compare(a, b *model.Package) (result int, aKnown, bKnown bool) {
+	switch k {
+	case SortName:
+		return strings.Compare(a.Path, b.Path), true, true
+	case SortSize:
+		return compareInts(a.Size, b.Size), a.Size != 0, b.Size != 0
+	case SortVersionDate:
+		aDate, bDate := a.VersionDate(), b.VersionDate()
+		return aDate.Compare(bDate), !aDate.IsZero(), !bDate.IsZero()
+	case SortVersion:
+		// Pseudo-versions and v0 modules come before stable releases
+		aVersion, bVersion := a.ParsedVersion(), b.ParsedVersion()
+		return aVersion.Compare(bVersion), aVersion.Valid, bVersion.Valid
+	case SortStars:
+		var aStars, bStars int64
+		if a.Repo != nil {
+			aStars = int64(a.Repo.Stars)
+		}
+		if b.Repo != nil {
+			bStars = int64(b.Repo.Stars)
+		}
+		return compareInts(aStars, bStars), a.Repo != nil, b.Repo != nil
+	case SortLastPush:
+		var aPush, bPush time.Time
+		if a.Repo != nil {
+			aPush = a.Repo.PushedAt
+		}
+		if b.Repo != nil {
+			bPush = b.Repo.PushedAt
+		}
+		return aPush.Compare(bPush), !aPush.IsZero(), !bPush.IsZero()
+	case SortStarred:
+		// Only GitHub repositories can be starred
+		return compareBools(a.IsStarred, b.IsStarred), a.IsGitHub, b.IsGitHub
+	}
+	return 0, true, true
+}
+
+// compareInts compares two integers
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareBools compares two booleans, false before true
+func compareBools(a, b bool) int {
+	switch {
+	case !a && b:
+		return -1
+	case a && !b:
+		return 1
+	}
+	return 0
+}
+
+// SortOrder sorts packages by a primary key and breaks ties with a secondary key
+type SortOrder struct {
+	Key           SortKey
+	Descending    bool
+	Secondary     SortKey
+	SecondaryDesc bool
+}
+
+// String describes the sort order for the title
+func (o SortOrder) String() string {
+	if o.Key == SortNone {
+		return ""
+	}
+	s := "by " + o.Key.String() + directionArrow(o.Descending)
+	if o.Secondary != SortNone {
+		s += ", " + o.Secondary.String() + directionArrow(o.SecondaryDesc)
+	}
+	return s
+}
+
+// directionArrow returns the arrow showing the sort direction
+func directionArrow(descending bool) string {
+	if descending {
+		return " ↓"
+	}
+	return " ↑"
+}
+
+// Sort sorts the packages in place. Packages that compare equal keep their order.
+func (o SortOrder) Sort(packages []*model.Package) {
+	if o.Key == SortNone {
+		return
+	}
+	sort.SliceStable(packages, func(i, j int) bool {
+		if c := compareBy(o.Key, o.Descending, packages[i], packages[j]); c != 0 {
+			return c < 0
+		}
+		return compareBy(o.Secondary, o.SecondaryDesc, packages[i], packages[j]) < 0
+	})
+}
+
+// compareBy compares the packages by the key in the given direction, with unknown values last
+func compareBy(key SortKey, descending bool, a, b *model.Package) int {
+	result, aKnown, bKnown := key.compare(a, b)
+	switch {
+	case aKnown && !bKnown:
+		return -1
+	case !aKnown && bKnown:
+		return 1
+	case !aKnown && !bKnown:
+		return 0
+	}
+	if descending {
+		return -result
+	}
+	return result
+}
+
+// NextKey sorts by the next key, in its default direction
+func (o SortOrder) NextKey() SortOrder {
+	o.Key = nextSortKey(o.Key, SortNone)
+	o.Descending = o.Key.defaultDescending()
+	if o.Key == o.Secondary || o.Key == SortNone {
+		o.Secondary = SortNone
+		o.SecondaryDesc = false
+	}
+	return o
+}
+
+// NextSecondary breaks ties with the next key other than the primary one, in its default direction
+func (o SortOrder) NextSecondary() SortOrder {
+	if o.Key == SortNone {
+		return o
+	}
+	o.Secondary = nextSortKey(o.Secondary, o.Key)
+	o.SecondaryDesc = o.Secondary.defaultDescending()
+	return o
+}
+
+// Reverse reverses the direction of the primary key
+func (o SortOrder) Reverse() SortOrder {
+	if o.Key != SortNone {
+		o.Descending = !o.Descending
+	}
+	return o
+}
+
+// ReverseSecondary reverses the direction of the secondary key
+func (o SortOrder) ReverseSecondary() SortOrder {
+	if o.Secondary != SortNone {
+		o.SecondaryDesc = !o.SecondaryDesc
+	}
+	return o
+}
+
+// nextSortKey returns the key after the given one, skipping a key that's already in use
+func nextSortKey(key, skip SortKey) SortKey {
+	for i, k := range sortKeys {
+		if k != key {
+			continue
+		}
+		next := sortKeys[(i+1)%len(sortKeys)]
+		if next == skip && next != SortNone {
+			next = sortKeys[(i+2)%len(sortKeys)]
+		}
+		return next
+	}
+	return SortNone
+}
@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// sortTestPackages returns packages with differing values for every sort key
+func sortTestPackages() []*model.Package {
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	bubbles.Size = 2 << 20
+	bubbles.VersionTime = time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	bubbles.IsStarred = true
+	bubbles.Repo = &model.RepoMetadata{Stars: 6000, PushedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}
+
+	lipgloss := model.NewPackage("github.com/charmbracelet/lipgloss", "v1.0.0")
+	lipgloss.Size = 2 << 20
+	lipgloss.VersionTime = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	lipgloss.Repo = &model.RepoMetadata{Stars: 9000, PushedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	mod := model.NewPackage("golang.org/x/mod", "v0.27.0")
+	mod.Size = 5 << 20
+	mod.VersionTime = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Nothing is known about this package yet, so it always sorts last
+	unknown := model.NewPackage("github.com/owner/unknown", "v1.0.0")
+
+	return []*model.Package{bubbles, lipgloss, mod, unknown}
+}
+
+func TestSortOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    SortOrder
+		expected []string
+	}{
+		{
+			name:     "go.mod order",
+			order:    SortOrder{},
+			expected: []string{"github.com/charmbracelet/bubbles", "github.com/charmbracelet/lipgloss", "golang.org/x/mod", "github.com/owner/unknown"},
+		},
+		{
+			name:     "name",
+			order:    SortOrder{Key: SortName},
+			expected: []string{"github.com/charmbracelet/bubbles", "github.com/charmbracelet/lipgloss", "github.com/owner/unknown", "golang.org/x/mod"},
+		},
+		{
+			name:     "biggest first, ties kept in go.mod order",
+			order:    SortOrder{Key: SortSize, Descending: true},
+			expected: []string{"golang.org/x/mod", "github.com/charmbracelet/bubbles", "github.com/charmbracelet/lipgloss", "github.com/owner/unknown"},
+		},
+		{
+			name:     "biggest first, ties broken by stars",
+			order:    SortOrder{Key: SortSize, Descending: true, Secondary: SortStars, SecondaryDesc: true},
+			expected: []string{"golang.org/x/mod", "github.com/charmbracelet/lipgloss", "github.com/charmbracelet/bubbles", "github.com/owner/unknown"},
+		},
+		{
+			name:     "biggest first, ties broken by fewest stars",
+			order:    SortOrder{Key: SortSize, Descending: true, Secondary: SortStars},
+			expected: []string{"golang.org/x/mod", "github.com/charmbracelet/bubbles", "github.com/charmbracelet/lipgloss", "github.com/owner/unknown"},
+		},
+		{
+			name:     "smallest first keeps unknown sizes last",
+			order:    SortOrder{Key: SortSize},
+			expected: []string{"github.com/charmbracelet/bubbles", "github.com/charmbracelet/lipgloss", "golang.org/x/mod", "github.com/owner/unknown"},
+		},
+		{
+			name:     "oldest version first",
+			order:    SortOrder{Key: SortVersionDate},
+			expected: []string{"golang.org/x/mod", "github.com/charmbracelet/bubbles", "github.com/charmbracelet/lipgloss", "github.com/owner/unknown"},
+		},
+		{
+			name:     "least mature version first",
+			order:    SortOrder{Key: SortVersion},
+			expected: []string{"github.com/charmbracelet/bubbles", "golang.org/x/mod", "github.com/charmbracelet/lipgloss", "github.com/owner/unknown"},
+		},
+		{
+			name:     "most stars first",
+			order:    SortOrder{Key: SortStars, Descending: true},
+			expected: []string{"github.com/charmbracelet/lipgloss", "github.com/charmbracelet/bubbles", "golang.org/x/mod", "github.com/owner/unknown"},
+		},
+		{
+			name:     "stalest repository first",
+			order:    SortOrder{Key: SortLastPush},
+			expected: []string{"github.com/charmbracelet/lipgloss", "github.com/charmbracelet/bubbles", "golang.org/x/mod", "github.com/owner/unknown"},
+		},
+		{
+			name:     "starred first",
+			order:    SortOrder{Key: SortStarred, Descending: true},
+			expected: []string{"github.com/charmbracelet/bubbles", "github.com/charmbracelet/lipgloss", "github.com/owner/unknown", "golang.org/x/mod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packages := sortTestPackages()
+			tt.order.Sort(packages)
+
+			var got []string
+			for _, pkg := range packages {
+				got = append(got, pkg.Path)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Sort() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSortOrderCycling(t *testing.T) {
+	order := SortOrder{}.NextKey()
+	if order.Key != SortName || order.Descending {
+		t.Errorf("NextKey() = %+v, want name ascending", order)
+	}
+
+	order = order.NextKey()
+	if order.Key != SortSize || !order.Descending {
+		t.Errorf("NextKey() = %+v, want size descending", order)
+	}
+
+	// The secondary key skips the primary one
+	order = order.NextSecondary().NextSecondary()
+	if order.Secondary != SortVersionDate {
+		t.Errorf("NextSecondary() twice = %+v, want version date", order)
+	}
+	if got := order.Reverse().String(); got != "by size ↑, version date ↑" {
+		t.Errorf("String() = %q", got)
+	}
+
+	// The secondary key is reversed on its own
+	order = order.NextSecondary()
+	if order.Secondary != SortVersion || order.SecondaryDesc {
+		t.Errorf("NextSecondary() = %+v, want version ascending", order)
+	}
+	if got := order.ReverseSecondary().String(); got != "by size ↓, version ↓" {
+		t.Errorf("String() = %q", got)
+	}
+
+	// Cycling past the last key goes back to go.mod order
+	for range sortKeys[2:] {
+		order = order.NextKey()
+	}
+	if order.Key != SortNone || order.Secondary != SortNone || order.String() != "" {
+		t.Errorf("Expected go.mod order after cycling through every key, got %+v", order)
+	}
+}
+
+func TestAppSort(t *testing.T) {
+	packages := sortTestPackages()
+	app := NewApp(packages, NewMockGitHubClient())
+
+	// Sort by size, biggest first
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if want := "Go Module Browser · by size ↓"; app.list.list.Title != want {
+		t.Errorf("Title = %q, want %q", app.list.list.Title, want)
+	}
+	// The cursor stays on the package it was on
+	if got := app.list.SelectedPackage(); got != packages[0] {
+		t.Errorf("SelectedPackage() = %v, want %s", got, packages[0].Path)
+	}
+
+	// Items and packages stay consistent when moving the cursor
+	app.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := app.list.SelectedPackage(); got != packages[2] {
+		t.Errorf("SelectedPackage() = %v, want the biggest package %s", got, packages[2].Path)
+	}
+	if app.details.pkg != packages[2] {
+		t.Errorf("Expected the details to show %s, got %v", packages[2].Path, app.details.pkg)
+	}
+
+	// The cursor follows the package when the order is reversed
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if got := app.list.SelectedPackage(); got != packages[2] {
+		t.Errorf("SelectedPackage() after reversing = %v, want %s", got, packages[2].Path)
+	}
+	if got := app.list.list.Index(); got != 2 {
+		t.Errorf("Index() after reversing = %d, want 2", got)
+	}
+	if want := "Go Module Browser · by size ↑"; app.list.list.Title != want {
+		t.Errorf("Title = %q, want %q", app.list.list.Title, want)
+	}
+
+	// The secondary key has its own direction
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("O")})
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if want := "Go Module Browser · by size ↑, name ↓"; app.list.list.Title != want {
+		t.Errorf("Title = %q, want %q", app.list.list.Title, want)
+	}
+
+	// The go.mod order isn't changed by sorting
+	if app.packages[0] != packages[0] || app.packages[2] != packages[2] {
+		t.Error("Expected the packages to stay in go.mod order")
+	}
+}
@@ -0,0 +1,820 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/modcache"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// Limits of the source browser, so that huge or generated files don't stall it
+const (
+	MaxSourceFileSize = 2 << 20 // Files bigger than this aren't shown or searched
+	MaxSourceMatches  = 500     // Module searches stop after this many matches
+)
+
+// execProcess runs a program in the terminal, suspending the TUI until it exits
+var execProcess = tea.ExecProcess
+
+// editorFinishedMsg is sent when the editor opened from the source browser exits
+type editorFinishedMsg struct {
+	err error
+}
+
+// editorLineArgs are the editors known to jump to a line given as +N before the file
+var editorLineArgs = map[string]bool{
+	"vi": true, "vim": true, "nvim": true, "nano": true, "emacs": true, "emacsclient": true,
+	"micro": true, "kak": true, "ne": true, "joe": true, "mg": true,
+}
+
+// editorCommand returns the command opening the file at the line in $VISUAL or $EDITOR, vi by default
+func editorCommand(path string, line int) (*exec.Cmd, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	args := strings.Fields(editor)
+	name := strings.TrimSuffix(filepath.Base(args[0]), ".exe")
+	switch {
+	case line > 0 && editorLineArgs[name]:
+		args = append(args, "+"+strconv.Itoa(line), path)
+	case line > 0 && (name == "code" || name == "codium" || name == "code-insiders"):
+		args = append(args, "--goto", path+":"+strconv.Itoa(line))
+	case line > 0 && (name == "subl" || name == "hx" || name == "zed"):
+		args = append(args, path+":"+strconv.Itoa(line))
+	default:
+		args = append(args, path)
+	}
+
+	bin, err := exec.LookPath(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the editor %s: %w", args[0], err)
+	}
+	return exec.Command(bin, args[1:]...), nil
+}
+
+// openInEditor returns a command opening the file at the line in the editor
+func openInEditor(path string, line int) tea.Cmd {
+	cmd, err := editorCommand(path, line)
+	if err != nil {
+		return func() tea.Msg { return editorFinishedMsg{err: err} }
+	}
+	return execProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			err = fmt.Errorf("failed to run the editor: %w", err)
+		}
+		return editorFinishedMsg{err: err}
+	})
+}
+
+// SourceMatch is a line of the module matching a search
+type SourceMatch struct {
+	Path string // Relative to the module root, with slashes
+	Line int    // Starting at 1
+	Text string
+}
+
+// sourceSearchMsg is sent when a module search has finished
+type sourceSearchMsg struct {
+	term      string
+	matches   []SourceMatch
+	truncated bool
+	err       error
+}
+
+// searchModule returns a command that searches the text files of the module for the term, case-insensitively
+func searchModule(root, term string) tea.Cmd {
+	return func() tea.Msg {
+		matches, truncated, err := SearchSource(root, term)
+		return sourceSearchMsg{term: term, matches: matches, truncated: truncated, err: err}
+	}
+}
+
+// SearchSource searches the text files under root for the term, case-insensitively.
+// Binary files and files bigger than MaxSourceFileSize are skipped. It stops after MaxSourceMatches matches.
+func SearchSource(root, term string) ([]SourceMatch, bool, error) {
+	term = strings.ToLower(term)
+	var matches []SourceMatch
+	truncated := false
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > MaxSourceFileSize {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil || isBinary(data) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, MaxSourceFileSize)
+		for line := 1; scanner.Scan(); line++ {
+			if !strings.Contains(strings.ToLower(scanner.Text()), term) {
+				continue
+			}
+			if len(matches) == MaxSourceMatches {
+				truncated = true
+				return filepath.SkipAll
+			}
+			matches = append(matches, SourceMatch{Path: filepath.ToSlash(rel), Line: line, Text: strings.TrimSpace(scanner.Text())})
+		}
+		return nil
+	})
+	return matches, truncated, err
+}
+
+// isBinary reports whether the data looks binary, having a NUL byte near its start
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0
+}
+
+// sourceFile is a file or directory of the module in the source browser
+type sourceFile struct {
+	name     string
+	path     string // Relative to the module root, with slashes
+	dir      bool
+	parent   *sourceFile
+	children []*sourceFile
+	expanded bool
+	loaded   bool
+	depth    int
+}
+
+// sourceMode is what the source browser is showing
+type sourceMode int
+
+const (
+	sourceModeTree    sourceMode = iota // Directory tree
+	sourceModeFile                      // Content of a file
+	sourceModeResults                   // Matches of a module search
+)
+
+// SourceBrowser represents a file browser of a module as extracted in the module cache
+type SourceBrowser struct {
+	pkg       *model.Package
+	root      string // Directory of the module in the module cache
+	err       error  // Why the module can't be browsed
+	tree      *sourceFile
+	nodes     []*sourceFile // Visible files and directories
+	cursor    int
+	offset    int
+	mode      sourceMode
+	file      *sourceFile // File being viewed
+	fileErr   error       // Why the file can't be shown
+	pager     *Pager
+	input     textinput.Model
+	typing    bool // Whether the module search term is being entered
+	searching bool // Whether a module search is running
+	term      string
+	matches   []SourceMatch
+	truncated bool
+	matchIdx  int
+	matchOff  int
+	fromMatch bool // Whether the file was opened from the search results
+	help      help.Model
+	width     int
+	height    int
+	styles    SourceStyles
+	keyMap    SourceKeyMap
+}
+
+// SourceStyles contains the styles for the source browser
+type SourceStyles struct {
+	Title    lipgloss.Style
+	Dir      lipgloss.Style
+	File     lipgloss.Style
+	Selected lipgloss.Style
+	Location lipgloss.Style
+	Message  lipgloss.Style
+	Prompt   lipgloss.Style
+	Syntax   SyntaxStyles
+}
+
+// DefaultSourceStyles returns the default styles for the source browser
+func DefaultSourceStyles() SourceStyles {
+	return SourceStyles{
+		Title: lipgloss.NewStyle().
+			Foreground(activeTheme.Accent).
+			Bold(true).
+			MarginLeft(2),
+		Dir: lipgloss.NewStyle().
+			Foreground(activeTheme.Link).
+			Bold(true),
+		File: lipgloss.NewStyle().
+			Foreground(activeTheme.Text),
+		Selected: lipgloss.NewStyle().
+			Foreground(activeTheme.Highlight).
+			Bold(true),
+		Location: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted),
+		Message: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted).
+			Italic(true).
+			MarginLeft(2),
+		Prompt: lipgloss.NewStyle().
+			Foreground(activeTheme.Accent).
+			Bold(true),
+		Syntax: DefaultSyntaxStyles(),
+	}
+}
+
+// SourceKeyMap defines the key bindings for the source browser
+type SourceKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Open   key.Binding
+	Back   key.Binding
+	Search key.Binding
+	Editor key.Binding
+	GitHub key.Binding
+	Close  key.Binding
+	Pager  PagerKeyMap
+}
+
+// DefaultSourceKeyMap returns the default key bindings for the source browser
+func DefaultSourceKeyMap() SourceKeyMap {
+	return SourceKeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Open: key.NewBinding(
+			key.WithKeys("enter", "right", "l"),
+			key.WithHelp("enter", "open"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "collapse"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search module"),
+		),
+		Editor: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "open in $EDITOR"),
+		),
+		GitHub: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "open on GitHub"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc/q", "back"),
+		),
+		Pager: DefaultPagerKeyMap(),
+	}
+}
+
+// sourceHelp is the key map shown in the help of each mode of the source browser
+type sourceHelp struct {
+	bindings []key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view.
+func (h sourceHelp) ShortHelp() []key.Binding {
+	return h.bindings
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (h sourceHelp) FullHelp() [][]key.Binding {
+	return [][]key.Binding{h.bindings}
+}
+
+// NewSourceBrowser creates a new source browser for the module of the package at its pinned version
+func NewSourceBrowser(pkg *model.Package) *SourceBrowser {
+	input := textinput.New()
+	input.Prompt = ""
+
+	b := &SourceBrowser{
+		pkg:    pkg,
+		pager:  NewPager(),
+		input:  input,
+		help:   help.New(),
+		width:  80,
+		height: 24,
+		styles: DefaultSourceStyles(),
+		keyMap: DefaultSourceKeyMap(),
+	}
+	b.pager.keyMap = b.keyMap.Pager
+	b.keyMap.GitHub.SetEnabled(pkg.IsGitHub)
+
+	// The module has to be downloaded to be browsed, as for its size
+	b.root, b.err = modcache.PackageDir(pkg.Path, pkg.Version)
+	if b.err == nil {
+		if _, err := os.Stat(b.root); errors.Is(err, fs.ErrNotExist) {
+			b.err = fmt.Errorf("%s@%s isn't in the module cache, run go mod download", pkg.Path, pkg.Version)
+		} else if err != nil {
+			b.err = fmt.Errorf("failed to open the module directory: %w", err)
+		}
+	}
+	if b.err == nil {
+		b.tree = &sourceFile{name: pkg.Path + "@" + pkg.Version, dir: true, depth: -1}
+		b.expand(b.tree)
+		b.refresh()
+	}
+	return b
+}
+
+// SetKeyMap sets the key bindings of the source browser, including those of the file viewer
+func (b *SourceBrowser) SetKeyMap(keyMap SourceKeyMap) {
+	keyMap.GitHub.SetEnabled(b.pkg.IsGitHub)
+	b.keyMap = keyMap
+	b.pager.keyMap = keyMap.Pager
+}
+
+// SetSize sets the size of the source browser
+func (b *SourceBrowser) SetSize(width, height int) {
+	b.width = width
+	b.height = height
+	b.help.Width = width
+	b.input.Width = width - 10
+
+	// Reserve space for the title and help message
+	b.pager.SetSize(width, height-4)
+	b.scrollToCursor()
+}
+
+// Closable reports whether closing goes back to the list, rather than to the directory tree
+func (b *SourceBrowser) Closable() bool {
+	return b.mode == sourceModeTree && !b.typing
+}
+
+// bodyHeight returns the number of lines available below the title
+func (b *SourceBrowser) bodyHeight() int {
+	return max(b.height-4, 1)
+}
+
+// expand reads the directory on first use and shows its entries, directories first
+func (b *SourceBrowser) expand(dir *sourceFile) {
+	if !dir.loaded {
+		dir.loaded = true
+		entries, err := os.ReadDir(filepath.Join(b.root, filepath.FromSlash(dir.path)))
+		if err != nil {
+			b.err = fmt.Errorf("failed to read %s: %w", dir.path, err)
+			return
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].IsDir() && !entries[j].IsDir()
+		})
+		for _, entry := range entries {
+			dir.children = append(dir.children, &sourceFile{
+				name:   entry.Name(),
+				path:   strings.TrimPrefix(dir.path+"/"+entry.Name(), "/"),
+				dir:    entry.IsDir(),
+				parent: dir,
+				depth:  dir.depth + 1,
+			})
+		}
+	}
+	dir.expanded = true
+}
+
+// refresh updates the visible files after expanding or collapsing, keeping the cursor on the same file
+func (b *SourceBrowser) refresh() {
+	var selected *sourceFile
+	if b.cursor < len(b.nodes) {
+		selected = b.nodes[b.cursor]
+	}
+
+	b.nodes = nil
+	var walk func(dir *sourceFile)
+	walk = func(dir *sourceFile) {
+		for _, child := range dir.children {
+			b.nodes = append(b.nodes, child)
+			if child.expanded {
+				walk(child)
+			}
+		}
+	}
+	walk(b.tree)
+
+	b.cursor = min(b.cursor, max(len(b.nodes)-1, 0))
+	for i, node := range b.nodes {
+		if node == selected {
+			b.cursor = i
+		}
+	}
+	b.scrollToCursor()
+}
+
+// scrollToCursor adjusts the scroll offsets so that the selected file and match are visible
+func (b *SourceBrowser) scrollToCursor() {
+	b.offset = scrollOffset(b.cursor, b.offset, len(b.nodes), b.bodyHeight())
+	b.matchOff = scrollOffset(b.matchIdx, b.matchOff, len(b.matches), b.bodyHeight())
+}
+
+// scrollOffset returns the scroll offset keeping the cursor in a view of the given height
+func scrollOffset(cursor, offset, total, height int) int {
+	if cursor < offset {
+		offset = cursor
+	}
+	if cursor >= offset+height {
+		offset = cursor - height + 1
+	}
+	return max(min(offset, total-height), 0)
+}
+
+// selected returns the file or directory under the cursor, or nil if there's none
+func (b *SourceBrowser) selected() *sourceFile {
+	if b.cursor < 0 || b.cursor >= len(b.nodes) {
+		return nil
+	}
+	return b.nodes[b.cursor]
+}
+
+// openFile shows the file, at the line if it's not 0, highlighting Go code
+func (b *SourceBrowser) openFile(file *sourceFile, line int) {
+	b.file = file
+	b.fileErr = nil
+	b.mode = sourceModeFile
+
+	data, err := b.readFile(file.path)
+	switch {
+	case err != nil:
+		b.fileErr = err
+		b.pager.SetContent(b.styles.Message.Render(err.Error()))
+		return
+	case strings.HasSuffix(file.name, ".go"):
+		b.pager.SetContent(strings.Join(WithLineNumbers(HighlightGo(string(data), b.styles.Syntax), b.styles.Syntax.LineNumber), "\n"))
+	default:
+		lines := strings.Split(expandTabs(string(data)), "\n")
+		b.pager.SetContent(strings.Join(WithLineNumbers(lines, b.styles.Syntax.LineNumber), "\n"))
+	}
+
+	b.pager.viewport.GotoTop()
+	if line > 0 {
+		b.pager.GotoLine(line - 1)
+	}
+}
+
+// readFile reads a text file of the module
+func (b *SourceBrowser) readFile(path string) ([]byte, error) {
+	full := filepath.Join(b.root, filepath.FromSlash(path))
+	info, err := os.Stat(full)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if info.Size() > MaxSourceFileSize {
+		return nil, fmt.Errorf("%s is too big to be shown (%s)", path, model.FormatSize(info.Size()))
+	}
+	data, err := os.ReadFile(full)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if isBinary(data) {
+		return nil, fmt.Errorf("%s is a binary file", path)
+	}
+	return data, nil
+}
+
+// findFile returns the file at the path, expanding the directories leading to it
+func (b *SourceBrowser) findFile(path string) *sourceFile {
+	dir := b.tree
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		b.expand(dir)
+		var next *sourceFile
+		for _, child := range dir.children {
+			if child.name == part {
+				next = child
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		if i == len(parts)-1 {
+			b.refresh()
+			return next
+		}
+		dir = next
+	}
+	return nil
+}
+
+// target returns the path of the file and the line an action applies to.
+// In the directory tree, it's the selected file or directory; in a file, the line of the current match or at the top.
+func (b *SourceBrowser) target() (string, int) {
+	switch b.mode {
+	case sourceModeFile:
+		return b.file.path, b.pager.CurrentLine() + 1
+	case sourceModeResults:
+		if b.matchIdx < len(b.matches) {
+			return b.matches[b.matchIdx].Path, b.matches[b.matchIdx].Line
+		}
+	default:
+		if node := b.selected(); node != nil {
+			return node.path, 0
+		}
+	}
+	return "", 0
+}
+
+// EditorTarget returns the absolute path of the file and the line to open in the editor.
+// Directories are opened as they are.
+func (b *SourceBrowser) EditorTarget() (string, int) {
+	path, line := b.target()
+	return filepath.Join(b.root, filepath.FromSlash(path)), line
+}
+
+// GitHubURL returns the GitHub URL of the file and line at the pinned version
+func (b *SourceBrowser) GitHubURL() string {
+	path, line := b.target()
+	return b.pkg.GitHubBlobURL(path, line)
+}
+
+// SetMatches sets the results of a module search
+func (b *SourceBrowser) SetMatches(msg sourceSearchMsg) {
+	if msg.term != b.term {
+		return // A newer search has started
+	}
+	b.searching = false
+	b.matches = msg.matches
+	b.truncated = msg.truncated
+	if msg.err != nil {
+		b.err = msg.err
+	}
+}
+
+// Update handles user input in the current mode
+func (b *SourceBrowser) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+
+	// The module search term is entered over any mode
+	if b.typing {
+		switch {
+		case ok && key.Matches(keyMsg, b.keyMap.Pager.Apply):
+			b.typing = false
+			b.input.Blur()
+			term := strings.TrimSpace(b.input.Value())
+			if term == "" || b.err != nil {
+				return nil
+			}
+			b.term = term
+			b.searching = true
+			b.matches = nil
+			b.matchIdx, b.matchOff = 0, 0
+			b.mode = sourceModeResults
+			return searchModule(b.root, term)
+		case ok && key.Matches(keyMsg, b.keyMap.Pager.Cancel):
+			b.typing = false
+			b.input.Blur()
+			return nil
+		}
+		var cmd tea.Cmd
+		b.input, cmd = b.input.Update(msg)
+		return cmd
+	}
+
+	switch b.mode {
+	case sourceModeFile:
+		return b.updateFile(msg)
+	case sourceModeResults:
+		if ok {
+			b.updateResults(keyMsg)
+		}
+		return nil
+	}
+
+	if ok {
+		return b.updateTree(keyMsg)
+	}
+	return nil
+}
+
+// startSearch starts entering a module search term
+func (b *SourceBrowser) startSearch() tea.Cmd {
+	b.typing = true
+	b.input.SetValue(b.term)
+	b.input.CursorEnd()
+	return b.input.Focus()
+}
+
+// updateTree handles user input in the directory tree
+func (b *SourceBrowser) updateTree(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, b.keyMap.Up):
+		b.cursor = max(b.cursor-1, 0)
+	case key.Matches(msg, b.keyMap.Down):
+		b.cursor = min(b.cursor+1, max(len(b.nodes)-1, 0))
+	case key.Matches(msg, b.keyMap.Open):
+		node := b.selected()
+		switch {
+		case node == nil:
+		case node.dir && node.expanded:
+			node.expanded = false
+			b.refresh()
+		case node.dir:
+			b.expand(node)
+			b.refresh()
+		default:
+			b.openFile(node, 0)
+			b.fromMatch = false
+		}
+	case key.Matches(msg, b.keyMap.Back):
+		// Collapse the directory, or go to the parent directory
+		node := b.selected()
+		switch {
+		case node == nil:
+		case node.dir && node.expanded:
+			node.expanded = false
+			b.refresh()
+		case node.parent != nil && node.parent != b.tree:
+			for i, n := range b.nodes {
+				if n == node.parent {
+					b.cursor = i
+				}
+			}
+		}
+	case key.Matches(msg, b.keyMap.Search):
+		return b.startSearch()
+	}
+	b.scrollToCursor()
+	return nil
+}
+
+// updateFile handles user input in the file viewer
+func (b *SourceBrowser) updateFile(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok && !b.pager.Searching() && key.Matches(msg, b.keyMap.Close) {
+		// Go back to where the file was opened from
+		b.mode = sourceModeTree
+		if b.fromMatch {
+			b.mode = sourceModeResults
+		}
+		return nil
+	}
+	return b.pager.Update(msg)
+}
+
+// updateResults handles user input in the search results
+func (b *SourceBrowser) updateResults(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, b.keyMap.Close):
+		b.mode = sourceModeTree
+	case key.Matches(msg, b.keyMap.Up):
+		b.matchIdx = max(b.matchIdx-1, 0)
+	case key.Matches(msg, b.keyMap.Down):
+		b.matchIdx = min(b.matchIdx+1, max(len(b.matches)-1, 0))
+	case key.Matches(msg, b.keyMap.Search):
+		b.startSearch()
+	case key.Matches(msg, b.keyMap.Open) && b.matchIdx < len(b.matches):
+		// Open the file at the match, with the term highlighted
+		match := b.matches[b.matchIdx]
+		if file := b.findFile(match.Path); file != nil {
+			b.openFile(file, match.Line)
+			b.fromMatch = true
+			if b.fileErr == nil {
+				b.pager.SearchFrom(b.term, match.Line-1)
+			}
+			// Select the file in the tree, for when going back to it
+			for i, node := range b.nodes {
+				if node == file {
+					b.cursor = i
+				}
+			}
+		}
+	}
+	b.scrollToCursor()
+}
+
+// treeView renders the directory tree
+func (b *SourceBrowser) treeView() string {
+	var lines []string
+	end := min(b.offset+b.bodyHeight(), len(b.nodes))
+	for i := b.offset; i < end; i++ {
+		node := b.nodes[i]
+		indent := strings.Repeat("  ", node.depth)
+		label := indent + "  " + node.name
+		style := b.styles.File
+		if node.dir {
+			style = b.styles.Dir
+			label = indent + "▸ " + node.name + "/"
+			if node.expanded {
+				label = indent + "▾ " + node.name + "/"
+			}
+		}
+		if i == b.cursor {
+			lines = append(lines, b.styles.Selected.Render("> "+label))
+		} else {
+			lines = append(lines, "  "+style.Render(label))
+		}
+	}
+	if len(b.nodes) == 0 {
+		lines = append(lines, b.styles.Message.Render("The module directory is empty."))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// resultsView renders the matches of the module search
+func (b *SourceBrowser) resultsView() string {
+	switch {
+	case b.searching:
+		return b.styles.Message.Render(fmt.Sprintf("Searching for %q...", b.term))
+	case len(b.matches) == 0:
+		return b.styles.Message.Render(fmt.Sprintf("No matches for %q.", b.term))
+	}
+
+	var lines []string
+	end := min(b.matchOff+b.bodyHeight(), len(b.matches))
+	for i := b.matchOff; i < end; i++ {
+		match := b.matches[i]
+		location := fmt.Sprintf("%s:%d", match.Path, match.Line)
+		if i == b.matchIdx {
+			lines = append(lines, b.styles.Selected.Render("> "+location)+"  "+match.Text)
+		} else {
+			lines = append(lines, "  "+b.styles.Location.Render(location)+"  "+match.Text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// title returns the title of the current mode
+func (b *SourceBrowser) title() string {
+	title := b.pkg.Path + "@" + b.pkg.Version
+	switch {
+	case b.mode == sourceModeFile && b.file != nil:
+		title += "/" + b.file.path
+	case b.mode == sourceModeResults && !b.searching:
+		title += fmt.Sprintf(" · %d matches for %q", len(b.matches), b.term)
+		if b.truncated {
+			title += fmt.Sprintf(" (first %d)", MaxSourceMatches)
+		}
+	}
+	return b.styles.Title.Render(title)
+}
+
+// footer returns the search input or the help of the current mode
+func (b *SourceBrowser) footer() string {
+	if b.typing {
+		return "  " + b.styles.Prompt.Render("Search module: ") + b.input.View()
+	}
+
+	var bindings []key.Binding
+	switch b.mode {
+	case sourceModeFile:
+		if search := b.pager.SearchView(); search != "" {
+			return "  " + search
+		}
+		bindings = append(b.keyMap.Pager.ShortHelp(), b.keyMap.Editor, b.keyMap.GitHub, b.keyMap.Close)
+	case sourceModeResults:
+		bindings = []key.Binding{b.keyMap.Up, b.keyMap.Down, b.keyMap.Open, b.keyMap.Search, b.keyMap.Editor, b.keyMap.GitHub, b.keyMap.Close}
+	default:
+		bindings = []key.Binding{b.keyMap.Open, b.keyMap.Back, b.keyMap.Search, b.keyMap.Editor, b.keyMap.GitHub, b.keyMap.Close}
+	}
+	return b.help.View(sourceHelp{bindings: bindings})
+}
+
+// View renders the source browser
+func (b *SourceBrowser) View() string {
+	if b.err != nil {
+		return b.styles.Title.Render(b.pkg.Path+"@"+b.pkg.Version) + "\n\n" +
+			b.styles.Message.Render(b.err.Error()) + "\n\n" + b.help.View(sourceHelp{bindings: []key.Binding{b.keyMap.Close}})
+	}
+
+	var body string
+	switch b.mode {
+	case sourceModeFile:
+		body = b.pager.View()
+	case sourceModeResults:
+		body = b.resultsView()
+	default:
+		body = b.treeView()
+	}
+	body = lipgloss.NewStyle().MaxWidth(b.width).Height(b.bodyHeight()).Render(body)
+
+	return b.title() + "\n\n" + body + "\n\n" + lipgloss.NewStyle().MaxWidth(b.width).Render(b.footer())
+}
@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// withCachedModule extracts the files, keyed by their slash-separated path, as the module in a temporary module cache
+func withCachedModule(t *testing.T, path, version string, files map[string]string) string {
+	t.Helper()
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	root := filepath.Join(cache, filepath.FromSlash(path+"@"+version))
+	for name, content := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestSearchSource(t *testing.T) {
+	root := withCachedModule(t, "github.com/owner/repo", "v1.0.0", map[string]string{
+		"main.go":       "package main\n\nfunc main() {\n\tRun()\n}\n",
+		"run/run.go":    "package run\n\n// Run runs it\nfunc Run() {}\n",
+		"logo.png":      "\x89PNG\x00\x00binary run",
+		"docs/guide.md": "Nothing to see here\n",
+	})
+
+	matches, truncated, err := SearchSource(root, "RUN")
+	if err != nil {
+		t.Fatalf("SearchSource() returned an error: %v", err)
+	}
+	if truncated {
+		t.Error("Expected the matches not to be truncated")
+	}
+	expected := []SourceMatch{
+		{Path: "main.go", Line: 4, Text: "Run()"},
+		{Path: "run/run.go", Line: 1, Text: "package run"},
+		{Path: "run/run.go", Line: 3, Text: "// Run runs it"},
+		{Path: "run/run.go", Line: 4, Text: "func Run() {}"},
+	}
+	if len(matches) != len(expected) {
+		t.Fatalf("SearchSource() = %v, want %v", matches, expected)
+	}
+	for i := range expected {
+		if matches[i] != expected[i] {
+			t.Errorf("SearchSource()[%d] = %v, want %v", i, matches[i], expected[i])
+		}
+	}
+}
+
+func TestHighlightGo(t *testing.T) {
+	styles := SyntaxStyles{
+		Keyword:    lipgloss.NewStyle().SetString("K"),
+		String:     lipgloss.NewStyle().SetString("S"),
+		Number:     lipgloss.NewStyle().SetString("N"),
+		Comment:    lipgloss.NewStyle().SetString("C"),
+		Builtin:    lipgloss.NewStyle().SetString("B"),
+		LineNumber: lipgloss.NewStyle(),
+	}
+
+	lines := HighlightGo("package main\n\n// Answer\nfunc f() int {\n\treturn len(\"ab\") + 40\n}", styles)
+	expected := []string{
+		"K package main",
+		"",
+		"C // Answer",
+		"K func f() B int {",
+		"    K return B len(S \"ab\") + N 40",
+		"}",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("HighlightGo() = %q, want %q", lines, expected)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("HighlightGo()[%d] = %q, want %q", i, lines[i], expected[i])
+		}
+	}
+
+	numbered := WithLineNumbers(make([]string, 10), styles.LineNumber)
+	if numbered[0] != " 1 │ " || numbered[9] != "10 │ " {
+		t.Errorf("WithLineNumbers() = %q, want right-aligned line numbers", numbered)
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	bin := t.TempDir()
+	for _, name := range []string{"vim", "code", "ed"} {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+
+	tests := []struct {
+		name     string
+		editor   string
+		line     int
+		expected []string
+	}{
+		{name: "Editor taking +line", editor: "vim", line: 12, expected: []string{"vim", "+12", "main.go"}},
+		{name: "Editor taking --goto", editor: "code --wait", line: 12, expected: []string{"code", "--wait", "--goto", "main.go:12"}},
+		{name: "Unknown editor", editor: "ed", line: 12, expected: []string{"ed", "main.go"}},
+		{name: "No line", editor: "vim", expected: []string{"vim", "main.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("VISUAL", "")
+			t.Setenv("EDITOR", tt.editor)
+			cmd, err := editorCommand("main.go", tt.line)
+			if err != nil {
+				t.Fatalf("editorCommand() returned an error: %v", err)
+			}
+			args := append([]string{filepath.Base(cmd.Path)}, cmd.Args[1:]...)
+			if strings.Join(args, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("editorCommand() = %q, want %q", args, tt.expected)
+			}
+		})
+	}
+
+	t.Setenv("EDITOR", "missing-editor")
+	if _, err := editorCommand("main.go", 0); err == nil {
+		t.Error("Expected an error for an editor that isn't installed")
+	}
+}
+
+func TestAppSource(t *testing.T) {
+	root := withCachedModule(t, "github.com/owner/repo", "v1.2.0", map[string]string{
+		"go.mod":     "module github.com/owner/repo\n",
+		"repo.go":    "package repo\n\n// Version is the version\nconst Version = \"1.2.0\"\n",
+		"cmd/cli.go": "package main\n\nfunc main() {}\n",
+	})
+	var executed *exec.Cmd
+	execProcess = func(cmd *exec.Cmd, fn tea.ExecCallback) tea.Cmd {
+		executed = cmd
+		return func() tea.Msg { return fn(nil) }
+	}
+	t.Cleanup(func() {
+		execProcess = tea.ExecProcess
+	})
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "true")
+
+	pkg := model.NewPackage("github.com/owner/repo", "v1.2.0")
+	app := NewApp([]*model.Package{pkg}, NewMockGitHubClient())
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	// The module directory is shown with directories first
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	if app.state != StateSource {
+		t.Fatalf("Expected the source browser to be shown, got state %v", app.state)
+	}
+	view := app.View()
+	if cmd, mod := strings.Index(view, "cmd/"), strings.Index(view, "go.mod"); cmd < 0 || mod < 0 || cmd > mod {
+		t.Errorf("Expected the directories to be listed before the files, got:\n%s", view)
+	}
+
+	// Expanding a directory shows its files
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(app.View(), "cli.go") {
+		t.Errorf("Expected the expanded directory to show its files, got:\n%s", app.View())
+	}
+
+	// Searching the module lists the matches
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	for _, r := range "version" {
+		app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected the module search to start")
+	}
+	app.Update(cmd())
+	view = app.View()
+	for _, expected := range []string{"2 matches", "repo.go:3", "repo.go:4"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected the search results to contain %q, got:\n%s", expected, view)
+		}
+	}
+
+	// Opening a match shows the file with line numbers
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	view = app.View()
+	for _, expected := range []string{"github.com/owner/repo@v1.2.0/repo.go", "4 │ ", "Version"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected the file view to contain %q, got:\n%s", expected, view)
+		}
+	}
+	if url := app.source.GitHubURL(); url != "https://github.com/owner/repo/blob/v1.2.0/repo.go#L4" {
+		t.Errorf("GitHubURL() = %q, want the line of the match at the tag", url)
+	}
+
+	// The file is opened in the editor at the line of the match
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if cmd == nil || executed == nil {
+		t.Fatal("Expected the editor to be run")
+	}
+	if args := strings.Join(executed.Args[1:], " "); args != filepath.Join(root, "repo.go") {
+		t.Errorf("Expected the editor to open the file, got %q", args)
+	}
+
+	// Closing goes back to the matches, the tree, then the list
+	for _, state := range []State{StateSource, StateSource, StateList} {
+		app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		if app.state != state {
+			t.Fatalf("Expected state %v after closing, got %v", state, app.state)
+		}
+	}
+}
+
+func TestAppSourceNotDownloaded(t *testing.T) {
+	withCachedModule(t, "github.com/owner/other", "v1.0.0", nil)
+
+	app := NewApp([]*model.Package{model.NewPackage("github.com/owner/repo", "v1.2.0")}, NewMockGitHubClient())
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+
+	if view := app.View(); !strings.Contains(view, "isn't in the module cache") {
+		t.Errorf("Expected the module not to be browsable, got:\n%s", view)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if app.state != StateList {
+		t.Errorf("Expected the source browser to be closed, got state %v", app.state)
+	}
+}
@@ -0,0 +1,262 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// SponsorGroup is a place maintainers can be funded, along with the dependencies they maintain
+type SponsorGroup struct {
+	Link     model.FundingLink
+	Packages []*model.Package
+}
+
+// Label returns the name of the funding destination
+func (g SponsorGroup) Label() string {
+	switch g.Link.Platform {
+	case "github":
+		return "@" + g.Link.Account + " · GitHub Sponsors"
+	case "custom":
+		return g.Link.URL
+	}
+	return g.Link.Account + " · " + strings.ReplaceAll(g.Link.Platform, "_", " ")
+}
+
+// GroupSponsors groups the packages by the maintainers they can be funded through.
+// GitHub Sponsors come first, then the destinations funding the most dependencies.
+func GroupSponsors(packages []*model.Package) []SponsorGroup {
+	var groups []SponsorGroup
+	index := make(map[string]int)
+
+	for _, pkg := range packages {
+		if pkg.Repo == nil {
+			continue
+		}
+		for _, link := range pkg.Repo.Sponsors() {
+			key := strings.ToLower(link.URL)
+			i, ok := index[key]
+			if !ok {
+				i = len(groups)
+				index[key] = i
+				groups = append(groups, SponsorGroup{Link: link})
+			}
+			groups[i].Packages = append(groups[i].Packages, pkg)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		gi, gj := groups[i].Link.Platform == "github", groups[j].Link.Platform == "github"
+		if gi != gj {
+			return gi
+		}
+		if len(groups[i].Packages) != len(groups[j].Packages) {
+			return len(groups[i].Packages) > len(groups[j].Packages)
+		}
+		return groups[i].Label() < groups[j].Label()
+	})
+
+	return groups
+}
+
+// SponsorsView represents the screen listing the maintainers of the dependencies that accept funding
+type SponsorsView struct {
+	groups []SponsorGroup
+	cursor int
+	offset int
+	width  int
+	height int
+	help   help.Model
+	styles SponsorsStyles
+	keyMap SponsorsKeyMap
+}
+
+// SponsorsStyles contains the styles for the sponsors screen
+type SponsorsStyles struct {
+	Title    lipgloss.Style
+	Group    lipgloss.Style
+	Selected lipgloss.Style
+	Package  lipgloss.Style
+	Message  lipgloss.Style
+}
+
+// DefaultSponsorsStyles returns the default styles for the sponsors screen
+func DefaultSponsorsStyles() SponsorsStyles {
+	return SponsorsStyles{
+		Title: lipgloss.NewStyle().
+			Foreground(activeTheme.Accent).
+			Bold(true).
+			MarginLeft(2),
+		Group: lipgloss.NewStyle().
+			Foreground(activeTheme.Text).
+			Bold(true),
+		Selected: lipgloss.NewStyle().
+			Foreground(activeTheme.Highlight).
+			Bold(true),
+		Package: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted),
+		Message: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted).
+			Italic(true).
+			MarginLeft(2),
+	}
+}
+
+// SponsorsKeyMap defines the key bindings for the sponsors screen
+type SponsorsKeyMap struct {
+	Up    key.Binding
+	Down  key.Binding
+	Open  key.Binding
+	Close key.Binding
+}
+
+// DefaultSponsorsKeyMap returns the default key bindings for the sponsors screen
+func DefaultSponsorsKeyMap() SponsorsKeyMap {
+	return SponsorsKeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Open: key.NewBinding(
+			key.WithKeys("enter", "o"),
+			key.WithHelp("enter/o", "open sponsor page"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc/q", "back"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view.
+func (k SponsorsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Open, k.Close}
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k SponsorsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Up, k.Down}, {k.Open, k.Close}}
+}
+
+// NewSponsorsView creates a new sponsors screen for the packages
+func NewSponsorsView(packages []*model.Package) *SponsorsView {
+	return &SponsorsView{
+		groups: GroupSponsors(packages),
+		width:  80,
+		height: 24,
+		help:   help.New(),
+		styles: DefaultSponsorsStyles(),
+		keyMap: DefaultSponsorsKeyMap(),
+	}
+}
+
+// SetSize sets the size of the sponsors screen
+func (s *SponsorsView) SetSize(width, height int) {
+	s.width = width
+	s.height = height
+	s.help.Width = width
+	s.scrollToCursor()
+}
+
+// SelectedGroup returns the group under the cursor, or nil if there are none
+func (s *SponsorsView) SelectedGroup() *SponsorGroup {
+	if s.cursor < 0 || s.cursor >= len(s.groups) {
+		return nil
+	}
+	return &s.groups[s.cursor]
+}
+
+// MoveUp moves the cursor to the previous group
+func (s *SponsorsView) MoveUp() {
+	if s.cursor > 0 {
+		s.cursor--
+	}
+	s.scrollToCursor()
+}
+
+// MoveDown moves the cursor to the next group
+func (s *SponsorsView) MoveDown() {
+	if s.cursor < len(s.groups)-1 {
+		s.cursor++
+	}
+	s.scrollToCursor()
+}
+
+// bodyHeight returns the number of lines available for the groups
+func (s *SponsorsView) bodyHeight() int {
+	// Reserve space for the title and help message
+	height := s.height - 4
+	if height < 1 {
+		height = 1
+	}
+	return height
+}
+
+// groupStart returns the line the i-th group starts at
+func (s *SponsorsView) groupStart(i int) int {
+	line := 0
+	for _, group := range s.groups[:i] {
+		line += len(group.Packages) + 2 // header, packages and a blank line
+	}
+	return line
+}
+
+// scrollToCursor adjusts the scroll offset so that the selected group is visible
+func (s *SponsorsView) scrollToCursor() {
+	if len(s.groups) == 0 {
+		return
+	}
+
+	start := s.groupStart(s.cursor)
+	end := start + len(s.groups[s.cursor].Packages) + 1
+	if start < s.offset {
+		s.offset = start
+	}
+	if end > s.offset+s.bodyHeight() {
+		s.offset = end - s.bodyHeight()
+	}
+	if s.offset > start {
+		s.offset = start
+	}
+}
+
+// View renders the sponsors screen
+func (s *SponsorsView) View() string {
+	title := s.styles.Title.Render(fmt.Sprintf("Sponsor your dependencies (%d)", len(s.groups)))
+
+	if len(s.groups) == 0 {
+		message := s.styles.Message.Render("None of the dependencies' maintainers have a sponsor listing or FUNDING.yml.")
+		return title + "\n\n" + message + "\n\n" + s.help.View(s.keyMap)
+	}
+
+	var lines []string
+	for i, group := range s.groups {
+		header := fmt.Sprintf("♥ %s (%d)", group.Label(), len(group.Packages))
+		if i == s.cursor {
+			lines = append(lines, s.styles.Selected.Render("> "+header))
+		} else {
+			lines = append(lines, s.styles.Group.Render("  "+header))
+		}
+		for _, pkg := range group.Packages {
+			lines = append(lines, s.styles.Package.Render("    "+pkg.Path))
+		}
+		lines = append(lines, "")
+	}
+
+	end := s.offset + s.bodyHeight()
+	if end > len(lines) {
+		end = len(lines)
+	}
+	body := strings.Join(lines[s.offset:end], "\n")
+
+	return title + "\n\n" + body + "\n" + s.help.View(s.keyMap)
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// newSponsoredPackage creates a package whose repository has the given funding
+func newSponsoredPackage(path, owner string, ownerSponsorable bool, links ...model.FundingLink) *model.Package {
+	pkg := model.NewPackage(path, "v1.0.0")
+	pkg.Repo = &model.RepoMetadata{
+		Owner:            owner,
+		OwnerSponsorable: ownerSponsorable,
+		FundingLinks:     links,
+	}
+	return pkg
+}
+
+func TestGroupSponsors(t *testing.T) {
+	bubbles := newSponsoredPackage("github.com/charmbracelet/bubbles", "charmbracelet", true,
+		model.FundingLink{Platform: "open_collective", Account: "charmbracelet", URL: "https://opencollective.com/charmbracelet"})
+	bubbletea := newSponsoredPackage("github.com/charmbracelet/bubbletea", "charmbracelet", true)
+	toml := newSponsoredPackage("github.com/BurntSushi/toml", "BurntSushi", false,
+		model.FundingLink{Platform: "github", Account: "arp242", URL: "https://github.com/sponsors/arp242"})
+	unfunded := newSponsoredPackage("github.com/pkg/errors", "pkg", false)
+	notFetched := model.NewPackage("golang.org/x/mod", "v0.27.0")
+
+	groups := GroupSponsors([]*model.Package{toml, bubbles, unfunded, bubbletea, notFetched})
+
+	expected := []struct {
+		label    string
+		packages []*model.Package
+	}{
+		{label: "@charmbracelet · GitHub Sponsors", packages: []*model.Package{bubbles, bubbletea}},
+		{label: "@arp242 · GitHub Sponsors", packages: []*model.Package{toml}},
+		{label: "charmbracelet · open collective", packages: []*model.Package{bubbles}},
+	}
+
+	if len(groups) != len(expected) {
+		t.Fatalf("GroupSponsors() returned %d groups, want %d: %+v", len(groups), len(expected), groups)
+	}
+	for i, group := range groups {
+		if group.Label() != expected[i].label {
+			t.Errorf("Group %d label = %q, want %q", i, group.Label(), expected[i].label)
+		}
+		if len(group.Packages) != len(expected[i].packages) {
+			t.Errorf("Group %d has %d packages, want %d", i, len(group.Packages), len(expected[i].packages))
+			continue
+		}
+		for j, pkg := range group.Packages {
+			if pkg != expected[i].packages[j] {
+				t.Errorf("Group %d package %d = %s, want %s", i, j, pkg.Path, expected[i].packages[j].Path)
+			}
+		}
+	}
+}
+
+func TestSponsorsViewNavigation(t *testing.T) {
+	packages := []*model.Package{
+		newSponsoredPackage("github.com/charmbracelet/bubbles", "charmbracelet", true),
+		newSponsoredPackage("github.com/BurntSushi/toml", "BurntSushi", true),
+	}
+
+	view := NewSponsorsView(packages)
+	view.SetSize(100, 30)
+
+	result := view.View()
+	for _, expected := range []string{
+		"Sponsor your dependencies (2)",
+		"> ♥ @BurntSushi · GitHub Sponsors (1)",
+		"github.com/BurntSushi/toml",
+		"♥ @charmbracelet · GitHub Sponsors (1)",
+		"github.com/charmbracelet/bubbles",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected view to contain %q, but it didn't.\nGot: %s", expected, result)
+		}
+	}
+
+	view.MoveDown()
+	view.MoveDown()
+	if group := view.SelectedGroup(); group == nil || group.Link.URL != "https://github.com/sponsors/charmbracelet" {
+		t.Errorf("SelectedGroup() = %+v, want charmbracelet", group)
+	}
+
+	view.MoveUp()
+	if group := view.SelectedGroup(); group == nil || group.Link.URL != "https://github.com/sponsors/BurntSushi" {
+		t.Errorf("SelectedGroup() = %+v, want BurntSushi", group)
+	}
+}
+
+func TestSponsorsViewEmpty(t *testing.T) {
+	view := NewSponsorsView([]*model.Package{model.NewPackage("golang.org/x/mod", "v0.27.0")})
+
+	if view.SelectedGroup() != nil {
+		t.Error("Expected no selected group")
+	}
+	if result := view.View(); !strings.Contains(result, "None of the dependencies' maintainers") {
+		t.Errorf("Expected empty message, got: %s", result)
+	}
+}
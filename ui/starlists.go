@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// starListsMsg is sent when the user's star lists have been fetched
+type starListsMsg struct {
+	pkg   *model.Package
+	lists []github.StarList
+	err   error
+}
+
+// starListsChosenMsg is sent when the star list picker is saved
+type starListsChosenMsg struct {
+	pkg     *model.Package
+	lists   []github.StarList
+	checked []bool // Whether the package should be in each list
+	name    string // Name of a new list to add the package to, empty for none
+}
+
+// fetchStarLists returns a command that fetches the user's star lists to pick from for the package
+func fetchStarLists(githubClient github.GitHubClient, pkg *model.Package) tea.Cmd {
+	return func() tea.Msg {
+		lists, err := githubClient.ListStarLists()
+		return starListsMsg{pkg: pkg, lists: lists, err: err}
+	}
+}
+
+// newStarListsLoading creates the dialog shown while the star lists are being fetched
+func newStarListsLoading(pkg *model.Package) *Dialog {
+	return NewDialog("Star lists: "+pkg.GitHubRepoPath(), "Loading star lists...").
+		WithButtons("Cancel").
+		OnResult(func(DialogResult) tea.Msg { return nil })
+}
+
+// newStarListPicker creates the dialog for choosing the star lists the package's repository is in,
+// with an input to name a new list to add it to
+func newStarListPicker(pkg *model.Package, lists []github.StarList) *Dialog {
+	labels := make([]string, len(lists))
+	checked := make([]bool, len(lists))
+	for i, list := range lists {
+		labels[i] = fmt.Sprintf("%s (%d)", list.Name, len(list.Repositories))
+		if list.IsPrivate {
+			labels[i] += " 🔒"
+		}
+		checked[i] = list.Contains(pkg)
+	}
+
+	message := "Name a new list to add the repository to, or check the lists to keep it in."
+	if len(lists) == 0 {
+		message = "You don't have any star lists yet. Name one to add the repository to."
+	}
+	return NewDialog("Star lists: "+pkg.GitHubRepoPath(), message).
+		WithInput("New list", "List name", "").
+		WithChecklist(labels, checked).
+		WithButtons("Save", "Cancel").
+		OnResult(func(result DialogResult) tea.Msg {
+			if !result.Confirmed() {
+				return nil
+			}
+			return starListsChosenMsg{
+				pkg:     pkg,
+				lists:   lists,
+				checked: result.Checked,
+				name:    strings.TrimSpace(result.Inputs[0]),
+			}
+		})
+}
+
+// updateStarLists returns a command that adds the package to the checked lists and the new one,
+// and removes it from the unchecked lists. It returns nil if nothing changed.
+// Adding stars the repository, so the update runs on a copy of the package like the other GitHub actions.
+func updateStarLists(githubClient github.GitHubClient, msg starListsChosenMsg) tea.Cmd {
+	var add, remove []github.StarList
+	for i, list := range msg.lists {
+		switch {
+		case msg.checked[i] && !list.Contains(msg.pkg):
+			add = append(add, list)
+		case !msg.checked[i] && list.Contains(msg.pkg):
+			remove = append(remove, list)
+		}
+	}
+	if len(add) == 0 && len(remove) == 0 && msg.name == "" {
+		return nil
+	}
+
+	return runGitHubAction(statusStar, []*model.Package{msg.pkg}, func(packages []*model.Package) (string, error) {
+		var errs []error
+		if msg.name != "" {
+			list, err := githubClient.CreateStarList(msg.name, "", false)
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				add = append(add, *list)
+			}
+		}
+		for _, list := range add {
+			errs = append(errs, githubClient.AddToStarList(&list, packages))
+		}
+		for _, list := range remove {
+			errs = append(errs, githubClient.RemoveFromStarList(&list, packages))
+		}
+
+		if err := errors.Join(errs...); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Updated the star lists of %s", msg.pkg.GitHubRepoPath()), nil
+	})
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StatusLevel is the severity of a status message
+type StatusLevel int
+
+const (
+	StatusSuccess StatusLevel = iota
+	StatusWarning
+	StatusError
+)
+
+// statusDurations is how long messages of each level stay in the status bar
+var statusDurations = map[StatusLevel]time.Duration{
+	StatusSuccess: 3 * time.Second,
+	StatusWarning: 5 * time.Second,
+	StatusError:   8 * time.Second,
+}
+
+// StatusEntry is a message shown in the status bar
+type StatusEntry struct {
+	Level   StatusLevel
+	Message string
+	Time    time.Time
+}
+
+// statusExpiredMsg is sent when a status message has been shown long enough
+type statusExpiredMsg struct {
+	id int
+}
+
+// StatusBar shows the result of the last action for a while and keeps a log of the errors
+type StatusBar struct {
+	current *StatusEntry
+	id      int // Identifies the current message so that older timers don't clear it
+	errors  []StatusEntry
+	width   int
+	styles  StatusBarStyles
+	now     func() time.Time
+}
+
+// StatusBarStyles contains the styles for the status bar
+type StatusBarStyles struct {
+	Success lipgloss.Style
+	Warning lipgloss.Style
+	Error   lipgloss.Style
+	Hint    lipgloss.Style
+}
+
+// DefaultStatusBarStyles returns the default styles for the status bar
+func DefaultStatusBarStyles() StatusBarStyles {
+	return StatusBarStyles{
+		Success: lipgloss.NewStyle().
+			Foreground(activeTheme.Success).
+			MarginLeft(2),
+		Warning: lipgloss.NewStyle().
+			Foreground(activeTheme.Warning).
+			MarginLeft(2),
+		Error: lipgloss.NewStyle().
+			Foreground(activeTheme.Error).
+			MarginLeft(2),
+		Hint: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted).
+			MarginLeft(2),
+	}
+}
+
+// NewStatusBar creates a new status bar
+func NewStatusBar() *StatusBar {
+	return &StatusBar{
+		width:  80,
+		styles: DefaultStatusBarStyles(),
+		now:    time.Now,
+	}
+}
+
+// Success shows a success message
+func (s *StatusBar) Success(message string) tea.Cmd {
+	return s.show(StatusSuccess, message)
+}
+
+// Warning shows a warning message
+func (s *StatusBar) Warning(message string) tea.Cmd {
+	return s.show(StatusWarning, message)
+}
+
+// Error shows an error and adds it to the error log
+func (s *StatusBar) Error(err error) tea.Cmd {
+	return s.show(StatusError, err.Error())
+}
+
+// show shows the message and returns a command that clears it once it has been shown long enough
+func (s *StatusBar) show(level StatusLevel, message string) tea.Cmd {
+	entry := StatusEntry{Level: level, Message: message, Time: s.now()}
+	if level == StatusError {
+		s.errors = append(s.errors, entry)
+	}
+
+	s.id++
+	s.current = &entry
+	id := s.id
+	return tea.Tick(statusDurations[level], func(time.Time) tea.Msg {
+		return statusExpiredMsg{id: id}
+	})
+}
+
+// Expire clears the message if it's the one the timer was started for
+func (s *StatusBar) Expire(msg statusExpiredMsg) {
+	if msg.id == s.id {
+		s.current = nil
+	}
+}
+
+// Errors returns the errors that occurred this session, oldest first
+func (s *StatusBar) Errors() []StatusEntry {
+	return s.errors
+}
+
+// SetWidth sets the width of the status bar
+func (s *StatusBar) SetWidth(width int) {
+	s.width = width
+}
+
+// View renders the current message, or a reminder of the errors once it has expired
+func (s *StatusBar) View() string {
+	if s.current == nil {
+		if len(s.errors) == 0 {
+			return ""
+		}
+		if len(s.errors) == 1 {
+			return s.styles.Hint.Render("1 error this session, press ! to see it")
+		}
+		return s.styles.Hint.Render(fmt.Sprintf("%d errors this session, press ! to see them", len(s.errors)))
+	}
+
+	var line string
+	switch s.current.Level {
+	case StatusWarning:
+		line = s.styles.Warning.Render("! " + s.current.Message)
+	case StatusError:
+		line = s.styles.Error.Render("✗ " + s.current.Message)
+	default:
+		line = s.styles.Success.Render("✓ " + s.current.Message)
+	}
+
+	// Keep the status bar on one line so the layout doesn't jump
+	return lipgloss.NewStyle().MaxWidth(s.width).MaxHeight(1).Render(line)
+}
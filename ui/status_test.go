@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/browser"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// withoutStatusDelay makes status messages expire immediately for the rest of the test
+func withoutStatusDelay(t *testing.T) {
+	t.Helper()
+	saved := make(map[StatusLevel]time.Duration)
+	for level, duration := range statusDurations {
+		saved[level] = duration
+		statusDurations[level] = 0
+	}
+	t.Cleanup(func() {
+		for level, duration := range saved {
+			statusDurations[level] = duration
+		}
+	})
+}
+
+func TestStatusBar(t *testing.T) {
+	withoutStatusDelay(t)
+	status := NewStatusBar()
+
+	if view := status.View(); view != "" {
+		t.Errorf("Expected an empty status bar, got %q", view)
+	}
+
+	first := status.Error(errors.New("failed to star owner/repo"))
+	if view := status.View(); !strings.Contains(view, "✗ failed to star owner/repo") {
+		t.Errorf("Expected the error to be shown, got %q", view)
+	}
+
+	// A newer message isn't cleared by the timer of the older one
+	second := status.Success("Starred owner/other")
+	status.Expire(first().(statusExpiredMsg))
+	if view := status.View(); !strings.Contains(view, "✓ Starred owner/other") {
+		t.Errorf("Expected the newer message to stay, got %q", view)
+	}
+
+	// Once the message expires, the errors are still pointed out
+	status.Expire(second().(statusExpiredMsg))
+	if view := status.View(); !strings.Contains(view, "1 error this session, press ! to see it") {
+		t.Errorf("Expected a reminder of the errors, got %q", view)
+	}
+
+	// Only errors are logged
+	status.Warning("Nothing to copy")
+	if errs := status.Errors(); len(errs) != 1 || errs[0].Message != "failed to star owner/repo" {
+		t.Errorf("Expected only the error to be logged, got %v", errs)
+	}
+}
+
+func TestAppReportsActionResults(t *testing.T) {
+	withoutStatusDelay(t)
+	failing := model.NewPackage("github.com/owner/failing", "v1.0.0")
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+
+	mockClient := NewMockGitHubClient()
+	mockClient.failingRepos[failing.Path] = errors.New("failed to star owner/failing: server error")
+	app := NewApp([]*model.Package{failing, pkg}, mockClient)
+
+	// Failures are shown in the status bar and kept in the error log
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	finishGitHubAction(t, app, cmd)
+	if view := app.View(); !strings.Contains(view, "failed to star owner/failing: server error") {
+		t.Errorf("Expected the star failure to be shown, got:\n%s", view)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	finishGitHubAction(t, app, cmd)
+	if view := app.View(); !strings.Contains(view, "Starred charmbracelet/bubbles") {
+		t.Errorf("Expected the star to be confirmed, got:\n%s", view)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("!")})
+	if app.state != StateErrorLog {
+		t.Fatalf("Expected state to be StateErrorLog, got %v", app.state)
+	}
+	if view := app.View(); !strings.Contains(view, "server error") {
+		t.Errorf("Expected the error log to list the failure, got:\n%s", view)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.state != StateList {
+		t.Errorf("Expected state to be StateList, got %v", app.state)
+	}
+}
+
+func TestAppCopiesURLTheBrowserCouldNotOpen(t *testing.T) {
+	withoutStatusDelay(t)
+	var copied string
+	copyToClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+	openBrowser = func(url string) error {
+		return errors.New("no browser")
+	}
+	defer func() {
+		copyToClipboard = clipboard.WriteAll
+		openBrowser = browser.OpenURL
+	}()
+
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	app := NewApp([]*model.Package{pkg}, NewMockGitHubClient())
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if view := app.View(); !strings.Contains(view, "failed to open the browser, press u to copy") {
+		t.Errorf("Expected the failure to suggest copying the URL, got:\n%s", view)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	if copied != pkg.PkgGoDevURL() {
+		t.Errorf("Expected %q to be copied, got %q", pkg.PkgGoDevURL(), copied)
+	}
+
+	// Without a failed URL, the selected package's repository is copied
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	if copied != pkg.GitHubURL() {
+		t.Errorf("Expected %q to be copied, got %q", pkg.GitHubURL(), copied)
+	}
+}
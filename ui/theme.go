@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/config"
+)
+
+// Theme contains the colors every screen is styled with, by role
+type Theme struct {
+	Accent    lipgloss.Color // Titles and prompts
+	Text      lipgloss.Color // Regular text
+	Muted     lipgloss.Color // Labels, borders and hints
+	Subtle    lipgloss.Color // Comments and quotes
+	Highlight lipgloss.Color // Selected items
+	Link      lipgloss.Color // Links and directories
+	Success   lipgloss.Color
+	Warning   lipgloss.Color
+	Error     lipgloss.Color
+	Inverse   lipgloss.Color // Text on an accent or warning background
+	Surface   lipgloss.Color // Background of code blocks
+	String    lipgloss.Color // String literals in source code
+	Number    lipgloss.Color // Number literals in source code
+	Builtin   lipgloss.Color // Predeclared identifiers in source code
+	Code      lipgloss.Color // Inline code in Markdown
+}
+
+// activeTheme is the theme the default styles are created with
+var activeTheme = DarkTheme()
+
+// DarkTheme returns the theme for terminals with a dark background, used by default
+func DarkTheme() Theme {
+	return Theme{
+		Accent:    lipgloss.Color("99"),
+		Text:      lipgloss.Color("252"),
+		Muted:     lipgloss.Color("240"),
+		Subtle:    lipgloss.Color("245"),
+		Highlight: lipgloss.Color("212"),
+		Link:      lipgloss.Color("39"),
+		Success:   lipgloss.Color("42"),
+		Warning:   lipgloss.Color("214"),
+		Error:     lipgloss.Color("196"),
+		Inverse:   lipgloss.Color("0"),
+		Surface:   lipgloss.Color("236"),
+		String:    lipgloss.Color("114"),
+		Number:    lipgloss.Color("215"),
+		Builtin:   lipgloss.Color("81"),
+		Code:      lipgloss.Color("203"),
+	}
+}
+
+// LightTheme returns the theme for terminals with a light background
+func LightTheme() Theme {
+	return Theme{
+		Accent:    lipgloss.Color("55"),
+		Text:      lipgloss.Color("235"),
+		Muted:     lipgloss.Color("244"),
+		Subtle:    lipgloss.Color("242"),
+		Highlight: lipgloss.Color("162"),
+		Link:      lipgloss.Color("25"),
+		Success:   lipgloss.Color("28"),
+		Warning:   lipgloss.Color("130"),
+		Error:     lipgloss.Color("160"),
+		Inverse:   lipgloss.Color("231"),
+		Surface:   lipgloss.Color("254"),
+		String:    lipgloss.Color("28"),
+		Number:    lipgloss.Color("130"),
+		Builtin:   lipgloss.Color("30"),
+		Code:      lipgloss.Color("124"),
+	}
+}
+
+// SetTheme sets the theme of the styles created afterwards
+func SetTheme(theme Theme) {
+	activeTheme = theme
+}
+
+// colors returns the colors of the theme by their name in the config file
+func (t *Theme) colors() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"accent":    &t.Accent,
+		"text":      &t.Text,
+		"muted":     &t.Muted,
+		"subtle":    &t.Subtle,
+		"highlight": &t.Highlight,
+		"link":      &t.Link,
+		"success":   &t.Success,
+		"warning":   &t.Warning,
+		"error":     &t.Error,
+		"inverse":   &t.Inverse,
+		"surface":   &t.Surface,
+		"string":    &t.String,
+		"number":    &t.Number,
+		"builtin":   &t.Builtin,
+		"code":      &t.Code,
+	}
+}
+
+// NewTheme returns the named theme with the colors overridden by the config
+func NewTheme(cfg config.Theme) (Theme, error) {
+	theme := DarkTheme()
+	if cfg.Name == config.ThemeLight {
+		theme = LightTheme()
+	}
+
+	colors := theme.colors()
+	var errs []error
+	for _, role := range sortedNames(cfg.Colors) {
+		color, ok := colors[role]
+		if !ok {
+			errs = append(errs, &config.FieldError{
+				Field:   "theme.colors." + role,
+				Message: fmt.Sprintf("unknown color, expected one of %s", strings.Join(sortedNames(colors), ", ")),
+			})
+			continue
+		}
+		*color = lipgloss.Color(cfg.Colors[role])
+	}
+	return theme, errors.Join(errs...)
+}
+
+// sortedNames returns the names in alphabetical order, so that they're listed in a stable order
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
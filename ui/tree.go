@@ -0,0 +1,321 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/deptree"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// TreeView represents the dependency tree screen
+type TreeView struct {
+	tree   *deptree.Tree
+	nodes  []*deptree.Node // Visible nodes, in the order they're shown
+	cursor int
+	offset int
+	width  int
+	height int
+	help   help.Model
+	styles TreeStyles
+	keyMap TreeKeyMap
+}
+
+// TreeStyles contains the styles for the dependency tree
+type TreeStyles struct {
+	Title     lipgloss.Style
+	Node      lipgloss.Style
+	Selected  lipgloss.Style
+	Version   lipgloss.Style
+	Duplicate lipgloss.Style
+	Conflict  lipgloss.Style
+	Error     lipgloss.Style
+}
+
+// DefaultTreeStyles returns the default styles for the dependency tree
+func DefaultTreeStyles() TreeStyles {
+	return TreeStyles{
+		Title: lipgloss.NewStyle().
+			Foreground(activeTheme.Accent).
+			Bold(true).
+			MarginLeft(2),
+		Node: lipgloss.NewStyle().
+			Foreground(activeTheme.Text),
+		Selected: lipgloss.NewStyle().
+			Foreground(activeTheme.Highlight).
+			Bold(true),
+		Version: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted),
+		Duplicate: lipgloss.NewStyle().
+			Foreground(activeTheme.Muted).
+			Italic(true),
+		Conflict: lipgloss.NewStyle().
+			Foreground(activeTheme.Warning),
+		Error: lipgloss.NewStyle().
+			Foreground(activeTheme.Error),
+	}
+}
+
+// TreeKeyMap defines the key bindings for the dependency tree
+type TreeKeyMap struct {
+	Up        key.Binding
+	Down      key.Binding
+	Expand    key.Binding
+	Collapse  key.Binding
+	Toggle    key.Binding
+	ExpandAll key.Binding
+	Parent    key.Binding
+	Close     key.Binding
+	Actions   []key.Binding // Package actions of the list, shown in the help
+}
+
+// DefaultTreeKeyMap returns the default key bindings for the dependency tree
+func DefaultTreeKeyMap() TreeKeyMap {
+	return TreeKeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Expand: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "expand"),
+		),
+		Collapse: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "collapse"),
+		),
+		Toggle: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "expand/collapse"),
+		),
+		ExpandAll: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "expand all"),
+		),
+		Parent: key.NewBinding(
+			key.WithKeys("P", "backspace"),
+			key.WithHelp("P", "parent"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "q", "t"),
+			key.WithHelp("esc/q", "back"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view.
+func (k TreeKeyMap) ShortHelp() []key.Binding {
+	return append([]key.Binding{k.Expand, k.Collapse, k.ExpandAll, k.Parent}, append(k.Actions, k.Close)...)
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k TreeKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Up, k.Down, k.Expand, k.Collapse, k.Toggle}, {k.ExpandAll, k.Parent, k.Close}, k.Actions}
+}
+
+// NewTreeView creates a new dependency tree screen
+func NewTreeView(tree *deptree.Tree) *TreeView {
+	v := &TreeView{
+		tree:   tree,
+		width:  80,
+		height: 24,
+		help:   help.New(),
+		styles: DefaultTreeStyles(),
+		keyMap: DefaultTreeKeyMap(),
+	}
+	v.refresh()
+	return v
+}
+
+// SetSize sets the size of the dependency tree screen
+func (v *TreeView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	v.help.Width = width
+	v.scrollToCursor()
+}
+
+// SelectedNode returns the node under the cursor, or nil if the tree is empty
+func (v *TreeView) SelectedNode() *deptree.Node {
+	if v.cursor < 0 || v.cursor >= len(v.nodes) {
+		return nil
+	}
+	return v.nodes[v.cursor]
+}
+
+// SelectedPackage returns the package of the node under the cursor, or nil if the tree is empty
+func (v *TreeView) SelectedPackage() *model.Package {
+	if node := v.SelectedNode(); node != nil {
+		return node.Package
+	}
+	return nil
+}
+
+// MoveUp moves the cursor to the previous node
+func (v *TreeView) MoveUp() {
+	if v.cursor > 0 {
+		v.cursor--
+	}
+	v.scrollToCursor()
+}
+
+// MoveDown moves the cursor to the next node
+func (v *TreeView) MoveDown() {
+	if v.cursor < len(v.nodes)-1 {
+		v.cursor++
+	}
+	v.scrollToCursor()
+}
+
+// Expand shows the requirements of the selected node, or moves to its first requirement if they're shown
+func (v *TreeView) Expand() error {
+	node := v.SelectedNode()
+	if node == nil {
+		return nil
+	}
+	if node.Expanded {
+		v.MoveDown()
+		return nil
+	}
+	err := v.tree.Expand(node)
+	v.refresh()
+	return err
+}
+
+// Collapse hides the requirements of the selected node, or moves to its parent if they're hidden
+func (v *TreeView) Collapse() {
+	node := v.SelectedNode()
+	if node == nil {
+		return
+	}
+	if !node.Expanded {
+		v.Parent()
+		return
+	}
+	v.tree.Collapse(node)
+	v.refresh()
+}
+
+// Toggle expands or collapses the selected node
+func (v *TreeView) Toggle() error {
+	node := v.SelectedNode()
+	if node == nil {
+		return nil
+	}
+	if node.Expanded {
+		v.tree.Collapse(node)
+		v.refresh()
+		return nil
+	}
+	return v.Expand()
+}
+
+// ExpandAll expands every node, and returns the number of modules whose requirements couldn't be read
+func (v *TreeView) ExpandAll() int {
+	failed := v.tree.ExpandAll()
+	v.refresh()
+	return failed
+}
+
+// Parent moves the cursor to the parent of the selected node
+func (v *TreeView) Parent() {
+	if node := v.SelectedNode(); node != nil && node.Parent != nil {
+		v.selectNode(node.Parent)
+	}
+}
+
+// selectNode moves the cursor to the node if it's visible
+func (v *TreeView) selectNode(node *deptree.Node) {
+	for i, n := range v.nodes {
+		if n == node {
+			v.cursor = i
+			v.scrollToCursor()
+			return
+		}
+	}
+}
+
+// refresh updates the visible nodes after expanding or collapsing, keeping the cursor on the same node
+func (v *TreeView) refresh() {
+	selected := v.SelectedNode()
+	v.nodes = v.tree.Visible()
+	v.cursor = min(v.cursor, max(len(v.nodes)-1, 0))
+	if selected != nil {
+		v.selectNode(selected)
+	}
+	v.scrollToCursor()
+}
+
+// bodyHeight returns the number of lines available for the nodes
+func (v *TreeView) bodyHeight() int {
+	// Reserve space for the title and help message
+	return max(v.height-4, 1)
+}
+
+// scrollToCursor adjusts the scroll offset so that the selected node is visible
+func (v *TreeView) scrollToCursor() {
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	}
+	if v.cursor >= v.offset+v.bodyHeight() {
+		v.offset = v.cursor - v.bodyHeight() + 1
+	}
+	v.offset = max(min(v.offset, len(v.nodes)-v.bodyHeight()), 0)
+}
+
+// nodeView renders a node with its expansion state and markers
+func (v *TreeView) nodeView(node *deptree.Node, selected bool) string {
+	indicator := "  "
+	switch {
+	case node.Expanded:
+		indicator = "▾ "
+	case node.Expandable():
+		indicator = "▸ "
+	}
+
+	label := strings.Repeat("  ", node.Depth()) + indicator + node.Package.String()
+	if selected {
+		label = v.styles.Selected.Render("> " + label)
+	} else {
+		label = v.styles.Node.Render("  " + label)
+	}
+	label += " " + v.styles.Version.Render(node.Package.Version)
+
+	if node.Package.Indirect {
+		label += v.styles.Version.Render(" [indirect]")
+	}
+	for _, flag := range node.Package.ParsedVersion().Flags() {
+		label += v.styles.Conflict.Render(" ⚑ " + flag)
+	}
+	if node.Conflict() {
+		label += v.styles.Conflict.Render(" ⚠ " + node.Selected + " selected")
+	}
+	if node.Duplicate {
+		label += v.styles.Duplicate.Render(" (shown elsewhere)")
+	}
+	if node.Err != nil {
+		label += v.styles.Error.Render(" ✗ requirements unavailable")
+	}
+	return label
+}
+
+// View renders the dependency tree screen
+func (v *TreeView) View() string {
+	title := v.styles.Title.Render(fmt.Sprintf("Dependency tree (%d shown)", len(v.nodes)))
+
+	var lines []string
+	end := min(v.offset+v.bodyHeight(), len(v.nodes))
+	for i := v.offset; i < end; i++ {
+		lines = append(lines, v.nodeView(v.nodes[i], i == v.cursor))
+	}
+	body := lipgloss.NewStyle().MaxWidth(v.width).Render(strings.Join(lines, "\n"))
+
+	return title + "\n\n" + body + "\n\n" + v.help.View(v.keyMap)
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tnagatomi/gh-lsmod/model"
+)
+
+// writeCachedGoMod writes the go.mod file of a module version to the download cache in GOMODCACHE
+func writeCachedGoMod(t *testing.T, path, version, content string) {
+	t.Helper()
+	dir := filepath.Join(os.Getenv("GOMODCACHE"), "cache", "download", filepath.FromSlash(path), "@v")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, version+".mod"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppTree(t *testing.T) {
+	withoutStatusDelay(t)
+	t.Setenv("GOMODCACHE", t.TempDir())
+	writeCachedGoMod(t, "github.com/charmbracelet/bubbles", "v0.20.0",
+		"module github.com/charmbracelet/bubbles\n\nrequire (\n\tgithub.com/charmbracelet/lipgloss v0.13.0\n\tgithub.com/sahilm/fuzzy v0.1.1\n)\n")
+	writeCachedGoMod(t, "github.com/charmbracelet/lipgloss", "v1.0.0",
+		"module github.com/charmbracelet/lipgloss\n\nrequire github.com/muesli/termenv v0.15.2\n")
+
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	lipgloss := model.NewPackage("github.com/charmbracelet/lipgloss", "v1.0.0")
+	mockClient := NewMockGitHubClient()
+	app := NewApp([]*model.Package{bubbles, lipgloss}, mockClient)
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if app.state != StateTree {
+		t.Fatalf("Expected the tree to be shown, got state %v", app.state)
+	}
+
+	// Expand bubbles and move to its first requirement
+	app.Update(tea.KeyMsg{Type: tea.KeyRight})
+	app.Update(tea.KeyMsg{Type: tea.KeyRight})
+	node := app.tree.SelectedNode()
+	if node == nil || node.Package.Path != lipgloss.Path || !node.Conflict() {
+		t.Fatalf("Expected lipgloss v0.13.0 with a conflict to be selected, got %+v", node)
+	}
+	if app.details.pkg != node.Package {
+		t.Error("Expected the details to show the selected node")
+	}
+
+	view := app.View()
+	for _, expected := range []string{"▾", "github.com/sahilm/fuzzy", "v1.0.0 selected"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected the tree to contain %q, got:\n%s", expected, view)
+		}
+	}
+
+	// Actions work on nested nodes
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	finishGitHubAction(t, app, cmd)
+	if !node.Package.IsStarred || !mockClient.starredRepos[lipgloss.Path] {
+		t.Error("Expected the nested module to be starred")
+	}
+
+	// Jump back to the parent, then expand everything
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	if got := app.tree.SelectedPackage(); got != bubbles {
+		t.Errorf("Expected the parent to be selected, got %v", got)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("E")})
+	view = app.View()
+	for _, expected := range []string{"github.com/muesli/termenv", "requirements unavailable"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected the expanded tree to contain %q, got:\n%s", expected, view)
+		}
+	}
+	if errors := app.statusBar.Errors(); len(errors) != 0 {
+		t.Errorf("Expected missing go.mod files to be a warning, got errors %v", errors)
+	}
+
+	// Collapsing the parent hides its requirements
+	app.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if app.tree.SelectedNode().Expanded {
+		t.Error("Expected bubbles to be collapsed")
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.state != StateList {
+		t.Errorf("Expected to be back to the list, got state %v", app.state)
+	}
+}
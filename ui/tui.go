@@ -1,10 +1,17 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/cli/browser"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tnagatomi/gh-lsmod/deptree"
+	"github.com/tnagatomi/gh-lsmod/filter"
 	"github.com/tnagatomi/gh-lsmod/github"
+	"github.com/tnagatomi/gh-lsmod/metadata"
 	"github.com/tnagatomi/gh-lsmod/model"
 )
 
@@ -13,51 +20,151 @@ type State int
 
 const (
 	StateList State = iota
-	StateDialog
+	StateReleases
+	StateSponsors
+	StateErrorLog
+	StateFilter
+	StateTree
+	StateReadme
+	StateSource
+	StateDetails
 )
 
 // Layout constants
 const (
-	DetailViewHeight = 10
+	DetailViewHeight = 13
 	HelpViewHeight   = 3
 	MinListHeight    = 5
+	StatusBarHeight  = 1
 )
 
+// Options configures the TUI application
+type Options struct {
+	Offline       bool                         // Whether GitHub data is served only from the cache
+	ModuleStatus  metadata.ModuleStatusChecker // Looks up the latest version of each module after startup
+	StartupErrors []error                      // Errors that occurred before the TUI started, shown in the status line
+	SavedFilters  *filter.Saved                // Filters that queries can refer to as @name, kept in memory if nil
+	Requirements  []*model.Package             // Every module in go.mod, to point out version conflicts in the dependency tree
+	Settings      *Settings                    // Key bindings, theme and fields from the config file, the defaults if nil
+}
+
 // App represents the TUI application
 type App struct {
-	packages     []*model.Package
-	list         *PackageList
-	details      *PackageDetails
-	state        State
-	githubClient github.GitHubClient
-	dialog       *Dialog
-	width        int
-	height       int
+	packages      []*model.Package
+	list          *PackageList
+	details       *PackageDetails
+	state         State
+	githubClient  github.GitHubClient
+	moduleStatus  metadata.ModuleStatusChecker
+	loader        *Loader
+	statusBar     *StatusBar
+	errorLog      *ErrorLog
+	filterBar     *FilterBar
+	tree          *TreeView
+	readme        *ReadmeView
+	source        *SourceBrowser
+	requirements  []*model.Package
+	keys          KeyMaps // Key bindings of the screens opened from the list
+	detailsHidden bool    // Whether the details pane is toggled off
+	detailsWidth  int     // Percentage of the width taken by the details on wide terminals
+	detailsHeight int     // Preferred height of the details below the list
+	detailsReturn State   // State to go back to from the maximized details
+	failedURL     string  // Last URL the browser couldn't open, to be copied instead
+	startupErrs   []error
+	modal         *Dialog   // Dialog shown over the current screen, which gets every key while it's open
+	queued        []*Dialog // Dialogs opened while another one was shown, in order
+	starLists     *Dialog   // Dialog shown while the star lists load, replaced by the picker if it's still open
+	bulkStar      *BulkStarRun
+	releaseNotes  *ReleaseNotes
+	sponsors      *SponsorsView
+	width         int
+	height        int
 }
 
 // NewApp creates a new TUI application
 func NewApp(packages []*model.Package, githubClient github.GitHubClient) *App {
+	return NewAppWithOptions(packages, githubClient, Options{})
+}
+
+// NewAppWithOptions creates a new TUI application with the given options
+func NewAppWithOptions(packages []*model.Package, githubClient github.GitHubClient, opts Options) *App {
+	// The theme has to be set before any style is created
+	settings := opts.Settings
+	if settings != nil {
+		SetTheme(settings.Theme)
+	} else {
+		settings = DefaultSettings()
+	}
+
 	list := NewPackageList(packages)
+	list.keyMap = settings.Keys.List
+	list.SetDescriptionFields(settings.Description)
 	details := NewPackageDetails()
+	details.SetFields(settings.Details)
+
+	// Star and watch actions need the network, so they're unavailable offline
+	if opts.Offline {
+		list.keyMap.ToggleStar.SetEnabled(false)
+		list.keyMap.StarAll.SetEnabled(false)
+		list.keyMap.StarLists.SetEnabled(false)
+		list.keyMap.ToggleWatch.SetEnabled(false)
+		list.keyMap.WatchAll.SetEnabled(false)
+		list.SetTitle(list.title + " (offline)")
+	}
+
+	// Without a GitHub client, only the module data is shown
+	if githubClient == nil {
+		list.keyMap.ToggleStar.SetEnabled(false)
+		list.keyMap.StarAll.SetEnabled(false)
+		list.keyMap.StarLists.SetEnabled(false)
+		list.keyMap.ToggleWatch.SetEnabled(false)
+		list.keyMap.WatchAll.SetEnabled(false)
+		list.keyMap.ReleaseNotes.SetEnabled(false)
+	}
+
+	loader := NewLoader()
+	list.SetLoader(loader)
+
+	savedFilters := opts.SavedFilters
+	if savedFilters == nil {
+		savedFilters = filter.NewSaved("")
+	}
 
 	if len(packages) > 0 {
 		details.SetPackage(packages[0])
 	}
 
+	filterBar := NewFilterBar(savedFilters)
+	filterBar.keyMap = settings.Keys.FilterBar
+
 	return &App{
-		packages:     packages,
-		list:         list,
-		details:      details,
-		state:        StateList,
-		githubClient: githubClient,
-		width:        80,
-		height:       24,
+		packages:      packages,
+		list:          list,
+		details:       details,
+		state:         StateList,
+		githubClient:  githubClient,
+		moduleStatus:  opts.ModuleStatus,
+		loader:        loader,
+		statusBar:     NewStatusBar(),
+		filterBar:     filterBar,
+		requirements:  opts.Requirements,
+		keys:          settings.Keys,
+		detailsWidth:  DefaultDetailsWidth,
+		detailsHeight: DetailViewHeight,
+		startupErrs:   opts.StartupErrors,
+		width:         80,
+		height:        24,
 	}
 }
 
-// Init initializes the TUI application
+// Init initializes the TUI application.
+// Sizes, module status and GitHub metadata are loaded in the background while the list is shown.
 func (a *App) Init() tea.Cmd {
-	return nil
+	cmds := []tea.Cmd{a.loader.Start(a.packages, a.moduleStatus, a.githubClient)}
+	for _, err := range a.startupErrs {
+		cmds = append(cmds, a.statusBar.Error(err))
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update handles user input and updates the application state
@@ -69,19 +176,141 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.width = msg.Width
 		a.height = msg.Height
 		a.updateComponentSizes()
+		if a.modal != nil {
+			a.modal.SetSize(a.width, a.height)
+		}
 
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, a.list.keyMap.Quit):
+		case msg.String() == "ctrl+c":
+			return a, tea.Quit
+		case a.modal != nil:
+			cmd := a.modal.Update(msg)
+			if a.modal.Closed() {
+				a.modal = nil
+				if len(a.queued) > 0 {
+					a.modal, a.queued = a.queued[0], a.queued[1:]
+					a.modal.SetSize(a.width, a.height)
+				}
+			}
+			return a, cmd
+		case a.state == StateList && key.Matches(msg, a.list.keyMap.Quit):
 			return a, tea.Quit
 		}
+
+	case sizeLoadedMsg, moduleStatusLoadedMsg, repositoriesLoadedMsg, spinner.TickMsg:
+		// The status line shrinks once loading finishes, so the list can grow
+		cmd, err := a.loader.Update(msg)
+		if err != nil {
+			cmd = tea.Batch(cmd, a.statusBar.Error(err))
+		}
+
+		// Loaded metadata can change which packages match the filter and how they're sorted
+		if _, tick := msg.(spinner.TickMsg); !tick && (a.list.Filtered() || a.list.SortOrder().Key != SortNone) {
+			a.list.Refresh()
+			a.details.SetPackage(a.list.SelectedPackage())
+		}
+		a.updateComponentSizes()
+		return a, cmd
+
+	case statusExpiredMsg:
+		a.statusBar.Expire(msg)
+		return a, nil
+
+	case readmeMsg:
+		if a.readme != nil && a.readme.pkg == msg.pkg {
+			a.readme.SetReadme(msg.name, msg.source, msg.content, msg.err)
+		}
+		return a, nil
+
+	case sourceSearchMsg:
+		if a.source != nil {
+			a.source.SetMatches(msg)
+		}
+		return a, nil
+
+	case copyMsg:
+		return a, a.copyPackages(msg.format, msg.packages)
+
+	case watchAllConfirmedMsg:
+		githubClient := a.githubClient
+		return a, runGitHubAction(statusWatch, a.packages, func(packages []*model.Package) (string, error) {
+			count, err := githubClient.WatchAllUnwatched(packages)
+			return bulkResult(fmt.Sprintf("Watching %d more repositories", count), err), err
+		})
+
+	case githubActionMsg:
+		a.applyGitHubAction(msg)
+		return a, a.showResult(msg.message, msg.err)
+
+	case bulkActionMsg:
+		return a, a.runBulkAction(msg.action, msg.packages)
+
+	case editorFinishedMsg:
+		if msg.err != nil {
+			return a, a.statusBar.Error(msg.err)
+		}
+		return a, nil
+
+	case releasesMsg:
+		if a.releaseNotes != nil && a.releaseNotes.pkg == msg.pkg {
+			a.releaseNotes.SetReleases(msg.releases, msg.err)
+		}
+		return a, nil
+
+	case starListsMsg:
+		// The lists are only shown if the loading dialog hasn't been cancelled
+		if a.starLists == nil || a.modal != a.starLists {
+			return a, nil
+		}
+		a.modal, a.starLists = nil, nil
+		if msg.err != nil {
+			return a, a.statusBar.Error(msg.err)
+		}
+		a.openDialog(newStarListPicker(msg.pkg, msg.lists), a.keys.StarLists)
+		return a, nil
+
+	case starListsChosenMsg:
+		return a, updateStarLists(a.githubClient, msg)
+
+	case bulkStarChosenMsg:
+		a.bulkStar = NewBulkStarRun(msg.items)
+		a.updateComponentSizes()
+		return a, a.bulkStar.Next(a.githubClient)
+
+	case bulkStarResultMsg:
+		a.applyGitHubAction(msg.star)
+		if a.bulkStar == nil {
+			return a, nil
+		}
+		cmd := a.bulkStar.Update(msg, a.githubClient)
+		if a.bulkStar.Done() {
+			a.openDialog(newBulkStarSummary(a.bulkStar.items), a.keys.BulkStar)
+			a.bulkStar = nil
+			a.updateComponentSizes()
+		}
+		return a, cmd
 	}
 
 	switch a.state {
 	case StateList:
 		return a.updateList(msg)
-	case StateDialog:
-		return a.updateDialog(msg)
+	case StateReleases:
+		return a.updateReleases(msg)
+	case StateSponsors:
+		return a.updateSponsors(msg)
+	case StateErrorLog:
+		return a.updateErrorLog(msg)
+	case StateFilter:
+		return a.updateFilter(msg)
+	case StateTree:
+		return a.updateTree(msg)
+	case StateReadme:
+		return a.updateReadme(msg)
+	case StateDetails:
+		return a.updateDetails(msg)
+	case StateSource:
+		return a.updateSource(msg)
 	}
 
 	return a, cmd
@@ -89,13 +318,211 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // updateComponentSizes updates the sizes of all components
 func (a *App) updateComponentSizes() {
-	// Reserve space for details and help message
-	listHeight := a.height - DetailViewHeight - HelpViewHeight
-	if listHeight < MinListHeight {
-		listHeight = MinListHeight // Ensure minimum list height
+	// Reserve space for the filter bar, the status line and help message, which grows when all keys are shown
+	l := a.layout(a.height - a.statusHeight() - a.filterHeight())
+	a.list.SetSize(l.mainWidth, max(l.mainHeight-HelpViewHeight-(a.list.HelpHeight()-1), MinListHeight))
+	switch a.state {
+	case StateDetails:
+		// The help takes a line below the maximized details
+		a.details.SetSize(a.width, a.height-StatusBarHeight-1)
+	case StateTree:
+		treeLayout := a.layout(a.height - StatusBarHeight)
+		a.details.SetSize(treeLayout.detailsWidth, treeLayout.detailsHeight)
+	default:
+		a.details.SetSize(l.detailsWidth, l.detailsHeight)
+	}
+	a.statusBar.SetWidth(a.width)
+	a.filterBar.SetWidth(a.width)
+	if a.releaseNotes != nil {
+		a.releaseNotes.SetSize(a.width, a.height-StatusBarHeight)
+	}
+	if a.sponsors != nil {
+		a.sponsors.SetSize(a.width, a.height-StatusBarHeight)
+	}
+	if a.errorLog != nil {
+		a.errorLog.SetSize(a.width, a.height-StatusBarHeight)
+	}
+	if a.readme != nil {
+		a.readme.SetSize(a.width, a.height-StatusBarHeight)
+	}
+	if a.source != nil {
+		a.source.SetSize(a.width, a.height-StatusBarHeight)
+	}
+	if a.tree != nil {
+		treeLayout := a.layout(a.height - StatusBarHeight)
+		a.tree.SetSize(treeLayout.mainWidth, treeLayout.mainHeight)
+	}
+}
+
+// statusHeight returns the height of the progress and the status bar below the list
+func (a *App) statusHeight() int {
+	if progress := a.progressView(); progress != "" {
+		return lipgloss.Height(progress) + StatusBarHeight
+	}
+	return StatusBarHeight
+}
+
+// progressView renders the loading progress and the progress of the stars being applied, empty if there's neither
+func (a *App) progressView() string {
+	var lines []string
+	if progress := a.loader.View(); progress != "" {
+		lines = append(lines, progress)
+	}
+	if a.bulkStar != nil {
+		lines = append(lines, a.bulkStar.View())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// filterHeight returns the height of the filter bar, which is only shown while the filter is edited
+func (a *App) filterHeight() int {
+	if a.state != StateFilter {
+		return 0
 	}
-	a.list.SetSize(a.width, listHeight)
-	a.details.SetSize(a.width, DetailViewHeight)
+	return lipgloss.Height(a.filterBar.View())
+}
+
+// openURL opens the URL in the browser, keeping it to be copied if the browser can't be opened
+func (a *App) openURL(url string) tea.Cmd {
+	if err := openBrowser(url); err != nil {
+		a.failedURL = url
+		return a.statusBar.Error(fmt.Errorf("failed to open the browser, press u to copy %s: %w", url, err))
+	}
+	return a.statusBar.Success("Opened " + url)
+}
+
+// copyURL copies the URL the browser couldn't open, or the page of the package
+func (a *App) copyURL(pkg *model.Package) tea.Cmd {
+	url := a.failedURL
+	if url == "" {
+		if pkg == nil {
+			return nil
+		}
+		url = pkg.GitHubURL()
+		if url == "" {
+			url = pkg.PkgGoDevURL()
+		}
+	}
+
+	if err := copyToClipboard(url); err != nil {
+		return a.statusBar.Error(fmt.Errorf("failed to copy %s: %w", url, err))
+	}
+	a.failedURL = ""
+	return a.statusBar.Success("Copied " + url)
+}
+
+// openGitHub opens the GitHub repository of the package in the browser
+func (a *App) openGitHub(pkg *model.Package) tea.Cmd {
+	if pkg == nil || !pkg.IsGitHub {
+		return nil
+	}
+	url := pkg.GitHubURL()
+	if url == "" {
+		return nil
+	}
+	return a.openURL(url)
+}
+
+// openPkgGoDev opens the pkg.go.dev page of the package in the browser
+func (a *App) openPkgGoDev(pkg *model.Package) tea.Cmd {
+	if pkg == nil {
+		return nil
+	}
+	return a.openURL(pkg.PkgGoDevURL())
+}
+
+// openCompare opens the GitHub compare view between the pinned and the latest version in the browser
+func (a *App) openCompare(pkg *model.Package) tea.Cmd {
+	if pkg == nil {
+		return nil
+	}
+	url := pkg.CompareURL()
+	if url == "" {
+		return a.statusBar.Warning("No newer version of " + pkg.Path + " to compare with on GitHub")
+	}
+	return a.openURL(url)
+}
+
+// toggleStar stars the repository of the package, or unstars it if it's starred.
+// GitHub is called in the background, and every module in the repository shows the new status.
+func (a *App) toggleStar(pkg *model.Package) tea.Cmd {
+	if pkg == nil || !pkg.IsGitHub {
+		return nil
+	}
+	githubClient := a.githubClient
+	repoPath := pkg.GitHubRepoPath()
+	if pkg.IsStarred {
+		return runGitHubAction(statusStar, []*model.Package{pkg}, func(packages []*model.Package) (string, error) {
+			if err := githubClient.UnstarRepository(packages[0]); err != nil {
+				return "", err
+			}
+			return "Unstarred " + repoPath, nil
+		})
+	}
+	return runGitHubAction(statusStar, []*model.Package{pkg}, func(packages []*model.Package) (string, error) {
+		if err := githubClient.StarRepository(packages[0]); err != nil {
+			return "", err
+		}
+		return "Starred " + repoPath, nil
+	})
+}
+
+// toggleWatch subscribes to the notifications of the package's repository, or unsubscribes if watching.
+// GitHub is called in the background, and every module in the repository shows the new status.
+func (a *App) toggleWatch(pkg *model.Package) tea.Cmd {
+	if pkg == nil || !pkg.IsGitHub {
+		return nil
+	}
+	githubClient := a.githubClient
+	repoPath := pkg.GitHubRepoPath()
+	if pkg.IsWatched {
+		return runGitHubAction(statusWatch, []*model.Package{pkg}, func(packages []*model.Package) (string, error) {
+			if err := githubClient.UnwatchRepository(packages[0]); err != nil {
+				return "", err
+			}
+			return "Stopped watching " + repoPath, nil
+		})
+	}
+	return runGitHubAction(statusWatch, []*model.Package{pkg}, func(packages []*model.Package) (string, error) {
+		if err := githubClient.WatchRepository(packages[0]); err != nil {
+			return "", err
+		}
+		return "Watching " + repoPath, nil
+	})
+}
+
+// applyGitHubAction applies the status changed by a star or watch action to the packages
+// and the other modules in their repositories. Only what the action changed is applied,
+// so that changes made while it was running are kept.
+func (a *App) applyGitHubAction(msg githubActionMsg) {
+	for i, pkg := range msg.packages {
+		original, fetched := msg.original[i], msg.fetched[i]
+		for _, p := range a.repositoryPackages(pkg) {
+			switch msg.status {
+			case statusStar:
+				if fetched.IsStarred != original.IsStarred {
+					p.IsStarred = fetched.IsStarred
+				}
+			case statusWatch:
+				if fetched.IsWatched != original.IsWatched || fetched.IsIgnored != original.IsIgnored {
+					p.IsWatched = fetched.IsWatched
+					p.IsIgnored = fetched.IsIgnored
+				}
+			}
+		}
+	}
+}
+
+// repositoryPackages returns the package and the other listed modules in its GitHub repository
+func (a *App) repositoryPackages(pkg *model.Package) []*model.Package {
+	packages := []*model.Package{pkg}
+	repoPath := pkg.GitHubRepoPath()
+	for _, p := range a.packages {
+		if p != pkg && p.IsGitHub && p.GitHubRepoPath() == repoPath {
+			packages = append(packages, p)
+		}
+	}
+	return packages
 }
 
 // updateList handles user input in the list view
@@ -104,50 +531,234 @@ func (a *App) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if a.updateLayout(msg) {
+			return a, nil
+		}
 		switch {
 		case key.Matches(msg, a.list.keyMap.OpenGitHub):
 			// Open GitHub repository in browser
-			pkg := a.list.SelectedPackage()
-			if pkg != nil && pkg.IsGitHub {
-				url := pkg.GitHubURL()
-				if url != "" {
-					_ = browser.OpenURL(url)
-				}
+			if cmd := a.openGitHub(a.list.SelectedPackage()); cmd != nil {
+				return a, cmd
 			}
 
 		case key.Matches(msg, a.list.keyMap.OpenPkgGoDev):
 			// Open pkg.go.dev page in browser
-			pkg := a.list.SelectedPackage()
-			if pkg != nil {
-				url := pkg.PkgGoDevURL()
-				_ = browser.OpenURL(url)
+			if cmd := a.openPkgGoDev(a.list.SelectedPackage()); cmd != nil {
+				return a, cmd
+			}
+
+		case key.Matches(msg, a.list.keyMap.OpenCompare):
+			return a, a.openCompare(a.list.SelectedPackage())
+
+		case key.Matches(msg, a.list.keyMap.ClearFilter):
+			a.list.SetFilter("", nil)
+			a.details.SetPackage(a.list.SelectedPackage())
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.Filter):
+			// Edit the filter query in the filter bar
+			a.state = StateFilter
+			cmd := a.filterBar.Start(a.list.Filter())
+			a.updateComponentSizes()
+			return a, cmd
+
+		case key.Matches(msg, a.list.keyMap.Sort):
+			a.list.SetSortOrder(a.list.SortOrder().NextKey())
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.SortThen):
+			a.list.SetSortOrder(a.list.SortOrder().NextSecondary())
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.Reverse):
+			a.list.SetSortOrder(a.list.SortOrder().Reverse())
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.ReverseThen):
+			a.list.SetSortOrder(a.list.SortOrder().ReverseSecondary())
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.CopyURL):
+			return a, a.copyURL(a.list.SelectedPackage())
+
+		case key.Matches(msg, a.list.keyMap.Copy):
+			// Copy the selection, or the package under the cursor if nothing is selected
+			selection := a.list.Selection()
+			if len(selection) == 0 {
+				if pkg := a.list.SelectedPackage(); pkg != nil {
+					selection = []*model.Package{pkg}
+				}
+			}
+			if len(selection) > 0 {
+				a.openCopyMenu(selection)
 			}
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.ErrorLog):
+			// Show the errors that occurred this session
+			a.errorLog = NewErrorLog(a.statusBar.Errors())
+			a.errorLog.keyMap = a.keys.ErrorLog
+			a.errorLog.SetSize(a.width, a.height-StatusBarHeight)
+			a.state = StateErrorLog
+			return a, nil
 
 		case key.Matches(msg, a.list.keyMap.ToggleStar):
 			// Toggle star status
+			if cmd := a.toggleStar(a.list.SelectedPackage()); cmd != nil {
+				return a, cmd
+			}
+
+		case key.Matches(msg, a.list.keyMap.ReleaseNotes):
+			// Show release notes between the pinned and the latest version
 			pkg := a.list.SelectedPackage()
 			if pkg != nil && pkg.IsGitHub {
-				if pkg.IsStarred {
-					_ = a.githubClient.UnstarRepository(pkg)
-				} else {
-					_ = a.githubClient.StarRepository(pkg)
+				a.releaseNotes = NewReleaseNotes(pkg)
+				a.releaseNotes.keyMap = a.keys.ReleaseNotes
+				a.releaseNotes.SetSize(a.width, a.height-StatusBarHeight)
+				a.state = StateReleases
+				return a, fetchReleases(a.githubClient, pkg)
+			}
+
+		case key.Matches(msg, a.list.keyMap.Readme):
+			// Show the README at the pinned version
+			pkg := a.list.SelectedPackage()
+			if pkg != nil {
+				a.readme = NewReadmeView(pkg)
+				a.readme.SetPagerKeyMap(a.keys.Pager)
+				a.readme.SetSize(a.width, a.height-StatusBarHeight)
+				a.state = StateReadme
+				return a, loadReadme(a.githubClient, pkg)
+			}
+
+		case key.Matches(msg, a.list.keyMap.Source):
+			// Browse the source of the module at the pinned version
+			pkg := a.list.SelectedPackage()
+			if pkg != nil {
+				a.source = NewSourceBrowser(pkg)
+				a.source.SetKeyMap(a.keys.Source)
+				a.source.SetSize(a.width, a.height-StatusBarHeight)
+				a.state = StateSource
+				return a, nil
+			}
+
+		case key.Matches(msg, a.list.keyMap.Sponsors):
+			// Show the maintainers that accept funding, grouped with their dependencies
+			a.sponsors = NewSponsorsView(a.packages)
+			a.sponsors.keyMap = a.keys.Sponsors
+			a.sponsors.SetSize(a.width, a.height-StatusBarHeight)
+			a.state = StateSponsors
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.Select):
+			// Select the package and move on to the next one
+			pkg := a.list.SelectedPackage()
+			if pkg != nil {
+				a.list.ToggleSelected(pkg)
+				a.list.list.CursorDown()
+				if next := a.list.SelectedPackage(); next != nil {
+					a.details.SetPackage(next)
+				}
+			}
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.SelectAll):
+			a.list.SelectAll()
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.Invert):
+			a.list.InvertSelection()
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.ClearSelect) && len(a.list.Selection()) > 0:
+			a.list.ClearSelection()
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.Actions):
+			// Run actions on the selection, or on the package under the cursor if nothing is selected
+			selection := a.list.Selection()
+			if len(selection) == 0 {
+				if pkg := a.list.SelectedPackage(); pkg != nil {
+					selection = []*model.Package{pkg}
 				}
 			}
+			if len(selection) > 0 {
+				a.openDialog(newBulkActionMenu(selection), a.keys.Actions)
+			}
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.Help):
+			a.list.ToggleHelp()
+			a.updateComponentSizes()
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.StarLists):
+			// Show the star lists to add the repository to
+			pkg := a.list.SelectedPackage()
+			if pkg != nil && pkg.IsGitHub {
+				a.starLists = newStarListsLoading(pkg)
+				a.openDialog(a.starLists, a.keys.StarLists)
+				return a, fetchStarLists(a.githubClient, pkg)
+			}
 
 		case key.Matches(msg, a.list.keyMap.StarAll):
-			// Show confirmation dialog for starring all unstarred repositories
-			unstarredCount := 0
+			// Show the unstarred repositories to choose which ones to star, unless they're being starred
+			if items := bulkStarItems(a.packages); len(items) > 0 && a.bulkStar == nil {
+				a.openDialog(newBulkStarDialog(items), a.keys.BulkStar)
+				return a, nil
+			}
+
+		case key.Matches(msg, a.list.keyMap.ToggleWatch):
+			// Toggle the subscription to the repository's notifications
+			if cmd := a.toggleWatch(a.list.SelectedPackage()); cmd != nil {
+				return a, cmd
+			}
+
+		case key.Matches(msg, a.list.keyMap.Tree):
+			// Show the dependency tree, keeping what was expanded the last time
+			if a.tree == nil {
+				requirements := a.requirements
+				if requirements == nil {
+					requirements = a.packages
+				}
+				a.tree = NewTreeView(deptree.New(a.packages, requirements))
+				a.tree.keyMap = a.keys.Tree
+				a.tree.keyMap.Actions = []key.Binding{
+					a.list.keyMap.OpenGitHub, a.list.keyMap.OpenPkgGoDev, a.list.keyMap.OpenCompare, a.list.keyMap.ToggleStar,
+					a.list.keyMap.ToggleWatch, a.list.keyMap.CopyURL, a.list.keyMap.Copy,
+				}
+			}
+			a.details.SetPackage(a.tree.SelectedPackage())
+			a.state = StateTree
+			a.updateComponentSizes()
+			return a, nil
+
+		case key.Matches(msg, a.list.keyMap.WatchAll):
+			// Show confirmation dialog for watching all unwatched repositories
+			// Repositories the user chose to ignore are skipped
+			unwatchedCount, ignoredCount := 0, 0
 			for _, pkg := range a.packages {
-				if pkg.IsGitHub && !pkg.IsStarred {
-					unstarredCount++
+				switch {
+				case !pkg.IsGitHub || pkg.IsWatched:
+				case pkg.IsIgnored:
+					ignoredCount++
+				default:
+					unwatchedCount++
 				}
 			}
-			if unstarredCount > 0 {
-				a.dialog = NewDialog(
-					"Star all unstarred GitHub repositories?",
-					"This will add stars to "+string(rune(unstarredCount))+" repositories.",
-				)
-				a.state = StateDialog
+			if unwatchedCount > 0 {
+				message := fmt.Sprintf("You will get notifications for all activity in %d repositories.", unwatchedCount)
+				if ignoredCount > 0 {
+					message += fmt.Sprintf(" %d ignored repositories are left as they are.", ignoredCount)
+				}
+				a.openDialog(NewDialog(
+					"Watch all unwatched GitHub repositories?",
+					message,
+				).OnResult(func(result DialogResult) tea.Msg {
+					if !result.Confirmed() {
+						return nil
+					}
+					return watchAllConfirmedMsg{}
+				}), a.keys.Dialog)
 				return a, nil
 			}
 		}
@@ -165,40 +776,297 @@ func (a *App) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return a, cmd
 }
 
-// updateDialog handles user input in the dialog view
-func (a *App) updateDialog(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
+// watchAllConfirmedMsg is sent when watching all unwatched repositories is confirmed
+type watchAllConfirmedMsg struct{}
+
+// openDialog shows the dialog over the current screen until it's answered, with the key bindings.
+// The answer comes back as the message of the dialog, so the screen underneath stays as it is.
+// A dialog opened while another one is shown waits for it to be answered.
+func (a *App) openDialog(dialog *Dialog, keyMap DialogKeyMap) {
+	dialog.SetKeyMap(keyMap)
+	if a.modal != nil {
+		a.queued = append(a.queued, dialog)
+		return
+	}
+	dialog.SetSize(a.width, a.height)
+	a.modal = dialog
+}
+
+// updateReleases handles user input in the release notes view
+func (a *App) updateReleases(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, a.releaseNotes.keyMap.OpenCompare):
+			// Open the compare view between the pinned and the latest version
+			if url := a.releaseNotes.pkg.CompareURL(); url != "" {
+				return a, a.openURL(url)
+			}
+			return a, nil
+
+		case key.Matches(msg, a.releaseNotes.keyMap.Close):
+			a.state = StateList
+			a.releaseNotes = nil
+			return a, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	a.releaseNotes, cmd = a.releaseNotes.Update(msg)
+	return a, cmd
+}
+
+// updateReadme handles user input in the README view
+func (a *App) updateReadme(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && !a.readme.Searching() && key.Matches(msg, a.readme.keyMap.Close) {
+		a.state = StateList
+		a.readme = nil
+		return a, nil
+	}
+	return a, a.readme.Update(msg)
+}
+
+// updateSource handles user input in the source browser
+func (a *App) updateSource(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && !a.source.typing && !a.source.pager.Searching() {
 		switch {
-		case key.Matches(msg, a.dialog.keyMap.Confirm):
-			// Confirm dialog
-			_, _ = a.githubClient.StarAllUnstarred(a.packages)
+		case key.Matches(msg, a.source.keyMap.Close) && a.source.Closable():
 			a.state = StateList
-			a.dialog = nil
+			a.source = nil
+			return a, nil
+
+		case key.Matches(msg, a.source.keyMap.Editor):
+			// Open the file at the current line in the editor
+			if path, line := a.source.EditorTarget(); a.source.err == nil {
+				return a, openInEditor(path, line)
+			}
+			return a, nil
+
+		case key.Matches(msg, a.source.keyMap.GitHub):
+			// Open the file at the current line on GitHub at the pinned version
+			if url := a.source.GitHubURL(); url != "" {
+				return a, a.openURL(url)
+			}
+			return a, nil
+		}
+	}
+	return a, a.source.Update(msg)
+}
 
-		case key.Matches(msg, a.dialog.keyMap.Cancel):
-			// Cancel dialog
+// updateSponsors handles user input in the sponsors view
+func (a *App) updateSponsors(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, a.sponsors.keyMap.Up):
+			a.sponsors.MoveUp()
+
+		case key.Matches(msg, a.sponsors.keyMap.Down):
+			a.sponsors.MoveDown()
+
+		case key.Matches(msg, a.sponsors.keyMap.Open):
+			// Open the sponsor page in browser
+			if group := a.sponsors.SelectedGroup(); group != nil {
+				return a, a.openURL(group.Link.URL)
+			}
+
+		case key.Matches(msg, a.sponsors.keyMap.Close):
 			a.state = StateList
-			a.dialog = nil
+			a.sponsors = nil
 		}
 	}
 	return a, nil
 }
 
-// View renders the TUI
+// updateFilter handles user input in the filter bar.
+// The list is filtered as the query is typed, as long as it's valid.
+func (a *App) updateFilter(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return a, a.filterBar.Update(msg)
+	}
+
+	var cmd tea.Cmd
+	switch {
+	case a.filterBar.Naming() && key.Matches(keyMsg, a.filterBar.keyMap.Apply):
+		name, err := a.filterBar.SaveAs()
+		if err != nil {
+			cmd = a.statusBar.Error(err)
+		} else {
+			cmd = a.statusBar.Success("Saved filter @" + name)
+		}
+
+	case a.filterBar.Naming() && key.Matches(keyMsg, a.filterBar.keyMap.Cancel):
+		a.filterBar.StopNaming()
+
+	case a.filterBar.Naming():
+		cmd = a.filterBar.Update(keyMsg)
+
+	case key.Matches(keyMsg, a.filterBar.keyMap.Apply):
+		// An invalid query stays in the filter bar to be fixed
+		query, err := a.filterBar.Parse()
+		if err != nil {
+			return a, nil
+		}
+		a.list.SetFilter(a.filterBar.Value(), query)
+		a.filterBar.Stop()
+		a.state = StateList
+
+	case key.Matches(keyMsg, a.filterBar.keyMap.Cancel):
+		// Go back to the filter from before editing, which was valid when it was applied
+		query, _ := filter.Parse(a.filterBar.Previous(), a.filterBar.saved.Filters)
+		a.list.SetFilter(a.filterBar.Previous(), query)
+		a.filterBar.Stop()
+		a.state = StateList
+
+	case key.Matches(keyMsg, a.filterBar.keyMap.Save):
+		if _, err := a.filterBar.Parse(); err == nil && strings.TrimSpace(a.filterBar.Value()) != "" {
+			a.filterBar.StartNaming()
+		}
+
+	default:
+		cmd = a.filterBar.Update(keyMsg)
+		if query, err := a.filterBar.Parse(); err == nil {
+			a.list.SetFilter(a.filterBar.Value(), query)
+		}
+	}
+
+	a.details.SetPackage(a.list.SelectedPackage())
+	a.updateComponentSizes()
+	return a, cmd
+}
+
+// updateTree handles user input in the dependency tree.
+// The package actions of the list work on any node.
+func (a *App) updateTree(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return a, nil
+	}
+
+	if a.updateLayout(keyMsg) {
+		return a, nil
+	}
+
+	var cmd tea.Cmd
+	switch {
+	case key.Matches(keyMsg, a.tree.keyMap.Close):
+		a.state = StateList
+		a.details.SetPackage(a.list.SelectedPackage())
+		a.updateComponentSizes()
+		return a, nil
+
+	case key.Matches(keyMsg, a.tree.keyMap.Up):
+		a.tree.MoveUp()
+
+	case key.Matches(keyMsg, a.tree.keyMap.Down):
+		a.tree.MoveDown()
+
+	case key.Matches(keyMsg, a.tree.keyMap.Expand):
+		if err := a.tree.Expand(); err != nil {
+			cmd = a.statusBar.Error(err)
+		}
+
+	case key.Matches(keyMsg, a.tree.keyMap.Collapse):
+		a.tree.Collapse()
+
+	case key.Matches(keyMsg, a.tree.keyMap.Toggle):
+		if err := a.tree.Toggle(); err != nil {
+			cmd = a.statusBar.Error(err)
+		}
+
+	case key.Matches(keyMsg, a.tree.keyMap.ExpandAll):
+		if failed := a.tree.ExpandAll(); failed > 0 {
+			cmd = a.statusBar.Warning(fmt.Sprintf("Requirements of %d modules couldn't be read, run go mod download", failed))
+		}
+
+	case key.Matches(keyMsg, a.tree.keyMap.Parent):
+		a.tree.Parent()
+
+	case key.Matches(keyMsg, a.list.keyMap.OpenGitHub):
+		cmd = a.openGitHub(a.tree.SelectedPackage())
+
+	case key.Matches(keyMsg, a.list.keyMap.OpenPkgGoDev):
+		cmd = a.openPkgGoDev(a.tree.SelectedPackage())
+
+	case key.Matches(keyMsg, a.list.keyMap.OpenCompare):
+		cmd = a.openCompare(a.tree.SelectedPackage())
+
+	case key.Matches(keyMsg, a.list.keyMap.ToggleStar):
+		cmd = a.toggleStar(a.tree.SelectedPackage())
+
+	case key.Matches(keyMsg, a.list.keyMap.ToggleWatch):
+		cmd = a.toggleWatch(a.tree.SelectedPackage())
+
+	case key.Matches(keyMsg, a.list.keyMap.CopyURL):
+		cmd = a.copyURL(a.tree.SelectedPackage())
+
+	case key.Matches(keyMsg, a.list.keyMap.Copy):
+		if pkg := a.tree.SelectedPackage(); pkg != nil {
+			a.openCopyMenu([]*model.Package{pkg})
+		}
+	}
+
+	a.details.SetPackage(a.tree.SelectedPackage())
+	return a, cmd
+}
+
+// updateErrorLog handles user input in the error log
+func (a *App) updateErrorLog(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, a.errorLog.keyMap.Close) {
+		a.state = StateList
+		a.errorLog = nil
+		return a, nil
+	}
+
+	var cmd tea.Cmd
+	a.errorLog, cmd = a.errorLog.Update(msg)
+	return a, cmd
+}
+
+// View renders the TUI, with the open dialog centered over the current screen
 func (a *App) View() string {
+	view := a.screenView()
+	if a.modal != nil {
+		view = placeOverlay(view, a.modal.View(), a.width, a.height)
+	}
+	return view
+}
+
+// screenView renders the screen of the current state
+func (a *App) screenView() string {
+	var view string
 	switch a.state {
-	case StateList:
-		return a.list.View() + "\n" + a.details.View()
-	case StateDialog:
-		return a.dialog.View()
+	case StateList, StateFilter:
+		// The filter bar, the progress and the status bar sit below the list
+		if a.state == StateFilter {
+			view += a.filterBar.View() + "\n"
+		}
+		if progress := a.progressView(); progress != "" {
+			view += progress + "\n"
+		}
+		view += a.statusBar.View()
+		return a.joinPanes(a.layout(a.height-a.statusHeight()-a.filterHeight()), a.list.View(), view)
+	case StateTree:
+		// The details of the selected node are shown next to the tree
+		return a.joinPanes(a.layout(a.height-StatusBarHeight), a.tree.View(), a.statusBar.View())
+	case StateDetails:
+		view = a.detailsView()
+	case StateReleases:
+		view = a.releaseNotes.View()
+	case StateReadme:
+		view = a.readme.View()
+	case StateSource:
+		view = a.source.View()
+	case StateSponsors:
+		view = a.sponsors.View()
+	case StateErrorLog:
+		view = a.errorLog.View()
 	}
-	return ""
+	return view + "\n" + a.statusBar.View()
 }
 
 // Run runs the TUI application
-func Run(packages []*model.Package, githubClient *github.Client) error {
-	app := NewApp(packages, githubClient)
+func Run(packages []*model.Package, githubClient github.GitHubClient, opts Options) error {
+	app := NewAppWithOptions(packages, githubClient, opts)
 	p := tea.NewProgram(app, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
@@ -1,23 +1,36 @@
 package ui
 
 import (
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tnagatomi/gh-lsmod/github"
 	"github.com/tnagatomi/gh-lsmod/model"
 )
 
 // MockGitHubClient is a mock implementation of the github.GitHubClient interface
 type MockGitHubClient struct {
-	starredRepos     map[string]bool
-	starCallCount    int
-	unstarCallCount  int
-	starAllCallCount int
+	mu                sync.Mutex
+	starredRepos      map[string]bool
+	failingRepos      map[string]error
+	starCallCount     int
+	unstarCallCount   int
+	bulkStarCallCount int
+	watchedRepos      map[string]bool
+	releases          []github.Release
+	readme            *github.Readme
+	starLists         []github.StarList
 }
 
 // NewMockGitHubClient creates a new mock GitHub client
 func NewMockGitHubClient() *MockGitHubClient {
 	return &MockGitHubClient{
 		starredRepos: make(map[string]bool),
+		failingRepos: make(map[string]error),
+		watchedRepos: make(map[string]bool),
 	}
 }
 
@@ -31,9 +44,19 @@ func (m *MockGitHubClient) CheckStarredStatus(packages []*model.Package) error {
 	return nil
 }
 
+// FetchRepositories mocks fetching repository metadata and starred status
+func (m *MockGitHubClient) FetchRepositories(packages []*model.Package) error {
+	return m.CheckStarredStatus(packages)
+}
+
 // StarRepository mocks starring a repository
 func (m *MockGitHubClient) StarRepository(pkg *model.Package) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.starCallCount++
+	if err := m.failingRepos[pkg.Path]; err != nil {
+		return err
+	}
 	m.starredRepos[pkg.Path] = true
 	pkg.IsStarred = true
 	return nil
@@ -47,20 +70,135 @@ func (m *MockGitHubClient) UnstarRepository(pkg *model.Package) error {
 	return nil
 }
 
-// StarAllUnstarred mocks starring all unstarred repositories
-func (m *MockGitHubClient) StarAllUnstarred(packages []*model.Package) (int, error) {
-	m.starAllCallCount++
+// StarRepositories mocks starring the unstarred repositories of the packages
+func (m *MockGitHubClient) StarRepositories(packages []*model.Package) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bulkStarCallCount++
 	count := 0
+	var errs github.PackageErrors
 	for _, pkg := range packages {
 		if pkg.IsGitHub && !pkg.IsStarred {
+			if err := m.failingRepos[pkg.Path]; err != nil {
+				errs = append(errs, &github.PackageError{Package: pkg, Err: err})
+				continue
+			}
 			m.starredRepos[pkg.Path] = true
 			pkg.IsStarred = true
 			count++
 		}
 	}
+	if len(errs) > 0 {
+		return count, errs
+	}
+	return count, nil
+}
+
+// UnstarRepositories mocks unstarring the starred repositories of the packages
+func (m *MockGitHubClient) UnstarRepositories(packages []*model.Package) (int, error) {
+	count := 0
+	for _, pkg := range packages {
+		if pkg.IsGitHub && pkg.IsStarred {
+			m.unstarCallCount++
+			delete(m.starredRepos, pkg.Path)
+			pkg.IsStarred = false
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ListReleases mocks listing the releases of a repository
+func (m *MockGitHubClient) ListReleases(pkg *model.Package) ([]github.Release, error) {
+	return m.releases, nil
+}
+
+// FetchReadme mocks fetching the README of a repository
+func (m *MockGitHubClient) FetchReadme(pkg *model.Package) (*github.Readme, error) {
+	if m.readme == nil {
+		return nil, github.ErrNoReadme
+	}
+	return m.readme, nil
+}
+
+// WatchRepository mocks watching a repository
+func (m *MockGitHubClient) WatchRepository(pkg *model.Package) error {
+	m.watchedRepos[pkg.Path] = true
+	pkg.IsWatched = true
+	return nil
+}
+
+// UnwatchRepository mocks unwatching a repository
+func (m *MockGitHubClient) UnwatchRepository(pkg *model.Package) error {
+	delete(m.watchedRepos, pkg.Path)
+	pkg.IsWatched = false
+	return nil
+}
+
+// WatchAllUnwatched mocks watching all unwatched repositories
+func (m *MockGitHubClient) WatchAllUnwatched(packages []*model.Package) (int, error) {
+	count := 0
+	for _, pkg := range packages {
+		if pkg.IsGitHub && !pkg.IsWatched && !pkg.IsIgnored {
+			m.watchedRepos[pkg.Path] = true
+			pkg.IsWatched = true
+			count++
+		}
+	}
 	return count, nil
 }
 
+// ListStarLists mocks listing the user's star lists
+func (m *MockGitHubClient) ListStarLists() ([]github.StarList, error) {
+	return m.starLists, nil
+}
+
+// CreateStarList mocks creating a star list
+func (m *MockGitHubClient) CreateStarList(name, description string, private bool) (*github.StarList, error) {
+	list := github.StarList{ID: "list-" + name, Name: name, Description: description, IsPrivate: private}
+	m.starLists = append(m.starLists, list)
+	return &list, nil
+}
+
+// AddToStarList mocks adding repositories to a star list
+func (m *MockGitHubClient) AddToStarList(list *github.StarList, packages []*model.Package) error {
+	for _, pkg := range packages {
+		list.Repositories = append(list.Repositories, pkg.GitHubRepoPath())
+		pkg.IsStarred = true
+	}
+	m.saveStarList(*list)
+	return nil
+}
+
+// RemoveFromStarList mocks removing repositories from a star list
+func (m *MockGitHubClient) RemoveFromStarList(list *github.StarList, packages []*model.Package) error {
+	list.Repositories = nil
+	m.saveStarList(*list)
+	return nil
+}
+
+// saveStarList replaces the list with the same ID, as GitHub would return it next time
+func (m *MockGitHubClient) saveStarList(list github.StarList) {
+	for i := range m.starLists {
+		if m.starLists[i].ID == list.ID {
+			m.starLists[i] = list
+		}
+	}
+}
+
+// finishGitHubAction runs the command of a star or watch action and applies its result, as Bubble Tea would
+func finishGitHubAction(t *testing.T, app *App, cmd tea.Cmd) {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("Expected a command running the GitHub action, got nil")
+	}
+	msg, ok := cmd().(githubActionMsg)
+	if !ok {
+		t.Fatalf("Expected a GitHub action result, got %T", msg)
+	}
+	app.Update(msg)
+}
+
 func TestAppView(t *testing.T) {
 	// Create test packages
 	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
@@ -79,11 +217,304 @@ func TestAppView(t *testing.T) {
 		t.Errorf("Expected list view to be non-empty")
 	}
 
-	// Test dialog view
-	app.state = StateDialog
-	app.dialog = NewDialog("Test Title", "Test Message")
+	// Test dialog view, shown over the list
+	app.openDialog(NewDialog("Test Title", "Test Message"), DefaultDialogKeyMap())
 	dialogView := app.View()
-	if dialogView == "" {
-		t.Errorf("Expected dialog view to be non-empty")
+	if !strings.Contains(dialogView, "Test Title") || !strings.Contains(dialogView, "open GitHub") {
+		t.Errorf("Expected the dialog over the list, got:\n%s", dialogView)
+	}
+}
+
+func TestAppReleaseNotes(t *testing.T) {
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	pkg.LatestVersion = "v0.21.0"
+	packages := []*model.Package{pkg}
+
+	mockClient := NewMockGitHubClient()
+	mockClient.releases = []github.Release{{TagName: "v0.21.0", Body: "New features"}}
+
+	app := NewApp(packages, mockClient)
+
+	// Open the release notes pane
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if app.state != StateReleases {
+		t.Fatalf("Expected state to be StateReleases, got %v", app.state)
+	}
+	if cmd == nil {
+		t.Fatal("Expected a command fetching the releases")
+	}
+
+	// Deliver the fetched releases
+	app.Update(cmd())
+	if len(app.releaseNotes.releases) != 1 {
+		t.Errorf("Expected 1 release, got %d", len(app.releaseNotes.releases))
+	}
+
+	// Close the release notes pane without quitting
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if app.state != StateList {
+		t.Errorf("Expected state to be StateList, got %v", app.state)
+	}
+	if cmd != nil {
+		t.Errorf("Expected no command when closing the release notes, got %v", cmd)
+	}
+}
+
+func TestAppOfflineDisablesStarActions(t *testing.T) {
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	packages := []*model.Package{pkg}
+
+	mockClient := NewMockGitHubClient()
+	app := NewAppWithOptions(packages, mockClient, Options{Offline: true})
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+
+	if mockClient.starCallCount != 0 || mockClient.bulkStarCallCount != 0 {
+		t.Errorf("Expected no star calls offline, got %d star and %d bulk star calls", mockClient.starCallCount, mockClient.bulkStarCallCount)
+	}
+	if app.state != StateList {
+		t.Errorf("Expected state to be StateList, got %v", app.state)
+	}
+}
+
+func TestAppSponsors(t *testing.T) {
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	pkg.Repo = &model.RepoMetadata{Owner: "charmbracelet", OwnerSponsorable: true}
+	app := NewApp([]*model.Package{pkg}, NewMockGitHubClient())
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("$")})
+	if app.state != StateSponsors {
+		t.Fatalf("Expected state to be StateSponsors, got %v", app.state)
+	}
+	if group := app.sponsors.SelectedGroup(); group == nil || len(group.Packages) != 1 {
+		t.Errorf("Expected a sponsor group for the package, got %+v", group)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.state != StateList {
+		t.Errorf("Expected state to be StateList, got %v", app.state)
+	}
+}
+
+func TestAppStarLists(t *testing.T) {
+	pkg := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	pkgV2 := model.NewPackage("github.com/charmbracelet/bubbles/v2", "v2.0.0")
+	mockClient := NewMockGitHubClient()
+	mockClient.starLists = []github.StarList{{ID: "list-tui", Name: "TUI"}}
+	app := NewApp([]*model.Package{pkg, pkgV2}, mockClient)
+	app.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+
+	// openPicker opens the star list picker once the lists have been fetched
+	openPicker := func() {
+		t.Helper()
+		_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")})
+		if cmd == nil || app.modal == nil || !strings.Contains(app.View(), "Loading star lists...") {
+			t.Fatal("Expected the star lists to be loading")
+		}
+		app.Update(cmd())
+		if !strings.Contains(app.View(), "TUI (") {
+			t.Fatalf("Expected the picker to list the star lists, got:\n%s", app.View())
+		}
+	}
+
+	// Check the list, which stars every module of the repository once the result arrives
+	openPicker()
+	app.Update(tea.KeyMsg{Type: tea.KeyTab})
+	app.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = app.Update(cmd())
+	if cmd == nil {
+		t.Fatal("Expected a command adding the package to the list")
+	}
+	msg := cmd()
+	if pkg.IsStarred {
+		t.Error("Expected the package not to change before the result arrives")
+	}
+	app.Update(msg)
+	if list := github.FindStarList(mockClient.starLists, "TUI"); !list.Contains(pkg) {
+		t.Errorf("Expected the list to contain the package, got %+v", list)
+	}
+	if !pkg.IsStarred || !pkgV2.IsStarred {
+		t.Errorf("Expected both modules of the repository to be starred, got %v and %v", pkg.IsStarred, pkgV2.IsStarred)
+	}
+
+	// Create a new list; typing q must not close the picker
+	openPicker()
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if app.modal == nil {
+		t.Fatal("Expected typing a list name to keep the picker open")
+	}
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = app.Update(cmd())
+	finishGitHubAction(t, app, cmd)
+	if list := github.FindStarList(mockClient.starLists, "q"); list == nil || !list.Contains(pkg) {
+		t.Errorf("Expected the new list to contain the package, got %+v", list)
+	}
+	if list := github.FindStarList(mockClient.starLists, "TUI"); !list.Contains(pkg) {
+		t.Errorf("Expected the package to stay in the checked list, got %+v", list)
+	}
+
+	// Uncheck the first list
+	openPicker()
+	app.Update(tea.KeyMsg{Type: tea.KeyTab})
+	app.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = app.Update(cmd())
+	finishGitHubAction(t, app, cmd)
+	if list := github.FindStarList(mockClient.starLists, "TUI"); list.Contains(pkg) {
+		t.Errorf("Expected the package to be removed from the list, got %+v", list)
+	}
+
+	// Lists that arrive after the loading dialog was cancelled aren't shown
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")})
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	app.Update(cmd())
+	if app.modal != nil {
+		t.Error("Expected the picker not to open once cancelled")
+	}
+}
+
+func TestAppWatch(t *testing.T) {
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	lipgloss := model.NewPackage("github.com/charmbracelet/lipgloss", "v1.0.0")
+	mockClient := NewMockGitHubClient()
+	app := NewApp([]*model.Package{bubbles, lipgloss}, mockClient)
+
+	// GitHub is called in the background, and the package only changes once the result arrives
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	if bubbles.IsWatched {
+		t.Error("Expected the package not to change before the result arrives")
+	}
+	finishGitHubAction(t, app, cmd)
+	if !bubbles.IsWatched {
+		t.Error("Expected the selected package to be watched")
+	}
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	finishGitHubAction(t, app, cmd)
+	if bubbles.IsWatched {
+		t.Error("Expected the selected package to be unwatched")
+	}
+
+	// Watching all asks for confirmation first
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("W")})
+	if app.modal == nil || app.state != StateList {
+		t.Fatalf("Expected a dialog over the list, got state %v", app.state)
+	}
+	if len(mockClient.watchedRepos) != 0 {
+		t.Error("Expected no repositories to be watched before confirming")
+	}
+
+	// Keys go to the dialog, not the list
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	if !strings.Contains(app.View(), "Watch all unwatched") {
+		t.Error("Expected the dialog to stay open")
+	}
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.modal != nil {
+		t.Error("Expected the dialog to close")
+	}
+	if cmd == nil {
+		t.Fatal("Expected a command sending the answer")
+	}
+	_, cmd = app.Update(cmd())
+	finishGitHubAction(t, app, cmd)
+	if !bubbles.IsWatched || !lipgloss.IsWatched {
+		t.Error("Expected all repositories to be watched")
+	}
+}
+
+func TestAppWatchRepository(t *testing.T) {
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	bubblesV2 := model.NewPackage("github.com/charmbracelet/bubbles/v2", "v2.0.0")
+	lipgloss := model.NewPackage("github.com/charmbracelet/lipgloss", "v1.0.0")
+	lipgloss.IsIgnored = true
+	mockClient := NewMockGitHubClient()
+	app := NewApp([]*model.Package{bubbles, bubblesV2, lipgloss}, mockClient)
+
+	// Every module in the repository shows the new status
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	finishGitHubAction(t, app, cmd)
+	if !bubbles.IsWatched || !bubblesV2.IsWatched {
+		t.Errorf("Expected both modules of the repository to be watched, got %v and %v", bubbles.IsWatched, bubblesV2.IsWatched)
+	}
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	finishGitHubAction(t, app, cmd)
+	if bubbles.IsWatched || bubblesV2.IsWatched {
+		t.Errorf("Expected both modules of the repository to be unwatched, got %v and %v", bubbles.IsWatched, bubblesV2.IsWatched)
+	}
+
+	// Watching all leaves ignored repositories as they are
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("W")})
+	if !strings.Contains(app.View(), "1 ignored") {
+		t.Errorf("Expected the dialog to mention the ignored repository, got:\n%s", app.View())
+	}
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = app.Update(cmd())
+	finishGitHubAction(t, app, cmd)
+	if lipgloss.IsWatched || !lipgloss.IsIgnored || mockClient.watchedRepos[lipgloss.Path] {
+		t.Error("Expected the ignored repository not to be watched")
+	}
+}
+
+func TestAppBulkActions(t *testing.T) {
+	bubbles := model.NewPackage("github.com/charmbracelet/bubbles", "v0.20.0")
+	mod := model.NewPackage("golang.org/x/mod", "v0.27.0")
+	lipgloss := model.NewPackage("github.com/charmbracelet/lipgloss", "v1.0.0")
+	mockClient := NewMockGitHubClient()
+	app := NewApp([]*model.Package{bubbles, mod, lipgloss}, mockClient)
+
+	var copied string
+	copyToClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+	defer func() { copyToClipboard = clipboard.WriteAll }()
+
+	// Select the first and the last package
+	app.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	app.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if got := app.list.Selection(); len(got) != 2 || got[0] != bubbles || got[1] != lipgloss {
+		t.Fatalf("Selection() = %v, want bubbles and lipgloss", got)
+	}
+
+	// Star the selection; the packages only change once GitHub answers
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if app.modal == nil {
+		t.Fatal("Expected the action menu to be open")
+	}
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = app.Update(cmd())
+	if app.modal != nil || bubbles.IsStarred {
+		t.Error("Expected the menu to close with the star running")
+	}
+	finishGitHubAction(t, app, cmd)
+	if !bubbles.IsStarred || !lipgloss.IsStarred {
+		t.Error("Expected the selected packages to be starred")
+	}
+	if view := app.statusBar.View(); !strings.Contains(view, "Starred 2 packages") {
+		t.Errorf("Expected the result to be shown, got %q", view)
+	}
+
+	// Copy the module paths of the selection
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	for i := 0; i < 3; i++ {
+		app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	app.Update(cmd())
+	if copied != "github.com/charmbracelet/bubbles\ngithub.com/charmbracelet/lipgloss" {
+		t.Errorf("Copied %q, want the selected module paths", copied)
+	}
+	if view := app.statusBar.View(); !strings.Contains(view, "Copied 2 module paths") {
+		t.Errorf("Expected the result to be shown, got %q", view)
+	}
+
+	// The selection is kept until it's cleared
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if got := app.list.Selection(); len(got) != 0 {
+		t.Errorf("Expected escape to clear the selection, got %v", got)
 	}
 }